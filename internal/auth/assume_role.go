@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// maxSessionNameLength is the maximum length of an STS role session name
+const maxSessionNameLength = 64
+
+// invalidSessionNameChars matches characters not allowed in a role session name
+var invalidSessionNameChars = regexp.MustCompile(`[^\w+=,.@-]`)
+
+// AssumeRoleOptions contains the parameters for assuming a role from source credentials
+type AssumeRoleOptions struct {
+	RoleARN     string
+	ExternalID  string
+	SessionName string
+	MFASerial   string
+	TokenCode   string
+	Region      string
+	Duration    time.Duration
+}
+
+// AssumeRole assumes a role with STS using the given source credentials
+func (m *DefaultManager) AssumeRole(ctx context.Context, source *RoleCredentials, opts AssumeRoleOptions) (*RoleCredentials, error) {
+	if source == nil {
+		return nil, fmt.Errorf("source credentials are required to assume role %s", opts.RoleARN)
+	}
+
+	region := opts.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	cfg := aws.Config{
+		Region:      region,
+		Credentials: source.CredentialsProvider(),
+	}
+
+	sessionName := SanitizeSessionName(opts.SessionName)
+	if sessionName == "" {
+		sessionName = "synacklab"
+	}
+
+	input := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(opts.RoleARN),
+		RoleSessionName: aws.String(sessionName),
+	}
+	if opts.ExternalID != "" {
+		input.ExternalId = aws.String(opts.ExternalID)
+	}
+	if opts.MFASerial != "" {
+		input.SerialNumber = aws.String(opts.MFASerial)
+		input.TokenCode = aws.String(opts.TokenCode)
+	}
+	if opts.Duration > 0 {
+		input.DurationSeconds = aws.Int32(int32(opts.Duration.Seconds()))
+	}
+
+	resp, err := sts.NewFromConfig(cfg).AssumeRole(ctx, input)
+	if err != nil {
+		return nil, ClassifyError(fmt.Errorf("failed to assume role %s: %w", opts.RoleARN, err))
+	}
+
+	if resp.Credentials == nil || resp.Credentials.AccessKeyId == nil {
+		return nil, ClassifyError(fmt.Errorf("invalid assume role response from AWS"))
+	}
+
+	return &RoleCredentials{
+		AccessKeyID:     aws.ToString(resp.Credentials.AccessKeyId),
+		SecretAccessKey: aws.ToString(resp.Credentials.SecretAccessKey),
+		SessionToken:    aws.ToString(resp.Credentials.SessionToken),
+		Expiration:      aws.ToTime(resp.Credentials.Expiration),
+	}, nil
+}
+
+// RenderSessionName expands ${name} placeholders in a session name template
+func RenderSessionName(template string, vars map[string]string) string {
+	rendered := template
+	for name, value := range vars {
+		rendered = strings.ReplaceAll(rendered, "${"+name+"}", value)
+	}
+	return SanitizeSessionName(rendered)
+}
+
+// SanitizeSessionName makes a string safe to use as an STS role session name
+func SanitizeSessionName(name string) string {
+	sanitized := invalidSessionNameChars.ReplaceAllString(name, "-")
+	if len(sanitized) > maxSessionNameLength {
+		sanitized = sanitized[:maxSessionNameLength]
+	}
+	return sanitized
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+)
+
+// RoleCredentials represents temporary AWS credentials for an SSO role
+type RoleCredentials struct {
+	AccessKeyID     string    `json:"access_key_id"`
+	SecretAccessKey string    `json:"secret_access_key"`
+	SessionToken    string    `json:"session_token"`
+	Expiration      time.Time `json:"expiration"`
+}
+
+// CredentialsProvider returns an AWS SDK credentials provider that serves the
+// temporary credentials as-is
+func (c *RoleCredentials) CredentialsProvider() aws.CredentialsProvider {
+	return aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{
+			AccessKeyID:     c.AccessKeyID,
+			SecretAccessKey: c.SecretAccessKey,
+			SessionToken:    c.SessionToken,
+			CanExpire:       true,
+			Expires:         c.Expiration,
+		}, nil
+	})
+}
+
+// GetRoleCredentials obtains temporary credentials for an account and role
+// using the stored AWS SSO session
+func (m *DefaultManager) GetRoleCredentials(ctx context.Context, accountID, roleName string) (*RoleCredentials, error) {
+	if accountID == "" || roleName == "" {
+		return nil, &Error{
+			Type:    ErrorTypeInvalidConfig,
+			Message: "Account ID and role name are required to obtain role credentials",
+			TroubleshootingSteps: []string{
+				"Ensure the profile has sso_account_id and sso_role_name set in ~/.aws/config",
+				"Run 'synacklab auth sync' to regenerate SSO profiles",
+			},
+		}
+	}
+
+	session, err := m.GetStoredCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh the access token before it expires, if possible
+	session, err = m.refreshIfNeeded(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize AWS config
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, ClassifyError(fmt.Errorf("failed to load AWS config: %w", err))
+	}
+
+	// Create SSO client
+	ssoClient := sso.NewFromConfig(cfg, func(o *sso.Options) {
+		o.Region = session.Region
+	})
+
+	resp, err := ssoClient.GetRoleCredentials(ctx, &sso.GetRoleCredentialsInput{
+		AccessToken: aws.String(session.AccessToken),
+		AccountId:   aws.String(accountID),
+		RoleName:    aws.String(roleName),
+	})
+	if err != nil {
+		return nil, ClassifyError(fmt.Errorf("failed to get role credentials for %s/%s: %w", accountID, roleName, err))
+	}
+
+	if resp.RoleCredentials == nil || resp.RoleCredentials.AccessKeyId == nil {
+		return nil, ClassifyError(fmt.Errorf("invalid role credentials response from AWS"))
+	}
+
+	return &RoleCredentials{
+		AccessKeyID:     aws.ToString(resp.RoleCredentials.AccessKeyId),
+		SecretAccessKey: aws.ToString(resp.RoleCredentials.SecretAccessKey),
+		SessionToken:    aws.ToString(resp.RoleCredentials.SessionToken),
+		Expiration:      time.UnixMilli(resp.RoleCredentials.Expiration),
+	}, nil
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetRoleCredentials_MissingParameters(t *testing.T) {
+	manager := &DefaultManager{
+		credentialsPath: filepath.Join(t.TempDir(), "credentials.json"),
+		browserOpener:   &MockBrowserOpener{},
+	}
+
+	_, err := manager.GetRoleCredentials(context.Background(), "", "Admin")
+	var authErr *Error
+	if !errors.As(err, &authErr) || authErr.Type != ErrorTypeInvalidConfig {
+		t.Errorf("Expected invalid config error, got %v", err)
+	}
+}
+
+func TestGetRoleCredentials_NoSession(t *testing.T) {
+	manager := &DefaultManager{
+		credentialsPath: filepath.Join(t.TempDir(), "credentials.json"),
+		browserOpener:   &MockBrowserOpener{},
+	}
+
+	if _, err := manager.GetRoleCredentials(context.Background(), "123456789012", "Admin"); err == nil {
+		t.Error("Expected error when no session is stored")
+	}
+}
+
+func TestGetRoleCredentials_ExpiredSession(t *testing.T) {
+	manager := &DefaultManager{
+		credentialsPath: filepath.Join(t.TempDir(), "credentials.json"),
+		browserOpener:   &MockBrowserOpener{},
+	}
+
+	err := manager.storeCredentials(&SSOSession{
+		AccessToken: "expired-token",
+		StartURL:    "https://test.awsapps.com/start",
+		Region:      "us-east-1",
+		ExpiresAt:   time.Now().Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store credentials: %v", err)
+	}
+
+	_, err = manager.GetRoleCredentials(context.Background(), "123456789012", "Admin")
+	var authErr *Error
+	if !errors.As(err, &authErr) || authErr.Type != ErrorTypeSessionExpired {
+		t.Errorf("Expected session expired error, got %v", err)
+	}
+}
+
+func TestRenderSessionName(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		vars     map[string]string
+		expected string
+	}{
+		{
+			name:     "placeholders expanded",
+			template: "${user}-${profile}",
+			vars:     map[string]string{"user": "jane", "profile": "prod-deploy"},
+			expected: "jane-prod-deploy",
+		},
+		{
+			name:     "invalid characters replaced",
+			template: "${user} deploy/${profile}",
+			vars:     map[string]string{"user": "jane doe", "profile": "prod"},
+			expected: "jane-doe-deploy-prod",
+		},
+		{
+			name:     "truncated to 64 characters",
+			template: strings.Repeat("a", 80),
+			vars:     nil,
+			expected: strings.Repeat("a", 64),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := RenderSessionName(tt.template, tt.vars); actual != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, actual)
+			}
+		})
+	}
+}
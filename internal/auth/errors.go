@@ -264,7 +264,7 @@ func classifyAWSError(err error) *Error {
 	errorMessage := apiErr.ErrorMessage()
 
 	switch errorCode {
-	case "UnauthorizedException", "InvalidTokenException":
+	case "UnauthorizedException", "InvalidTokenException", "InvalidGrantException":
 		return &Error{
 			Type:          ErrorTypeSessionExpired,
 			Message:       "AWS SSO session has expired or is invalid",
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -40,12 +41,54 @@ type SSOSession struct {
 	StartURL    string    `json:"start_url"`
 	Region      string    `json:"region"`
 	ExpiresAt   time.Time `json:"expires_at"`
+
+	// OIDC client registration and refresh token used to renew the access token
+	RefreshToken          string    `json:"refresh_token,omitempty"`
+	ClientID              string    `json:"client_id,omitempty"`
+	ClientSecret          string    `json:"client_secret,omitempty"`
+	ClientSecretExpiresAt time.Time `json:"client_secret_expires_at"`
+}
+
+// tokenRefreshWindow is how long before expiry the access token is refreshed
+const tokenRefreshWindow = 15 * time.Minute
+
+// ssoAccessScope is the OIDC scope that allows refresh tokens for AWS SSO
+const ssoAccessScope = "sso:account:access"
+
+// NeedsRefresh reports whether the access token expires within the refresh window
+func (s *SSOSession) NeedsRefresh(now time.Time) bool {
+	return now.After(s.ExpiresAt.Add(-tokenRefreshWindow))
+}
+
+// CanRefresh reports whether the session holds a usable refresh token and client registration
+func (s *SSOSession) CanRefresh(now time.Time) bool {
+	if s.RefreshToken == "" || s.ClientID == "" || s.ClientSecret == "" {
+		return false
+	}
+	return s.ClientSecretExpiresAt.IsZero() || now.Before(s.ClientSecretExpiresAt)
 }
 
 // DefaultManager implements the Manager interface
 type DefaultManager struct {
 	credentialsPath string
 	browserOpener   BrowserOpener
+
+	// out receives the sign-in instructions, stdout when unset
+	out io.Writer
+}
+
+// SetOutput directs the sign-in instructions of Authenticate to w, so that
+// commands whose stdout is consumed by the shell can print them to stderr
+func (m *DefaultManager) SetOutput(w io.Writer) {
+	m.out = w
+}
+
+// output returns the writer for sign-in instructions
+func (m *DefaultManager) output() io.Writer {
+	if m.out == nil {
+		return os.Stdout
+	}
+	return m.out
 }
 
 // NewManager creates a new authentication manager instance
@@ -89,11 +132,17 @@ func (m *DefaultManager) IsAuthenticated(ctx context.Context) (bool, error) {
 		return false, nil // No stored credentials or benign error reading them
 	}
 
-	// Check if session has expired
-	if time.Now().After(session.ExpiresAt) {
-		// Clear expired credentials
-		_ = m.ClearCredentials()
-		return false, nil
+	// Refresh the access token before it expires, if possible
+	session, err = m.refreshIfNeeded(ctx, session)
+	if err != nil {
+		authErr := ClassifyError(err)
+		// Only clear credentials once the session has ended; the refresh token
+		// is kept through network failures so that a later retry can succeed
+		if isSessionEnded(authErr) {
+			_ = m.ClearCredentials()
+			return false, nil
+		}
+		return false, authErr
 	}
 
 	// Validate session by making a test API call
@@ -126,7 +175,8 @@ func (m *DefaultManager) Authenticate(ctx context.Context, appConfig *config.Con
 		return nil, err
 	}
 
-	fmt.Printf("🔐 Authenticating with AWS SSO: %s\n", appConfig.AWS.SSO.StartURL)
+	out := m.output()
+	fmt.Fprintf(out, "🔐 Authenticating with AWS SSO: %s\n", appConfig.AWS.SSO.StartURL)
 
 	// Initialize AWS config
 	cfg, err := awsconfig.LoadDefaultConfig(ctx)
@@ -143,6 +193,7 @@ func (m *DefaultManager) Authenticate(ctx context.Context, appConfig *config.Con
 	registerResp, err := ssooidcClient.RegisterClient(ctx, &ssooidc.RegisterClientInput{
 		ClientName: aws.String("synacklab-cli"),
 		ClientType: aws.String("public"),
+		Scopes:     []string{ssoAccessScope},
 	})
 	if err != nil {
 		return nil, ClassifyError(fmt.Errorf("failed to register client: %w", err))
@@ -163,20 +214,20 @@ func (m *DefaultManager) Authenticate(ctx context.Context, appConfig *config.Con
 		return nil, ClassifyError(fmt.Errorf("invalid device authorization response from AWS"))
 	}
 
-	fmt.Printf("\n🌐 Opening browser for authorization: %s\n", *deviceAuthResp.VerificationUriComplete)
-	fmt.Printf("📋 Verification code: %s\n", *deviceAuthResp.UserCode)
+	fmt.Fprintf(out, "\n🌐 Opening browser for authorization: %s\n", *deviceAuthResp.VerificationUriComplete)
+	fmt.Fprintf(out, "📋 Verification code: %s\n", *deviceAuthResp.UserCode)
 
 	// Try to open browser automatically
 	browserOpened := false
 	if err := m.browserOpener.Open(*deviceAuthResp.VerificationUriComplete); err != nil {
-		fmt.Printf("⚠️  Failed to open browser automatically: %v\n", err)
-		fmt.Printf("🌐 Please manually visit: %s\n", *deviceAuthResp.VerificationUriComplete)
+		fmt.Fprintf(out, "⚠️  Failed to open browser automatically: %v\n", err)
+		fmt.Fprintf(out, "🌐 Please manually visit: %s\n", *deviceAuthResp.VerificationUriComplete)
 	} else {
-		fmt.Println("✅ Browser opened automatically")
+		fmt.Fprintln(out, "✅ Browser opened automatically")
 		browserOpened = true
 	}
 
-	fmt.Println("\n⏳ Waiting for authorization completion...")
+	fmt.Fprintln(out, "\n⏳ Waiting for authorization completion...")
 
 	// Poll for token with exponential backoff
 	var tokenResp *ssooidc.CreateTokenOutput
@@ -199,7 +250,7 @@ func (m *DefaultManager) Authenticate(ctx context.Context, appConfig *config.Con
 		})
 
 		if err == nil {
-			fmt.Println("✅ Authorization completed successfully!")
+			fmt.Fprintln(out, "✅ Authorization completed successfully!")
 			break
 		}
 
@@ -208,7 +259,7 @@ func (m *DefaultManager) Authenticate(ctx context.Context, appConfig *config.Con
 		if authErr != nil {
 			// For authorization pending, continue polling
 			if authErr.Type == ErrorTypeAuthorizationPending {
-				fmt.Print(".")
+				fmt.Fprint(out, ".")
 				time.Sleep(pollInterval)
 
 				// Increase poll interval up to maximum
@@ -223,7 +274,7 @@ func (m *DefaultManager) Authenticate(ctx context.Context, appConfig *config.Con
 
 			// For slow down errors, increase the poll interval
 			if authErr.Type == ErrorTypeSlowDown {
-				fmt.Print("⏳")
+				fmt.Fprint(out, "⏳")
 				pollInterval = pollInterval * 2
 				if pollInterval > maxPollInterval {
 					pollInterval = maxPollInterval
@@ -240,8 +291,8 @@ func (m *DefaultManager) Authenticate(ctx context.Context, appConfig *config.Con
 
 		// For unknown errors, provide fallback instructions
 		if !browserOpened {
-			fmt.Printf("\n⚠️  Polling failed: %v\n", err)
-			fmt.Printf("🌐 Please ensure you've completed authorization at: %s\n", *deviceAuthResp.VerificationUriComplete)
+			fmt.Fprintf(out, "\n⚠️  Polling failed: %v\n", err)
+			fmt.Fprintf(out, "🌐 Please ensure you've completed authorization at: %s\n", *deviceAuthResp.VerificationUriComplete)
 		}
 
 		time.Sleep(pollInterval)
@@ -258,10 +309,16 @@ func (m *DefaultManager) Authenticate(ctx context.Context, appConfig *config.Con
 	}
 
 	session := &SSOSession{
-		AccessToken: *tokenResp.AccessToken,
-		StartURL:    appConfig.AWS.SSO.StartURL,
-		Region:      appConfig.AWS.SSO.Region,
-		ExpiresAt:   expiresAt,
+		AccessToken:  *tokenResp.AccessToken,
+		StartURL:     appConfig.AWS.SSO.StartURL,
+		Region:       appConfig.AWS.SSO.Region,
+		ExpiresAt:    expiresAt,
+		RefreshToken: aws.ToString(tokenResp.RefreshToken),
+		ClientID:     aws.ToString(registerResp.ClientId),
+		ClientSecret: aws.ToString(registerResp.ClientSecret),
+	}
+	if registerResp.ClientSecretExpiresAt != 0 {
+		session.ClientSecretExpiresAt = time.Unix(registerResp.ClientSecretExpiresAt, 0)
 	}
 
 	// Store credentials
@@ -269,7 +326,7 @@ func (m *DefaultManager) Authenticate(ctx context.Context, appConfig *config.Con
 		return nil, ClassifyError(fmt.Errorf("failed to store credentials: %w", err))
 	}
 
-	fmt.Println("✅ Successfully authenticated with AWS SSO")
+	fmt.Fprintln(out, "✅ Successfully authenticated with AWS SSO")
 	return session, nil
 }
 
@@ -339,6 +396,103 @@ func (m *DefaultManager) ValidateSession(ctx context.Context, session *SSOSessio
 	return nil
 }
 
+// RefreshSession renews the access token using the stored OIDC refresh token
+func (m *DefaultManager) RefreshSession(ctx context.Context, session *SSOSession) (*SSOSession, error) {
+	if !session.CanRefresh(time.Now()) {
+		return nil, &Error{
+			Type:    ErrorTypeSessionExpired,
+			Message: "AWS SSO session cannot be refreshed",
+			TroubleshootingSteps: []string{
+				"Run 'synacklab auth aws-login' to re-authenticate",
+			},
+		}
+	}
+
+	// Initialize AWS config
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, ClassifyError(fmt.Errorf("failed to load AWS config: %w", err))
+	}
+
+	// Create SSO OIDC client
+	ssooidcClient := ssooidc.NewFromConfig(cfg, func(o *ssooidc.Options) {
+		o.Region = session.Region
+	})
+
+	tokenResp, err := ssooidcClient.CreateToken(ctx, &ssooidc.CreateTokenInput{
+		ClientId:     aws.String(session.ClientID),
+		ClientSecret: aws.String(session.ClientSecret),
+		RefreshToken: aws.String(session.RefreshToken),
+		GrantType:    aws.String("refresh_token"),
+	})
+	if err != nil {
+		return nil, ClassifyError(fmt.Errorf("failed to refresh SSO token: %w", err))
+	}
+
+	if tokenResp.AccessToken == nil {
+		return nil, ClassifyError(fmt.Errorf("invalid token refresh response from AWS"))
+	}
+
+	refreshed := *session
+	refreshed.AccessToken = *tokenResp.AccessToken
+	refreshed.ExpiresAt = time.Now().Add(8 * time.Hour)
+	if tokenResp.ExpiresIn != 0 {
+		refreshed.ExpiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	}
+	// The refresh token may be rotated
+	if tokenResp.RefreshToken != nil {
+		refreshed.RefreshToken = *tokenResp.RefreshToken
+	}
+
+	if err := m.storeCredentials(&refreshed); err != nil {
+		return nil, ClassifyError(fmt.Errorf("failed to store credentials: %w", err))
+	}
+
+	return &refreshed, nil
+}
+
+// refreshIfNeeded refreshes a session close to expiry and reports an error if it is no longer usable
+func (m *DefaultManager) refreshIfNeeded(ctx context.Context, session *SSOSession) (*SSOSession, error) {
+	now := time.Now()
+	if !session.NeedsRefresh(now) {
+		return session, nil
+	}
+
+	if session.CanRefresh(now) {
+		refreshed, err := m.RefreshSession(ctx, session)
+		if err == nil {
+			return refreshed, nil
+		}
+		// Keep using the current token while it is still valid
+		if now.Before(session.ExpiresAt) {
+			return session, nil
+		}
+		return nil, err
+	}
+
+	if now.After(session.ExpiresAt) {
+		return nil, &Error{
+			Type:    ErrorTypeSessionExpired,
+			Message: "AWS SSO session has expired",
+			TroubleshootingSteps: []string{
+				"Run 'synacklab auth aws-login' to re-authenticate",
+			},
+		}
+	}
+
+	return session, nil
+}
+
+// isSessionEnded reports whether err means the session or its refresh token
+// is no longer valid, as opposed to a failure to reach AWS
+func isSessionEnded(err *Error) bool {
+	switch err.Type {
+	case ErrorTypeSessionExpired, ErrorTypeInvalidCredentials, ErrorTypeExpiredToken, ErrorTypeDeviceCodeExpired:
+		return true
+	}
+	return false
+}
+
 // storeCredentials saves authentication credentials to disk
 func (m *DefaultManager) storeCredentials(session *SSOSession) error {
 	// Create credentials directory if it doesn't exist
@@ -1213,3 +1213,173 @@ func TestAuthenticate_BrowserOpeningFailure(t *testing.T) {
 	// This test would need extensive AWS SDK mocking to work properly in CI
 	t.Skip("Skipping authentication test that requires AWS SDK mocking")
 }
+
+func TestSSOSession_NeedsRefresh(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		expected  bool
+	}{
+		{name: "fresh session", expiresAt: now.Add(8 * time.Hour), expected: false},
+		{name: "inside refresh window", expiresAt: now.Add(5 * time.Minute), expected: true},
+		{name: "expired session", expiresAt: now.Add(-time.Hour), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &SSOSession{ExpiresAt: tt.expiresAt}
+			if actual := session.NeedsRefresh(now); actual != tt.expected {
+				t.Errorf("NeedsRefresh() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSSOSession_CanRefresh(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		session  SSOSession
+		expected bool
+	}{
+		{
+			name: "refresh token and registration",
+			session: SSOSession{
+				RefreshToken:          "refresh",
+				ClientID:              "client",
+				ClientSecret:          "secret",
+				ClientSecretExpiresAt: now.Add(24 * time.Hour),
+			},
+			expected: true,
+		},
+		{
+			name:     "no refresh token",
+			session:  SSOSession{ClientID: "client", ClientSecret: "secret"},
+			expected: false,
+		},
+		{
+			name: "expired client registration",
+			session: SSOSession{
+				RefreshToken:          "refresh",
+				ClientID:              "client",
+				ClientSecret:          "secret",
+				ClientSecretExpiresAt: now.Add(-time.Hour),
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.session.CanRefresh(now); actual != tt.expected {
+				t.Errorf("CanRefresh() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRefreshIfNeeded_ExpiredWithoutRefreshToken(t *testing.T) {
+	manager := &DefaultManager{
+		credentialsPath: filepath.Join(t.TempDir(), "credentials.json"),
+		browserOpener:   &MockBrowserOpener{},
+	}
+
+	session := &SSOSession{
+		AccessToken: "expired-token",
+		Region:      "us-east-1",
+		ExpiresAt:   time.Now().Add(-time.Hour),
+	}
+
+	_, err := manager.refreshIfNeeded(context.Background(), session)
+	var authErr *Error
+	if !errors.As(err, &authErr) || authErr.Type != ErrorTypeSessionExpired {
+		t.Errorf("Expected session expired error, got %v", err)
+	}
+}
+
+func TestRefreshIfNeeded_FreshSession(t *testing.T) {
+	manager := &DefaultManager{
+		credentialsPath: filepath.Join(t.TempDir(), "credentials.json"),
+		browserOpener:   &MockBrowserOpener{},
+	}
+
+	session := &SSOSession{
+		AccessToken: "valid-token",
+		Region:      "us-east-1",
+		ExpiresAt:   time.Now().Add(8 * time.Hour),
+	}
+
+	refreshed, err := manager.refreshIfNeeded(context.Background(), session)
+	if err != nil {
+		t.Fatalf("refreshIfNeeded failed: %v", err)
+	}
+	if refreshed.AccessToken != "valid-token" {
+		t.Error("Fresh session should be returned unchanged")
+	}
+}
+
+func TestAuthManager_IsAuthenticated_RefreshFailureKeepsCredentials(t *testing.T) {
+	manager := &DefaultManager{
+		credentialsPath: filepath.Join(t.TempDir(), "credentials.json"),
+		browserOpener:   &MockBrowserOpener{},
+	}
+
+	session := &SSOSession{
+		AccessToken:  "expired-token",
+		StartURL:     "https://example.awsapps.com/start",
+		Region:       "us-east-1",
+		ExpiresAt:    time.Now().Add(-time.Hour),
+		RefreshToken: "refresh",
+		ClientID:     "client",
+		ClientSecret: "secret",
+	}
+	if err := manager.storeCredentials(session); err != nil {
+		t.Fatalf("Failed to store credentials: %v", err)
+	}
+
+	// A cancelled context makes the refresh fail without reaching AWS
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	isAuth, err := manager.IsAuthenticated(ctx)
+	if err == nil {
+		t.Fatal("Expected the refresh failure to be returned")
+	}
+	if isAuth {
+		t.Error("Expected authentication to fail when the refresh fails")
+	}
+	if _, err := os.Stat(manager.credentialsPath); err != nil {
+		t.Errorf("Expected credentials to be kept after a failed refresh: %v", err)
+	}
+}
+
+func TestIsSessionEnded(t *testing.T) {
+	tests := []struct {
+		errorType ErrorType
+		expected  bool
+	}{
+		{ErrorTypeSessionExpired, true},
+		{ErrorTypeInvalidCredentials, true},
+		{ErrorTypeDeviceCodeExpired, true},
+		{ErrorTypeNetworkConnectivity, false},
+		{ErrorTypeNetworkTimeout, false},
+		{ErrorTypeDNSResolution, false},
+		{ErrorTypeServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.errorType), func(t *testing.T) {
+			if actual := isSessionEnded(&Error{Type: tt.errorType}); actual != tt.expected {
+				t.Errorf("isSessionEnded(%s) = %v, want %v", tt.errorType, actual, tt.expected)
+			}
+		})
+	}
+
+	invalidGrant := &smithy.GenericAPIError{Code: "InvalidGrantException", Message: "Invalid grant provided"}
+	if authErr := ClassifyError(invalidGrant); !isSessionEnded(authErr) {
+		t.Errorf("InvalidGrantException classified as %s, want an ended session", authErr.Type)
+	}
+}
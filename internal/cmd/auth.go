@@ -16,6 +16,8 @@ func init() {
 	authCmd.AddCommand(awsLoginCmd)
 	authCmd.AddCommand(awsCtxCmd)
 	authCmd.AddCommand(awsSyncCmd)
+	authCmd.AddCommand(awsCredsCmd)
+	authCmd.AddCommand(awsStatusCmd)
 	authCmd.AddCommand(eksConfigCmd)
 	authCmd.AddCommand(eksCtxCmd)
 }
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"synacklab/internal/auth"
 	"synacklab/pkg/config"
 	"synacklab/pkg/fuzzy"
+	"synacklab/pkg/state"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/ini.v1"
@@ -18,6 +21,12 @@ var (
 	configFile  string
 	interactive bool
 	noAuth      bool
+	awsCtxShell bool
+	awsCtxPin   string
+	awsCtxUnpin string
+	awsCtxQuery string
+	awsCtxFirst bool
+	awsCtxPrint bool
 )
 
 var awsCtxCmd = &cobra.Command{
@@ -28,9 +37,32 @@ This command allows you to select and set a default AWS profile from your existi
 If you are not authenticated, it will automatically prompt you to authenticate first unless --no-auth is specified.
 
 The command provides an interactive fuzzy finder interface for easy profile selection.
+Favourite profiles are listed first, followed by recently and frequently used ones.
+Pass a profile name to switch directly, or '-' to switch back to the previous profile.
+
+With --shell, the default profile is left untouched and an export statement for
+AWS_PROFILE is printed instead, so the selection only applies to the current shell:
+
+  eval "$(synacklab auth aws-ctx --shell)"
+
+Run 'synacklab shell-init <bash|zsh|fish>' to install a wrapper that does this automatically.
+
+For scripts, --query selects a profile with the fuzzy matcher instead of the
+picker. It fails with exit code 3 when nothing matches and exit code 4 when
+several profiles match, unless --first is given to take the best match. --print
+writes the selected profile to stdout without switching (and without signing in):
+
+  synacklab auth aws-ctx --query 'prod admin' --print
 
 Flags:
-  --no-auth    Skip automatic authentication and allow profile switching without AWS SSO authentication`,
+  --no-auth    Skip automatic authentication and allow profile switching without AWS SSO authentication
+  --shell      Print an export statement for the current shell instead of changing the default profile
+  --pin        Add a profile to the favourites shown at the top of the picker
+  --unpin      Remove a profile from the favourites
+  --query      Select the profile matching a fuzzy query without the picker
+  --first      With --query, take the best match when several profiles match
+  --print      Print the selected profile instead of switching to it`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runAWSCtx,
 }
 
@@ -38,18 +70,127 @@ func init() {
 	awsCtxCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to configuration file")
 	awsCtxCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Force interactive mode even with config file")
 	awsCtxCmd.Flags().BoolVar(&noAuth, "no-auth", false, "Skip automatic authentication and allow profile switching without AWS SSO authentication")
+	awsCtxCmd.Flags().BoolVar(&awsCtxShell, "shell", false, "Print 'export AWS_PROFILE=...' for eval instead of changing the default profile")
+	awsCtxCmd.Flags().StringVar(&awsCtxPin, "pin", "", "Pin a profile to the top of the picker")
+	awsCtxCmd.Flags().StringVar(&awsCtxUnpin, "unpin", "", "Remove a profile from the pinned favourites")
+	awsCtxCmd.Flags().StringVarP(&awsCtxQuery, "query", "q", "", "Select the profile matching a fuzzy query without the picker")
+	awsCtxCmd.Flags().BoolVar(&awsCtxFirst, "first", false, "With --query, take the best match instead of failing when several profiles match")
+	awsCtxCmd.Flags().BoolVar(&awsCtxPrint, "print", false, "Print the selected profile to stdout instead of switching to it")
 }
 
-func runAWSCtx(_ *cobra.Command, _ []string) error {
+func runAWSCtx(_ *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	fmt.Println("🔄 Switching AWS SSO context...")
+	// In shell and print mode stdout is consumed by the caller, so progress output goes to stderr
+	out := progressOutput(awsCtxShell || awsCtxPrint)
+
+	store := loadPickerState(out)
+	if awsCtxPin != "" {
+		return updateFavourite(out, store, state.PickerAWSProfile, awsCtxPin, true)
+	}
+	if awsCtxUnpin != "" {
+		return updateFavourite(out, store, state.PickerAWSProfile, awsCtxUnpin, false)
+	}
+
+	if err := validateQueryFlags(args, awsCtxQuery, awsCtxFirst); err != nil {
+		return err
+	}
+
+	// Printing the selection does not need credentials
+	if !awsCtxPrint {
+		fmt.Fprintln(out, "🔄 Switching AWS SSO context...")
+
+		if err := ensureAWSCtxAuthenticated(ctx, out); err != nil {
+			return err
+		}
+	}
+
+	// Check if AWS config exists
+	configPath, err := getAWSConfigPath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		fmt.Fprintln(out, "❌ No AWS config file found at ~/.aws/config")
+		fmt.Fprintln(out, "💡 Run 'synacklab auth sync' first to create profiles from AWS SSO")
+		return nil
+	}
+
+	// Load existing AWS config
+	cfg, err := ini.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	// Build options for fuzzy finder from all profile sections
+	options := buildAWSProfileOptions(parseAWSProfiles(cfg))
+
+	if len(options) == 0 {
+		fmt.Fprintln(out, "❌ No profiles found in AWS config")
+		fmt.Fprintln(out, "💡 Run 'synacklab auth sync' first to create profiles from AWS SSO")
+		return nil
+	}
+
+	var selectedProfile string
+	switch {
+	case len(args) == 1 && args[0] == "-":
+		selectedProfile, err = previousSelection(store, state.PickerAWSProfile, "AWS profile")
+	case len(args) == 1:
+		selectedProfile = args[0]
+	case awsCtxQuery != "":
+		options = rankOptions(store, state.PickerAWSProfile, options)
+		selectedProfile, err = fuzzy.Resolve(options, awsCtxQuery, awsCtxFirst)
+	default:
+		options = rankOptions(store, state.PickerAWSProfile, options)
+		selectedProfile, err = selectAWSProfile("🔍 Select AWS profile to set as default:", options)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := findAWSProfile(cfg, selectedProfile); err != nil {
+		return err
+	}
+
+	if awsCtxPrint {
+		fmt.Println(selectedProfile)
+		return nil
+	}
+
+	if awsCtxShell {
+		store.Observe(state.PickerAWSProfile, os.Getenv("AWS_PROFILE"))
+		recordSelection(out, store, state.PickerAWSProfile, selectedProfile)
+		fmt.Println(formatShellExport("AWS_PROFILE", selectedProfile))
+		fmt.Fprintf(out, "✅ Switched this shell to AWS profile '%s'\n", selectedProfile)
+		return nil
+	}
+
+	// Remember the profile being replaced so that '-' can switch back to it
+	if current := matchDefaultProfile(cfg); current != "default" {
+		store.Observe(state.PickerAWSProfile, current)
+	}
+
+	// Copy selected profile configuration to default section
+	err = setDefaultProfile(cfg, selectedProfile, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to set default profile: %w", err)
+	}
 
+	recordSelection(out, store, state.PickerAWSProfile, selectedProfile)
+	fmt.Fprintf(out, "✅ Successfully set '%s' as the default AWS profile\n", selectedProfile)
+	return nil
+}
+
+// ensureAWSCtxAuthenticated signs in to AWS SSO unless already authenticated or
+// --no-auth is set, writing progress and sign-in instructions to out
+func ensureAWSCtxAuthenticated(ctx context.Context, out io.Writer) error {
 	// Initialize authentication manager
 	authManager, err := auth.NewManager()
 	if err != nil {
 		return fmt.Errorf("failed to initialize authentication manager: %w", err)
 	}
+	authManager.SetOutput(out)
 
 	// Check authentication status
 	isAuthenticated, err := authManager.IsAuthenticated(ctx)
@@ -57,7 +198,7 @@ func runAWSCtx(_ *cobra.Command, _ []string) error {
 		// Handle authentication check errors with user-friendly messages
 		var authErr *auth.Error
 		if errors.As(err, &authErr) {
-			fmt.Printf("❌ %s%s\n", authErr.Message, authErr.GetTroubleshootingMessage())
+			fmt.Fprintf(out, "❌ %s%s\n", authErr.Message, authErr.GetTroubleshootingMessage())
 			return fmt.Errorf("authentication check failed")
 		}
 		return fmt.Errorf("failed to check authentication status: %w", err)
@@ -66,11 +207,11 @@ func runAWSCtx(_ *cobra.Command, _ []string) error {
 	// Handle authentication based on --no-auth flag
 	if !isAuthenticated {
 		if noAuth {
-			fmt.Println("⚠️  You are not authenticated to AWS SSO")
-			fmt.Println("🔄 Proceeding with profile switching without authentication (--no-auth flag specified)")
+			fmt.Fprintln(out, "⚠️  You are not authenticated to AWS SSO")
+			fmt.Fprintln(out, "🔄 Proceeding with profile switching without authentication (--no-auth flag specified)")
 		} else {
-			fmt.Println("🔐 You are not authenticated to AWS SSO")
-			fmt.Println("🚀 Starting automatic authentication...")
+			fmt.Fprintln(out, "🔐 You are not authenticated to AWS SSO")
+			fmt.Fprintln(out, "🚀 Starting automatic authentication...")
 
 			// Load configuration for authentication
 			appConfig, err := config.LoadConfig()
@@ -84,91 +225,149 @@ func runAWSCtx(_ *cobra.Command, _ []string) error {
 				// Handle structured authentication errors
 				var authErr *auth.Error
 				if errors.As(err, &authErr) {
-					fmt.Printf("❌ %s%s\n", authErr.Message, authErr.GetTroubleshootingMessage())
+					fmt.Fprintf(out, "❌ %s%s\n", authErr.Message, authErr.GetTroubleshootingMessage())
 					return fmt.Errorf("authentication failed")
 				}
 				return fmt.Errorf("authentication failed: %w", err)
 			}
 
-			fmt.Println("✅ Authentication successful!")
+			fmt.Fprintln(out, "✅ Authentication successful!")
 		}
 	}
 
-	// Check if AWS config exists
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+	return nil
+}
+
+func setDefaultProfile(cfg *ini.File, profileName, configPath string) error {
+	// Get the selected profile section
+	profileSectionName := fmt.Sprintf("profile %s", profileName)
+
+	// Check if section exists
+	if !cfg.HasSection(profileSectionName) {
+		return fmt.Errorf("profile section not found: %s", profileSectionName)
 	}
 
-	configPath := filepath.Join(homeDir, ".aws", "config")
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		fmt.Println("❌ No AWS config file found at ~/.aws/config")
-		fmt.Println("💡 Run 'synacklab auth sync' first to create profiles from AWS SSO")
-		return nil
+	profileSection := cfg.Section(profileSectionName)
+
+	// Create or get default section
+	var defaultSection *ini.Section
+	if cfg.HasSection("default") {
+		defaultSection = cfg.Section("default")
+	} else {
+		var err error
+		defaultSection, err = cfg.NewSection("default")
+		if err != nil {
+			return fmt.Errorf("failed to create default section: %w", err)
+		}
 	}
 
-	// Load existing AWS config
-	cfg, err := ini.Load(configPath)
-	if err != nil {
-		return fmt.Errorf("failed to load AWS config: %w", err)
+	// Clear the previous default first, so that keys of another kind of
+	// profile, such as role_arn or sso_account_id, are not left behind
+	for _, key := range defaultSection.KeyStrings() {
+		defaultSection.DeleteKey(key)
 	}
 
-	// Find all profile sections and build options for fuzzy finder
-	var options []fuzzy.Option
-	var maxProfileLen, maxAccountLen, maxRoleLen int
+	// Copy all keys from profile to default
+	for _, key := range profileSection.Keys() {
+		defaultSection.Key(key.Name()).SetValue(key.Value())
+	}
 
-	// First pass: collect all profiles and calculate max lengths for alignment
-	type profileInfo struct {
-		name      string
-		accountID string
-		roleName  string
-		region    string
-		startURL  string
+	// Save the configuration
+	return cfg.SaveTo(configPath)
+}
+
+// getAWSConfigPath returns the path of the shared AWS config file
+func getAWSConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
-	var profiles []profileInfo
+
+	return filepath.Join(homeDir, ".aws", "config"), nil
+}
+
+// awsProfileInfo holds the SSO settings of a profile in ~/.aws/config
+type awsProfileInfo struct {
+	name      string
+	accountID string
+	roleName  string
+	region    string
+	startURL  string
+
+	// Assume-role settings for chained profiles
+	sourceProfile   string
+	roleARN         string
+	externalID      string
+	sessionName     string
+	mfaSerial       string
+	durationSeconds int
+}
+
+// parseAWSProfiles collects all named profiles from an AWS config file
+func parseAWSProfiles(cfg *ini.File) []awsProfileInfo {
+	var profiles []awsProfileInfo
 
 	for _, section := range cfg.Sections() {
-		if section.Name() != "DEFAULT" && section.Name() != "default" {
-			// Remove "profile " prefix if present
-			profileName := section.Name()
-			if len(profileName) > 8 && profileName[:8] == "profile " {
-				profileName = profileName[8:]
-			}
+		if section.Name() == "DEFAULT" || section.Name() == "default" {
+			continue
+		}
 
-			// Get profile metadata
-			accountID := section.Key("sso_account_id").String()
-			roleName := section.Key("sso_role_name").String()
-			region := section.Key("region").String()
-			startURL := section.Key("sso_start_url").String()
-
-			profiles = append(profiles, profileInfo{
-				name:      profileName,
-				accountID: accountID,
-				roleName:  roleName,
-				region:    region,
-				startURL:  startURL,
-			})
-
-			// Track max lengths for alignment
-			if len(profileName) > maxProfileLen {
-				maxProfileLen = len(profileName)
-			}
-			if len(accountID) > maxAccountLen {
-				maxAccountLen = len(accountID)
-			}
-			if len(roleName) > maxRoleLen {
-				maxRoleLen = len(roleName)
-			}
+		// Remove "profile " prefix if present
+		profileName := strings.TrimPrefix(section.Name(), "profile ")
+
+		profiles = append(profiles, awsProfileInfo{
+			name:            profileName,
+			accountID:       section.Key("sso_account_id").String(),
+			roleName:        section.Key("sso_role_name").String(),
+			region:          section.Key("region").String(),
+			startURL:        section.Key("sso_start_url").String(),
+			sourceProfile:   section.Key("source_profile").String(),
+			roleARN:         section.Key("role_arn").String(),
+			externalID:      section.Key("external_id").String(),
+			sessionName:     section.Key("role_session_name").String(),
+			mfaSerial:       section.Key("mfa_serial").String(),
+			durationSeconds: section.Key("duration_seconds").MustInt(0),
+		})
+	}
+
+	return profiles
+}
+
+// findAWSProfile looks up a named profile in an AWS config file
+func findAWSProfile(cfg *ini.File, profileName string) (*awsProfileInfo, error) {
+	for _, profile := range parseAWSProfiles(cfg) {
+		if profile.name == profileName {
+			return &profile, nil
+		}
+	}
+	return nil, fmt.Errorf("profile '%s' not found in AWS config", profileName)
+}
+
+// buildAWSProfileOptions builds aligned fuzzy finder options for AWS profiles
+func buildAWSProfileOptions(profiles []awsProfileInfo) []fuzzy.Option {
+	var maxAccountLen, maxRoleLen int
+
+	// First pass: calculate max lengths for alignment
+	for _, profile := range profiles {
+		if len(profile.accountID) > maxAccountLen {
+			maxAccountLen = len(profile.accountID)
+		}
+		if len(profile.roleName) > maxRoleLen {
+			maxRoleLen = len(profile.roleName)
 		}
 	}
 
 	// Second pass: build formatted options with proper alignment
+	var options []fuzzy.Option
 	for _, profile := range profiles {
 		// Build aligned description with consistent spacing
 		description := fmt.Sprintf("Account: %-*s | Role: %-*s | Region: %s",
 			maxAccountLen, profile.accountID,
 			maxRoleLen, profile.roleName,
 			profile.region)
+		if profile.roleARN != "" {
+			description = fmt.Sprintf("Assume: %s | Source: %s", profile.roleARN, profile.sourceProfile)
+		}
 
 		// Add metadata for consistent display
 		metadata := map[string]string{
@@ -177,6 +376,10 @@ func runAWSCtx(_ *cobra.Command, _ []string) error {
 			"region":     profile.region,
 			"start_url":  profile.startURL,
 		}
+		if profile.roleARN != "" {
+			metadata["role_arn"] = profile.roleARN
+			metadata["source_profile"] = profile.sourceProfile
+		}
 
 		options = append(options, fuzzy.Option{
 			Value:       profile.name,
@@ -185,61 +388,34 @@ func runAWSCtx(_ *cobra.Command, _ []string) error {
 		})
 	}
 
-	if len(options) == 0 {
-		fmt.Println("❌ No profiles found in AWS config")
-		fmt.Println("💡 Run 'synacklab auth sync' first to create profiles from AWS SSO")
-		return nil
-	}
+	return options
+}
+
+// previewAWSProfile renders the preview pane for an AWS profile option
+func previewAWSProfile(option fuzzy.Option) string {
+	return fuzzy.FormatPreview(
+		fuzzy.PreviewField{Label: "Profile", Value: option.Value},
+		fuzzy.PreviewField{Label: "Account ID", Value: option.Metadata["account_id"]},
+		fuzzy.PreviewField{Label: "Role", Value: option.Metadata["role_name"]},
+		fuzzy.PreviewField{Label: "Region", Value: option.Metadata["region"]},
+		fuzzy.PreviewField{Label: "SSO start URL", Value: option.Metadata["start_url"]},
+		fuzzy.PreviewField{Label: "Role ARN", Value: option.Metadata["role_arn"]},
+		fuzzy.PreviewField{Label: "Source profile", Value: option.Metadata["source_profile"]},
+	)
+}
 
-	// Use fzf-based fuzzy finder for profile selection
-	finder := fuzzy.NewFzf("🔍 Select AWS profile to set as default:")
+// selectAWSProfile presents AWS profile options in the fzf-based fuzzy finder
+func selectAWSProfile(prompt string, options []fuzzy.Option) (string, error) {
+	finder := fuzzy.NewFzf(prompt)
+	finder.SetPreview(previewAWSProfile)
 	if err := finder.SetOptions(options); err != nil {
-		return fmt.Errorf("failed to set finder options: %w", err)
+		return "", fmt.Errorf("failed to set finder options: %w", err)
 	}
 
 	selectedProfile, err := finder.Select()
 	if err != nil {
-		return fmt.Errorf("profile selection failed: %w", err)
-	}
-
-	// Copy selected profile configuration to default section
-	err = setDefaultProfile(cfg, selectedProfile, configPath)
-	if err != nil {
-		return fmt.Errorf("failed to set default profile: %w", err)
-	}
-
-	fmt.Printf("✅ Successfully set '%s' as the default AWS profile\n", selectedProfile)
-	return nil
-}
-
-func setDefaultProfile(cfg *ini.File, profileName, configPath string) error {
-	// Get the selected profile section
-	profileSectionName := fmt.Sprintf("profile %s", profileName)
-
-	// Check if section exists
-	if !cfg.HasSection(profileSectionName) {
-		return fmt.Errorf("profile section not found: %s", profileSectionName)
+		return "", fmt.Errorf("profile selection failed: %w", err)
 	}
 
-	profileSection := cfg.Section(profileSectionName)
-
-	// Create or get default section
-	var defaultSection *ini.Section
-	if cfg.HasSection("default") {
-		defaultSection = cfg.Section("default")
-	} else {
-		var err error
-		defaultSection, err = cfg.NewSection("default")
-		if err != nil {
-			return fmt.Errorf("failed to create default section: %w", err)
-		}
-	}
-
-	// Copy all keys from profile to default
-	for _, key := range profileSection.Keys() {
-		defaultSection.Key(key.Name()).SetValue(key.Value())
-	}
-
-	// Save the configuration
-	return cfg.SaveTo(configPath)
+	return selectedProfile, nil
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -177,6 +178,66 @@ func TestSetDefaultProfileExistingDefault(t *testing.T) {
 	}
 }
 
+func TestSetDefaultProfileSwitchesKind(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+
+	cfg := ini.Empty()
+	sso, err := cfg.NewSection("profile shared-admin")
+	if err != nil {
+		t.Fatalf("Failed to create profile section: %v", err)
+	}
+	sso.Key("sso_start_url").SetValue("https://test.awsapps.com/start")
+	sso.Key("sso_region").SetValue("us-east-1")
+	sso.Key("sso_account_id").SetValue("111111111111")
+	sso.Key("sso_role_name").SetValue("AdministratorAccess")
+	sso.Key("region").SetValue("us-east-1")
+
+	chained, err := cfg.NewSection("profile prod-deploy")
+	if err != nil {
+		t.Fatalf("Failed to create profile section: %v", err)
+	}
+	chained.Key("source_profile").SetValue("shared-admin")
+	chained.Key("role_arn").SetValue("arn:aws:iam::222222222222:role/Deploy")
+	chained.Key("external_id").SetValue("ext-123")
+	chained.Key("mfa_serial").SetValue("arn:aws:iam::111111111111:mfa/me")
+	chained.Key("role_session_name").SetValue("deploy")
+
+	steps := []struct {
+		profile string
+		present []string
+		absent  []string
+	}{
+		{"prod-deploy", []string{"role_arn", "source_profile", "external_id"}, []string{"sso_account_id", "sso_role_name", "sso_start_url"}},
+		{"shared-admin", []string{"sso_account_id", "sso_role_name", "region"}, []string{"role_arn", "source_profile", "external_id", "mfa_serial", "role_session_name"}},
+		{"prod-deploy", []string{"role_arn", "mfa_serial", "role_session_name"}, []string{"sso_account_id", "sso_role_name", "sso_region", "region"}},
+	}
+
+	for _, step := range steps {
+		if err := setDefaultProfile(cfg, step.profile, configPath); err != nil {
+			t.Fatalf("setDefaultProfile(%s) failed: %v", step.profile, err)
+		}
+
+		saved, err := ini.Load(configPath)
+		if err != nil {
+			t.Fatalf("Failed to load updated config: %v", err)
+		}
+		defaultSection := saved.Section("default")
+		for _, key := range step.present {
+			if !defaultSection.HasKey(key) {
+				t.Errorf("After switching to %s, default should have %s", step.profile, key)
+			}
+		}
+		for _, key := range step.absent {
+			if defaultSection.HasKey(key) {
+				t.Errorf("After switching to %s, default should not have %s", step.profile, key)
+			}
+		}
+		if got := matchDefaultProfile(saved); got != step.profile {
+			t.Errorf("After switching to %s, matchDefaultProfile() = %s", step.profile, got)
+		}
+	}
+}
+
 func TestSetDefaultProfileMissingProfile(t *testing.T) {
 	// Create a temporary directory for testing
 	tempDir := t.TempDir()
@@ -420,3 +481,115 @@ func TestAWSCtxNoAuthFlag(t *testing.T) {
 	// which triggers actual AWS authentication and browser opening
 	t.Skip("Skipping test that calls real AWS authentication - requires mocking")
 }
+
+func TestPreviewAWSProfile(t *testing.T) {
+	options := buildAWSProfileOptions([]awsProfileInfo{
+		{name: "dev", accountID: "123456789012", roleName: "Admin", region: "us-east-1", startURL: "https://example.awsapps.com/start"},
+		{name: "deploy", roleARN: "arn:aws:iam::210987654321:role/Deploy", sourceProfile: "dev"},
+	})
+
+	sso := previewAWSProfile(options[0])
+	for _, want := range []string{
+		"Account ID:     123456789012",
+		"Role:           Admin",
+		"Region:         us-east-1",
+		"SSO start URL:  https://example.awsapps.com/start",
+	} {
+		if !strings.Contains(sso, want) {
+			t.Errorf("SSO profile preview missing %q:\n%s", want, sso)
+		}
+	}
+	if strings.Contains(sso, "Role ARN") {
+		t.Errorf("Expected no role ARN for SSO profile:\n%s", sso)
+	}
+
+	assumed := previewAWSProfile(options[1])
+	for _, want := range []string{"Role ARN:", "arn:aws:iam::210987654321:role/Deploy", "Source profile:"} {
+		if !strings.Contains(assumed, want) {
+			t.Errorf("Assumed role preview missing %q:\n%s", want, assumed)
+		}
+	}
+}
+
+func TestRunAWSCtxQueryPrint(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestAWSConfig(t, filepath.Join(home, ".aws", "config"), `[profile prod-admin]
+sso_account_id = 111111111111
+sso_role_name = Admin
+
+[profile prod-readonly]
+sso_account_id = 111111111111
+sso_role_name = ReadOnly
+
+[profile staging-admin]
+sso_account_id = 222222222222
+sso_role_name = Admin
+`)
+
+	defer func() { awsCtxQuery, awsCtxFirst, awsCtxPrint = "", false, false }()
+	awsCtxPrint = true
+
+	tests := []struct {
+		name    string
+		query   string
+		first   bool
+		want    string
+		wantErr bool
+		code    int
+	}{
+		{name: "single match", query: "prd adm", want: "prod-admin\n"},
+		{name: "ambiguous", query: "admin", wantErr: true, code: exitAmbiguous},
+		{name: "first", query: "admin", first: true, want: "prod-admin\n"},
+		{name: "no match", query: "dev", wantErr: true, code: exitNoMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			awsCtxQuery, awsCtxFirst = tt.query, tt.first
+
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			var buf bytes.Buffer
+			done := make(chan bool)
+			go func() {
+				_, _ = buf.ReadFrom(r)
+				done <- true
+			}()
+
+			err := runAWSCtx(awsCtxCmd, nil)
+
+			_ = w.Close()
+			os.Stdout = oldStdout
+			<-done
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				if got := exitCode(err); got != tt.code {
+					t.Errorf("exitCode() = %d, want %d", got, tt.code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runAWSCtx failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Expected only the profile on stdout, got %q", buf.String())
+			}
+		})
+	}
+}
+
+func writeTestAWSConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create AWS config directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write AWS config: %v", err)
+	}
+}
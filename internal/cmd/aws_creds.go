@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/ini.v1"
+
+	"synacklab/internal/auth"
+	"synacklab/pkg/config"
+)
+
+// maxProfileChainDepth limits how many assume-role hops a profile may chain
+const maxProfileChainDepth = 5
+
+// defaultSessionNameTemplate is used when a chained profile has no session name
+const defaultSessionNameTemplate = "synacklab-${user}"
+
+var credsFormat string
+
+// mfaPromptMu serializes MFA prompts on the terminal
+var mfaPromptMu sync.Mutex
+
+var awsCredsCmd = &cobra.Command{
+	Use:   "creds [profile]",
+	Short: "Print temporary AWS credentials for a profile",
+	Long: `Print temporary AWS credentials for an SSO or chained profile.
+
+SSO profiles obtain role credentials through your AWS SSO session. Chained
+profiles (source_profile/role_arn) are resolved hop by hop with STS AssumeRole,
+prompting for an MFA code when mfa_serial is set.
+
+Output formats:
+  process  JSON for the AWS CLI credential_process setting (default)
+  env      shell export statements for eval
+
+Examples:
+  synacklab auth creds prod-admin
+  synacklab auth creds prod-deploy --format env
+  eval "$(synacklab auth creds prod-deploy --format env)"
+
+  # ~/.aws/config
+  [profile prod-deploy-process]
+  credential_process = synacklab auth creds prod-deploy`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAWSCreds,
+}
+
+func init() {
+	awsCredsCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to configuration file")
+	awsCredsCmd.Flags().StringVar(&credsFormat, "format", "process", "Output format: process or env")
+}
+
+// roleCredentialsProvider obtains SSO role credentials and assumes chained roles
+type roleCredentialsProvider interface {
+	GetRoleCredentials(ctx context.Context, accountID, roleName string) (*auth.RoleCredentials, error)
+	AssumeRole(ctx context.Context, source *auth.RoleCredentials, opts auth.AssumeRoleOptions) (*auth.RoleCredentials, error)
+}
+
+// credentialProcessOutput is the JSON format expected by credential_process
+type credentialProcessOutput struct {
+	Version         int    `json:"Version"`
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken"`
+	Expiration      string `json:"Expiration"`
+}
+
+func runAWSCreds(_ *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if credsFormat != "process" && credsFormat != "env" {
+		return fmt.Errorf("invalid format '%s': must be one of: process, env", credsFormat)
+	}
+
+	appConfig, err := loadCredsAppConfig()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadAWSConfigForCreds()
+	if err != nil {
+		return err
+	}
+
+	profileName := ""
+	if len(args) > 0 {
+		profileName = args[0]
+	} else {
+		options := buildAWSProfileOptions(listAWSProfiles(cfg, appConfig))
+		if len(options) == 0 {
+			return fmt.Errorf("no profiles found in AWS config")
+		}
+		profileName, err = selectAWSProfile("🔍 Select AWS profile:", options)
+		if err != nil {
+			return err
+		}
+	}
+
+	profile, err := lookupAWSProfile(cfg, appConfig, profileName)
+	if err != nil {
+		return err
+	}
+
+	authManager, err := auth.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize authentication manager: %w", err)
+	}
+	// Stdout carries the credentials read by the AWS CLI or the shell
+	authManager.SetOutput(os.Stderr)
+
+	if err := ensureAWSAuthenticated(ctx, authManager); err != nil {
+		return err
+	}
+
+	creds, err := resolveProfileCredentials(ctx, authManager, cfg, appConfig, profile.name)
+	if err != nil {
+		return reportCredentialsError(err)
+	}
+
+	switch credsFormat {
+	case "env":
+		region := resolveProfileRegion(cfg, appConfig, profile.name)
+		fmt.Print(formatCredentialsEnv(profile.name, region, creds))
+	default:
+		output, err := formatCredentialProcess(creds)
+		if err != nil {
+			return err
+		}
+		fmt.Println(output)
+	}
+
+	return nil
+}
+
+// loadCredsAppConfig loads the synacklab configuration without prompting
+func loadCredsAppConfig() (*config.Config, error) {
+	var appConfig *config.Config
+	var err error
+
+	if configFile != "" {
+		appConfig, err = config.LoadConfigFromPath(configFile)
+	} else {
+		appConfig, err = config.LoadConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if err := appConfig.ValidateChainedProfiles(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return appConfig, nil
+}
+
+// loadAWSConfigForCreds loads ~/.aws/config, returning an empty file if it does not exist
+func loadAWSConfigForCreds() (*ini.File, error) {
+	configPath, err := getAWSConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return ini.Empty(), nil
+	}
+
+	cfg, err := ini.Load(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// reportCredentialsError prints structured authentication errors with troubleshooting steps
+func reportCredentialsError(err error) error {
+	var authErr *auth.Error
+	if errors.As(err, &authErr) {
+		fmt.Fprintf(os.Stderr, "❌ %s%s\n", authErr.Message, authErr.GetTroubleshootingMessage())
+		return fmt.Errorf("failed to obtain credentials")
+	}
+	return fmt.Errorf("failed to obtain credentials: %w", err)
+}
+
+// chainedProfileInfo converts a chained profile from synacklab config into profile info
+func chainedProfileInfo(profile *config.ChainedProfile) *awsProfileInfo {
+	return &awsProfileInfo{
+		name:            profile.Name,
+		region:          profile.Region,
+		sourceProfile:   profile.SourceProfile,
+		roleARN:         profile.RoleARN,
+		externalID:      profile.ExternalID,
+		sessionName:     profile.SessionName,
+		mfaSerial:       profile.MFASerial,
+		durationSeconds: profile.DurationSeconds,
+	}
+}
+
+// lookupAWSProfile finds a profile in synacklab chained profiles or ~/.aws/config
+func lookupAWSProfile(cfg *ini.File, appConfig *config.Config, profileName string) (*awsProfileInfo, error) {
+	// Chained profiles in synacklab config take precedence so that
+	// session name templates are rendered at resolution time
+	if appConfig != nil {
+		if chained, ok := appConfig.GetChainedProfile(profileName); ok {
+			return chainedProfileInfo(chained), nil
+		}
+	}
+
+	return findAWSProfile(cfg, profileName)
+}
+
+// listAWSProfiles lists profiles from ~/.aws/config plus chained profiles not yet synced
+func listAWSProfiles(cfg *ini.File, appConfig *config.Config) []awsProfileInfo {
+	profiles := parseAWSProfiles(cfg)
+	if appConfig == nil {
+		return profiles
+	}
+
+	existing := make(map[string]bool)
+	for _, profile := range profiles {
+		existing[profile.name] = true
+	}
+
+	for i := range appConfig.AWS.ChainedProfiles {
+		chained := &appConfig.AWS.ChainedProfiles[i]
+		if !existing[chained.Name] {
+			profiles = append(profiles, *chainedProfileInfo(chained))
+		}
+	}
+
+	return profiles
+}
+
+// resolveProfileCredentials resolves credentials for an SSO or chained profile
+func resolveProfileCredentials(ctx context.Context, provider roleCredentialsProvider, cfg *ini.File, appConfig *config.Config, profileName string) (*auth.RoleCredentials, error) {
+	return resolveProfileChain(ctx, provider, cfg, appConfig, profileName, nil)
+}
+
+// resolveProfileChain walks source_profile references down to an SSO profile and assumes each role on the way back
+func resolveProfileChain(ctx context.Context, provider roleCredentialsProvider, cfg *ini.File, appConfig *config.Config, profileName string, chain []string) (*auth.RoleCredentials, error) {
+	for _, visited := range chain {
+		if visited == profileName {
+			return nil, fmt.Errorf("profile chain contains a cycle: %s -> %s", strings.Join(chain, " -> "), profileName)
+		}
+	}
+	chain = append(chain, profileName)
+	if len(chain) > maxProfileChainDepth+1 {
+		return nil, fmt.Errorf("profile chain exceeds maximum depth of %d: %s", maxProfileChainDepth, strings.Join(chain, " -> "))
+	}
+
+	profile, err := lookupAWSProfile(cfg, appConfig, profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	// SSO profiles terminate the chain
+	if profile.roleARN == "" {
+		if profile.accountID == "" || profile.roleName == "" {
+			return nil, fmt.Errorf("profile '%s' is neither an SSO profile nor a chained profile", profileName)
+		}
+		return provider.GetRoleCredentials(ctx, profile.accountID, profile.roleName)
+	}
+
+	if profile.sourceProfile == "" {
+		return nil, fmt.Errorf("profile '%s' has role_arn but no source_profile", profileName)
+	}
+
+	source, err := resolveProfileChain(ctx, provider, cfg, appConfig, profile.sourceProfile, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := auth.AssumeRoleOptions{
+		RoleARN:     profile.roleARN,
+		ExternalID:  profile.externalID,
+		SessionName: renderProfileSessionName(profile),
+		MFASerial:   profile.mfaSerial,
+		Region:      resolveProfileRegion(cfg, appConfig, profileName),
+		Duration:    time.Duration(profile.durationSeconds) * time.Second,
+	}
+
+	if profile.mfaSerial != "" {
+		opts.TokenCode, err = promptMFATokenCode(profile.mfaSerial)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return provider.AssumeRole(ctx, source, opts)
+}
+
+// resolveProfileRegion returns the region of a profile, falling back along its source chain
+func resolveProfileRegion(cfg *ini.File, appConfig *config.Config, profileName string) string {
+	for depth := 0; depth <= maxProfileChainDepth && profileName != ""; depth++ {
+		profile, err := lookupAWSProfile(cfg, appConfig, profileName)
+		if err != nil {
+			return ""
+		}
+		if profile.region != "" {
+			return profile.region
+		}
+		profileName = profile.sourceProfile
+	}
+	return ""
+}
+
+// renderProfileSessionName renders the role session name for a chained profile
+func renderProfileSessionName(profile *awsProfileInfo) string {
+	template := profile.sessionName
+	if template == "" {
+		template = defaultSessionNameTemplate
+	}
+
+	return auth.RenderSessionName(template, map[string]string{
+		"user":           currentUsername(),
+		"profile":        profile.name,
+		"source_profile": profile.sourceProfile,
+	})
+}
+
+// currentUsername returns the local username used in session names
+func currentUsername() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		// Strip Windows domain prefixes
+		if idx := strings.LastIndex(current.Username, `\`); idx >= 0 {
+			return current.Username[idx+1:]
+		}
+		return current.Username
+	}
+	if username := os.Getenv("USER"); username != "" {
+		return username
+	}
+	return "unknown"
+}
+
+// promptMFATokenCode asks for an MFA token code on stderr
+func promptMFATokenCode(mfaSerial string) (string, error) {
+	// Profiles may be resolved concurrently, so only prompt one at a time
+	mfaPromptMu.Lock()
+	defer mfaPromptMu.Unlock()
+
+	fmt.Fprintf(os.Stderr, "🔑 Enter MFA code for %s: ", mfaSerial)
+
+	reader := bufio.NewReader(os.Stdin)
+	code, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read MFA code: %w", err)
+	}
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return "", fmt.Errorf("MFA code is required for %s", mfaSerial)
+	}
+
+	return code, nil
+}
+
+// formatCredentialProcess renders credentials in credential_process JSON format
+func formatCredentialProcess(creds *auth.RoleCredentials) (string, error) {
+	data, err := json.Marshal(credentialProcessOutput{
+		Version:         1,
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Expiration:      creds.Expiration.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+	return string(data), nil
+}
+
+// formatCredentialsEnv renders credentials as shell export statements
+func formatCredentialsEnv(profileName, region string, creds *auth.RoleCredentials) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("export AWS_ACCESS_KEY_ID=%s\n", creds.AccessKeyID))
+	sb.WriteString(fmt.Sprintf("export AWS_SECRET_ACCESS_KEY=%s\n", creds.SecretAccessKey))
+	sb.WriteString(fmt.Sprintf("export AWS_SESSION_TOKEN=%s\n", creds.SessionToken))
+	sb.WriteString(fmt.Sprintf("export AWS_CREDENTIAL_EXPIRATION=%s\n", creds.Expiration.UTC().Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("export SYNACKLAB_PROFILE=%s\n", profileName))
+	if region != "" {
+		sb.WriteString(fmt.Sprintf("export AWS_REGION=%s\n", region))
+		sb.WriteString(fmt.Sprintf("export AWS_DEFAULT_REGION=%s\n", region))
+	}
+	return sb.String()
+}
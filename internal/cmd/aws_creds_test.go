@@ -0,0 +1,367 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"synacklab/internal/auth"
+	"synacklab/pkg/config"
+)
+
+// mockRoleCredentialsProvider records role credential and assume role calls
+type mockRoleCredentialsProvider struct {
+	mu          sync.Mutex
+	ssoCalls    []string
+	assumeCalls []auth.AssumeRoleOptions
+}
+
+func (m *mockRoleCredentialsProvider) GetRoleCredentials(_ context.Context, accountID, roleName string) (*auth.RoleCredentials, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ssoCalls = append(m.ssoCalls, accountID+"/"+roleName)
+	return &auth.RoleCredentials{
+		AccessKeyID:     "SSO-" + accountID,
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      time.Now().Add(time.Hour),
+	}, nil
+}
+
+func (m *mockRoleCredentialsProvider) AssumeRole(_ context.Context, source *auth.RoleCredentials, opts auth.AssumeRoleOptions) (*auth.RoleCredentials, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assumeCalls = append(m.assumeCalls, opts)
+	return &auth.RoleCredentials{
+		AccessKeyID:     source.AccessKeyID + ">" + opts.RoleARN,
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      time.Now().Add(time.Hour),
+	}, nil
+}
+
+func createTestAWSConfigForCreds(t *testing.T) *ini.File {
+	t.Helper()
+
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("profile shared-admin")
+	if err != nil {
+		t.Fatalf("Failed to create profile section: %v", err)
+	}
+	section.Key("sso_account_id").SetValue("111111111111")
+	section.Key("sso_role_name").SetValue("AdministratorAccess")
+	section.Key("region").SetValue("us-east-1")
+
+	section, err = cfg.NewSection("profile legacy-deploy")
+	if err != nil {
+		t.Fatalf("Failed to create profile section: %v", err)
+	}
+	section.Key("source_profile").SetValue("shared-admin")
+	section.Key("role_arn").SetValue("arn:aws:iam::333333333333:role/Legacy")
+
+	return cfg
+}
+
+func TestResolveProfileCredentials(t *testing.T) {
+	cfg := createTestAWSConfigForCreds(t)
+	appConfig := &config.Config{
+		AWS: config.AWSConfig{
+			ChainedProfiles: []config.ChainedProfile{
+				{
+					Name:          "prod-deploy",
+					SourceProfile: "shared-admin",
+					RoleARN:       "arn:aws:iam::222222222222:role/Deploy",
+					ExternalID:    "ext-123",
+					SessionName:   "${profile}-session",
+					Region:        "eu-west-1",
+				},
+				{
+					Name:          "prod-break-glass",
+					SourceProfile: "prod-deploy",
+					RoleARN:       "arn:aws:iam::222222222222:role/BreakGlass",
+				},
+			},
+		},
+	}
+
+	t.Run("SSO profile", func(t *testing.T) {
+		provider := &mockRoleCredentialsProvider{}
+		creds, err := resolveProfileCredentials(context.Background(), provider, cfg, appConfig, "shared-admin")
+		if err != nil {
+			t.Fatalf("resolveProfileCredentials failed: %v", err)
+		}
+		if creds.AccessKeyID != "SSO-111111111111" {
+			t.Errorf("Unexpected access key: %s", creds.AccessKeyID)
+		}
+		if len(provider.assumeCalls) != 0 {
+			t.Errorf("Expected no assume role calls, got %d", len(provider.assumeCalls))
+		}
+	})
+
+	t.Run("single hop chained profile", func(t *testing.T) {
+		provider := &mockRoleCredentialsProvider{}
+		creds, err := resolveProfileCredentials(context.Background(), provider, cfg, appConfig, "prod-deploy")
+		if err != nil {
+			t.Fatalf("resolveProfileCredentials failed: %v", err)
+		}
+		if creds.AccessKeyID != "SSO-111111111111>arn:aws:iam::222222222222:role/Deploy" {
+			t.Errorf("Unexpected access key: %s", creds.AccessKeyID)
+		}
+		if len(provider.assumeCalls) != 1 {
+			t.Fatalf("Expected 1 assume role call, got %d", len(provider.assumeCalls))
+		}
+		opts := provider.assumeCalls[0]
+		if opts.ExternalID != "ext-123" {
+			t.Errorf("Expected external ID 'ext-123', got '%s'", opts.ExternalID)
+		}
+		if opts.SessionName != "prod-deploy-session" {
+			t.Errorf("Expected session name 'prod-deploy-session', got '%s'", opts.SessionName)
+		}
+		if opts.Region != "eu-west-1" {
+			t.Errorf("Expected region 'eu-west-1', got '%s'", opts.Region)
+		}
+	})
+
+	t.Run("two hop chained profile", func(t *testing.T) {
+		provider := &mockRoleCredentialsProvider{}
+		_, err := resolveProfileCredentials(context.Background(), provider, cfg, appConfig, "prod-break-glass")
+		if err != nil {
+			t.Fatalf("resolveProfileCredentials failed: %v", err)
+		}
+		if len(provider.assumeCalls) != 2 {
+			t.Fatalf("Expected 2 assume role calls, got %d", len(provider.assumeCalls))
+		}
+		if provider.assumeCalls[1].RoleARN != "arn:aws:iam::222222222222:role/BreakGlass" {
+			t.Errorf("Expected break-glass role last, got %s", provider.assumeCalls[1].RoleARN)
+		}
+		// Region is inherited from the source profile
+		if provider.assumeCalls[1].Region != "eu-west-1" {
+			t.Errorf("Expected inherited region 'eu-west-1', got '%s'", provider.assumeCalls[1].Region)
+		}
+	})
+
+	t.Run("chained profile from AWS config", func(t *testing.T) {
+		provider := &mockRoleCredentialsProvider{}
+		_, err := resolveProfileCredentials(context.Background(), provider, cfg, appConfig, "legacy-deploy")
+		if err != nil {
+			t.Fatalf("resolveProfileCredentials failed: %v", err)
+		}
+		if len(provider.assumeCalls) != 1 || !strings.HasPrefix(provider.assumeCalls[0].SessionName, "synacklab-") {
+			t.Errorf("Expected default session name, got %+v", provider.assumeCalls)
+		}
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		provider := &mockRoleCredentialsProvider{}
+		if _, err := resolveProfileCredentials(context.Background(), provider, cfg, appConfig, "missing"); err == nil {
+			t.Error("Expected error for unknown profile")
+		}
+	})
+}
+
+func TestResolveProfileCredentialsCycle(t *testing.T) {
+	appConfig := &config.Config{
+		AWS: config.AWSConfig{
+			ChainedProfiles: []config.ChainedProfile{
+				{Name: "a", SourceProfile: "b", RoleARN: "arn:aws:iam::1:role/A"},
+				{Name: "b", SourceProfile: "a", RoleARN: "arn:aws:iam::1:role/B"},
+			},
+		},
+	}
+
+	provider := &mockRoleCredentialsProvider{}
+	_, err := resolveProfileCredentials(context.Background(), provider, ini.Empty(), appConfig, "a")
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Expected cycle error, got %v", err)
+	}
+}
+
+func TestFormatCredentialProcess(t *testing.T) {
+	creds := &auth.RoleCredentials{
+		AccessKeyID:     "AKIATEST",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	output, err := formatCredentialProcess(creds)
+	if err != nil {
+		t.Fatalf("formatCredentialProcess failed: %v", err)
+	}
+
+	var parsed map[string]any
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	if parsed["Version"] != float64(1) {
+		t.Errorf("Expected Version 1, got %v", parsed["Version"])
+	}
+	if parsed["AccessKeyId"] != "AKIATEST" {
+		t.Errorf("Expected AccessKeyId AKIATEST, got %v", parsed["AccessKeyId"])
+	}
+	if parsed["Expiration"] != "2030-01-01T00:00:00Z" {
+		t.Errorf("Unexpected Expiration: %v", parsed["Expiration"])
+	}
+}
+
+func TestWriteChainedProfiles(t *testing.T) {
+	cfg := createTestAWSConfigForCreds(t)
+	chained := []config.ChainedProfile{
+		{
+			Name:            "prod-deploy",
+			SourceProfile:   "shared-admin",
+			RoleARN:         "arn:aws:iam::222222222222:role/Deploy",
+			ExternalID:      "ext-123",
+			SessionName:     "${profile}",
+			MFASerial:       "arn:aws:iam::111111111111:mfa/jane",
+			DurationSeconds: 3600,
+		},
+	}
+
+	if err := writeChainedProfiles(cfg, chained); err != nil {
+		t.Fatalf("writeChainedProfiles failed: %v", err)
+	}
+
+	section := cfg.Section("profile prod-deploy")
+	expected := map[string]string{
+		"source_profile":    "shared-admin",
+		"role_arn":          "arn:aws:iam::222222222222:role/Deploy",
+		"external_id":       "ext-123",
+		"role_session_name": "prod-deploy",
+		"mfa_serial":        "arn:aws:iam::111111111111:mfa/jane",
+		"duration_seconds":  "3600",
+	}
+	for key, value := range expected {
+		if actual := section.Key(key).String(); actual != value {
+			t.Errorf("Expected %s = %s, got %s", key, value, actual)
+		}
+	}
+}
+
+// setupExpiredSSOSession points the home directory at a temporary one holding
+// an expired SSO session and an SSO profile named dev, and serves the AWS SSO
+// sign-in and role credentials from a fake endpoint, so that commands sign in
+// again before resolving credentials
+func setupExpiredSSOSession(t *testing.T) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(home, ".aws", "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(home, ".aws", "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "us-east-1")
+	// No browser can be opened, so the sign-in prints the verification URL
+	t.Setenv("PATH", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/client/register":
+			_, _ = w.Write([]byte(`{"clientId": "client", "clientSecret": "secret"}`))
+		case "/device_authorization":
+			_, _ = w.Write([]byte(`{"deviceCode": "device", "userCode": "ABCD-EFGH", "verificationUriComplete": "https://device.sso.example.com/?user_code=ABCD-EFGH"}`))
+		case "/token":
+			_, _ = w.Write([]byte(`{"accessToken": "access", "expiresIn": 28800}`))
+		case "/federation/credentials":
+			expiration := time.Now().Add(time.Hour).UnixMilli()
+			_ = json.NewEncoder(w).Encode(map[string]any{"roleCredentials": map[string]any{
+				"accessKeyId": "AKIDEXAMPLE", "secretAccessKey": "secret", "sessionToken": "session", "expiration": expiration,
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("AWS_ENDPOINT_URL", server.URL)
+
+	files := map[string]string{
+		".synacklab/config.yaml":          "aws:\n  sso:\n    start_url: https://example.awsapps.com/start\n    region: us-east-1\n",
+		".synacklab/aws_credentials.json": `{"access_token": "old", "start_url": "https://example.awsapps.com/start", "region": "us-east-1", "expires_at": "2020-01-01T00:00:00Z"}`,
+		".aws/config":                     "[profile dev]\nsso_account_id = 111111111111\nsso_role_name = Admin\nregion = eu-west-1\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(home, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+}
+
+// captureStdout returns what run writes to stdout
+func captureStdout(t *testing.T, run func() error) (string, error) {
+	t.Helper()
+
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	os.Stdout = w
+
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		_, _ = buf.ReadFrom(r)
+		close(done)
+	}()
+
+	runErr := run()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+	<-done
+	return buf.String(), runErr
+}
+
+func TestRunAWSCredsAfterSessionExpired(t *testing.T) {
+	oldFormat := credsFormat
+	defer func() { credsFormat = oldFormat }()
+
+	t.Run("process format", func(t *testing.T) {
+		setupExpiredSSOSession(t)
+		credsFormat = "process"
+		stdout, err := captureStdout(t, func() error { return runAWSCreds(awsCredsCmd, []string{"dev"}) })
+		if err != nil {
+			t.Fatalf("runAWSCreds failed: %v", err)
+		}
+
+		// The sign-in instructions go to stderr, stdout is read by the AWS CLI
+		var output map[string]any
+		if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+			t.Fatalf("stdout is not JSON only: %v\n%s", err, stdout)
+		}
+		if output["AccessKeyId"] != "AKIDEXAMPLE" {
+			t.Errorf("Unexpected access key: %v", output["AccessKeyId"])
+		}
+	})
+
+	t.Run("env format", func(t *testing.T) {
+		setupExpiredSSOSession(t)
+		credsFormat = "env"
+		stdout, err := captureStdout(t, func() error { return runAWSCreds(awsCredsCmd, []string{"dev"}) })
+		if err != nil {
+			t.Fatalf("runAWSCreds failed: %v", err)
+		}
+
+		for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+			if !strings.HasPrefix(line, "export ") {
+				t.Errorf("stdout has a line the shell cannot eval: %q", line)
+			}
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"synacklab/internal/auth"
+)
+
+var statusRefresh bool
+
+var awsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show AWS SSO session status",
+	Long: `Show the status of the stored AWS SSO session.
+
+Displays the SSO start URL the session belongs to, the remaining session
+lifetime, whether the session can be refreshed silently, and when the OIDC
+client registration used for refreshing expires.
+
+Examples:
+  synacklab auth status
+  synacklab auth status --refresh`,
+	RunE: runAWSStatus,
+}
+
+func init() {
+	awsStatusCmd.Flags().BoolVar(&statusRefresh, "refresh", false, "Refresh the access token now using the stored refresh token")
+}
+
+func runAWSStatus(_ *cobra.Command, _ []string) error {
+	authManager, err := auth.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize authentication manager: %w", err)
+	}
+
+	session, err := authManager.GetStoredCredentials()
+	if err != nil {
+		fmt.Println("❌ Not authenticated with AWS SSO")
+		fmt.Println("💡 Run 'synacklab auth aws-login' to authenticate")
+		return fmt.Errorf("not authenticated")
+	}
+
+	if statusRefresh {
+		session, err = authManager.RefreshSession(context.Background(), session)
+		if err != nil {
+			return reportCredentialsError(err)
+		}
+		fmt.Println("✅ Access token refreshed")
+	}
+
+	displaySessionStatus(session, time.Now())
+
+	if time.Now().After(session.ExpiresAt) && !session.CanRefresh(time.Now()) {
+		return fmt.Errorf("session expired")
+	}
+
+	return nil
+}
+
+// displaySessionStatus prints the lifetime and refresh state of an SSO session
+func displaySessionStatus(session *auth.SSOSession, now time.Time) {
+	fmt.Printf("📍 SSO URL: %s\n", session.StartURL)
+	fmt.Printf("🌍 Region: %s\n", session.Region)
+
+	if now.After(session.ExpiresAt) {
+		fmt.Printf("⏰ Session expired: %s (%s ago)\n",
+			session.ExpiresAt.Format("2006-01-02 15:04:05 MST"), formatDuration(now.Sub(session.ExpiresAt)))
+	} else {
+		fmt.Printf("⏰ Session expires: %s (in %s)\n",
+			session.ExpiresAt.Format("2006-01-02 15:04:05 MST"), formatDuration(session.ExpiresAt.Sub(now)))
+	}
+
+	if session.CanRefresh(now) {
+		fmt.Println("🔄 Silent refresh: available")
+	} else {
+		fmt.Println("🔄 Silent refresh: not available (re-run 'synacklab auth aws-login' to enable)")
+	}
+
+	if !session.ClientSecretExpiresAt.IsZero() {
+		if now.After(session.ClientSecretExpiresAt) {
+			fmt.Printf("🪪 Client registration expired: %s\n", session.ClientSecretExpiresAt.Format("2006-01-02 15:04:05 MST"))
+		} else {
+			fmt.Printf("🪪 Client registration expires: %s (in %s)\n",
+				session.ClientSecretExpiresAt.Format("2006-01-02 15:04:05 MST"), formatDuration(session.ClientSecretExpiresAt.Sub(now)))
+		}
+	}
+}
+
+// formatDuration renders a duration as a short human-readable string
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAWSStatusCommand(t *testing.T) {
+	if awsStatusCmd.Use != "status" {
+		t.Errorf("Expected command use to be 'status', got '%s'", awsStatusCmd.Use)
+	}
+
+	if awsStatusCmd.Flags().Lookup("refresh") == nil {
+		t.Error("refresh flag should be defined")
+	}
+
+	found := false
+	for _, cmd := range authCmd.Commands() {
+		if cmd.Use == "status" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("status command not registered on auth command")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{duration: 30 * time.Second, expected: "1m"},
+		{duration: 45 * time.Minute, expected: "45m"},
+		{duration: 7*time.Hour + 12*time.Minute, expected: "7h12m"},
+		{duration: 89*24*time.Hour + 3*time.Hour, expected: "89d3h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if actual := formatDuration(tt.duration); actual != tt.expected {
+				t.Errorf("formatDuration(%v) = %s, want %s", tt.duration, actual, tt.expected)
+			}
+		})
+	}
+}
@@ -16,10 +16,12 @@ import (
 	"gopkg.in/ini.v1"
 
 	"synacklab/pkg/config"
+	"synacklab/pkg/fuzzy"
 )
 
 var (
 	resetProfiles bool
+	syncSelect    bool
 )
 
 var awsSyncCmd = &cobra.Command{
@@ -27,21 +29,24 @@ var awsSyncCmd = &cobra.Command{
 	Short: "Sync AWS SSO profiles to local configuration",
 	Long: `Authenticate with AWS SSO and sync all available profiles to ~/.aws/config.
 By default, this command will add new profiles and update existing ones with remote data.
-Use --reset to replace all profiles with only those available in AWS SSO.`,
+Use --reset to replace all profiles with only those available in AWS SSO.
+Use --select to choose which accounts to include from an interactive list.`,
 	RunE: runAWSSync,
 }
 
 func init() {
 	awsSyncCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to configuration file")
 	awsSyncCmd.Flags().BoolVar(&resetProfiles, "reset", false, "Replace all profiles with AWS SSO profiles only")
+	awsSyncCmd.Flags().BoolVar(&syncSelect, "select", false, "Interactively choose which accounts to include")
 }
 
 // AWSProfile represents an AWS profile configuration
 type AWSProfile struct {
-	Name      string
-	AccountID string
-	RoleName  string
-	Region    string
+	Name        string
+	AccountID   string
+	AccountName string
+	RoleName    string
+	Region      string
 }
 
 // SSOSession represents AWS SSO session information
@@ -83,8 +88,16 @@ func runAWSSync(_ *cobra.Command, _ []string) error {
 
 	fmt.Printf("📋 Found %d profiles in AWS SSO\n", len(profiles))
 
+	if syncSelect {
+		profiles, err = selectSSOAccounts(profiles)
+		if err != nil {
+			return fmt.Errorf("failed to select accounts: %w", err)
+		}
+		fmt.Printf("📋 Selected %d profiles\n", len(profiles))
+	}
+
 	// Update AWS config file
-	err = updateAWSConfigWithProfiles(profiles, ssoSession, resetProfiles)
+	err = updateAWSConfigWithProfiles(profiles, appConfig.AWS.ChainedProfiles, ssoSession, resetProfiles)
 	if err != nil {
 		return fmt.Errorf("failed to update AWS config: %w", err)
 	}
@@ -98,6 +111,69 @@ func runAWSSync(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// selectSSOAccounts asks which accounts to include and returns their profiles
+func selectSSOAccounts(profiles []AWSProfile) ([]AWSProfile, error) {
+	finder := fuzzy.NewFzf("🔍 Select accounts to sync (Tab to toggle, Ctrl+A for all):")
+	if err := finder.SetOptions(buildSSOAccountOptions(profiles)); err != nil {
+		return nil, fmt.Errorf("failed to set finder options: %w", err)
+	}
+
+	accounts, err := finder.SelectMany()
+	if err != nil {
+		return nil, err
+	}
+
+	return filterProfilesByAccount(profiles, accounts), nil
+}
+
+// buildSSOAccountOptions returns one picker option per account, listing its roles
+func buildSSOAccountOptions(profiles []AWSProfile) []fuzzy.Option {
+	var options []fuzzy.Option
+	index := make(map[string]int)
+	for _, profile := range profiles {
+		i, seen := index[profile.AccountID]
+		if !seen {
+			i = len(options)
+			index[profile.AccountID] = i
+			options = append(options, fuzzy.Option{
+				Value: profile.AccountID,
+				Metadata: map[string]string{
+					"account_id": profile.AccountID,
+					"region":     profile.Region,
+				},
+			})
+		}
+
+		option := &options[i]
+		if option.Metadata["role_name"] != "" {
+			option.Metadata["role_name"] += ", "
+		}
+		option.Metadata["role_name"] += profile.RoleName
+		option.Description = fmt.Sprintf("%s | Roles: %s", profile.AccountName, option.Metadata["role_name"])
+	}
+
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].Description < options[j].Description
+	})
+	return options
+}
+
+// filterProfilesByAccount keeps the profiles of the given accounts
+func filterProfilesByAccount(profiles []AWSProfile, accountIDs []string) []AWSProfile {
+	keep := make(map[string]bool, len(accountIDs))
+	for _, id := range accountIDs {
+		keep[id] = true
+	}
+
+	var filtered []AWSProfile
+	for _, profile := range profiles {
+		if keep[profile.AccountID] {
+			filtered = append(filtered, profile)
+		}
+	}
+	return filtered
+}
+
 func loadAppConfig() (*config.Config, error) {
 	var appConfig *config.Config
 	var err error
@@ -242,10 +318,11 @@ func fetchSSOProfiles(session *SSOSession) ([]AWSProfile, error) {
 				sanitizeProfileName(*role.RoleName))
 
 			profile := AWSProfile{
-				Name:      profileName,
-				AccountID: *account.AccountId,
-				RoleName:  *role.RoleName,
-				Region:    session.Region,
+				Name:        profileName,
+				AccountID:   *account.AccountId,
+				AccountName: aws.ToString(account.AccountName),
+				RoleName:    *role.RoleName,
+				Region:      session.Region,
 			}
 			profiles = append(profiles, profile)
 		}
@@ -282,7 +359,7 @@ func sanitizeProfileName(name string) string {
 	return sanitized
 }
 
-func updateAWSConfigWithProfiles(profiles []AWSProfile, session *SSOSession, reset bool) error {
+func updateAWSConfigWithProfiles(profiles []AWSProfile, chained []config.ChainedProfile, session *SSOSession, reset bool) error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return fmt.Errorf("failed to get home directory: %w", err)
@@ -343,6 +420,11 @@ func updateAWSConfigWithProfiles(profiles []AWSProfile, session *SSOSession, res
 		section.Key("output").SetValue("json")
 	}
 
+	// Write chained assume-role profiles after the SSO profiles they depend on
+	if err := writeChainedProfiles(cfg, chained); err != nil {
+		return err
+	}
+
 	// Save the configuration file
 	if err := cfg.SaveTo(configPath); err != nil {
 		return fmt.Errorf("failed to save AWS config: %w", err)
@@ -354,3 +436,54 @@ func updateAWSConfigWithProfiles(profiles []AWSProfile, session *SSOSession, res
 
 	return nil
 }
+
+// writeChainedProfiles writes chained profiles as source_profile/role_arn sections
+func writeChainedProfiles(cfg *ini.File, chained []config.ChainedProfile) error {
+	if len(chained) == 0 {
+		return nil
+	}
+
+	chainedNames := make(map[string]bool)
+	for _, profile := range chained {
+		chainedNames[profile.Name] = true
+	}
+
+	for _, profile := range chained {
+		sectionName := fmt.Sprintf("profile %s", profile.Name)
+
+		// Replace the section so that removed settings do not linger
+		if cfg.HasSection(sectionName) {
+			cfg.DeleteSection(sectionName)
+		}
+
+		section, err := cfg.NewSection(sectionName)
+		if err != nil {
+			return fmt.Errorf("failed to create section %s: %w", sectionName, err)
+		}
+
+		if !chainedNames[profile.SourceProfile] && !cfg.HasSection(fmt.Sprintf("profile %s", profile.SourceProfile)) {
+			fmt.Printf("⚠️  Warning: Source profile '%s' for chained profile '%s' not found in AWS config\n", profile.SourceProfile, profile.Name)
+		}
+
+		info := chainedProfileInfo(&profile)
+		section.Key("source_profile").SetValue(profile.SourceProfile)
+		section.Key("role_arn").SetValue(profile.RoleARN)
+		section.Key("role_session_name").SetValue(renderProfileSessionName(info))
+		if profile.ExternalID != "" {
+			section.Key("external_id").SetValue(profile.ExternalID)
+		}
+		if profile.MFASerial != "" {
+			section.Key("mfa_serial").SetValue(profile.MFASerial)
+		}
+		if profile.DurationSeconds != 0 {
+			section.Key("duration_seconds").SetValue(fmt.Sprintf("%d", profile.DurationSeconds))
+		}
+		if profile.Region != "" {
+			section.Key("region").SetValue(profile.Region)
+		}
+		section.Key("output").SetValue("json")
+	}
+
+	fmt.Printf("🔗 Wrote %d chained profiles\n", len(chained))
+	return nil
+}
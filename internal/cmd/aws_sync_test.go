@@ -42,3 +42,28 @@ func TestAWSProfile(t *testing.T) {
 		t.Errorf("Expected account ID '123456789012', got %s", profile.AccountID)
 	}
 }
+
+func TestBuildSSOAccountOptions(t *testing.T) {
+	profiles := []AWSProfile{
+		{Name: "prod-admin", AccountID: "222222222222", AccountName: "Prod", RoleName: "Admin"},
+		{Name: "dev-admin", AccountID: "111111111111", AccountName: "Dev", RoleName: "Admin"},
+		{Name: "prod-readonly", AccountID: "222222222222", AccountName: "Prod", RoleName: "ReadOnly"},
+	}
+
+	options := buildSSOAccountOptions(profiles)
+	if len(options) != 2 {
+		t.Fatalf("Expected one option per account, got %d", len(options))
+	}
+
+	if options[0].Value != "111111111111" || options[0].Description != "Dev | Roles: Admin" {
+		t.Errorf("Unexpected first option: %+v", options[0])
+	}
+	if options[1].Value != "222222222222" || options[1].Description != "Prod | Roles: Admin, ReadOnly" {
+		t.Errorf("Unexpected second option: %+v", options[1])
+	}
+
+	filtered := filterProfilesByAccount(profiles, []string{"222222222222"})
+	if len(filtered) != 2 || filtered[0].Name != "prod-admin" || filtered[1].Name != "prod-readonly" {
+		t.Errorf("Unexpected filtered profiles: %+v", filtered)
+	}
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var eksCmd = &cobra.Command{
+	Use:   "eks",
+	Short: "Amazon EKS commands",
+	Long: `Commands for working with Amazon EKS clusters.
+
+Use the subcommands to obtain cluster credentials without the AWS CLI.`,
+}
+
+func init() {
+	eksCmd.AddCommand(eksTokenCmd)
+}
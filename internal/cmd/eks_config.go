@@ -6,34 +6,85 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
+
+	"synacklab/internal/auth"
+	"synacklab/pkg/fuzzy"
 )
 
 var (
-	eksRegion string
-	dryRun    bool
+	eksRegion      string
+	dryRun         bool
+	eksAllProfiles bool
+	eksProfiles    []string
+	eksProfileRole string
+	eksContextTmpl string
+	eksNamespace   string
+	eksPrune       bool
+	eksKubeconfig  string
+	eksSelect      bool
 )
 
 var eksConfigCmd = &cobra.Command{
 	Use:   "eks-config",
 	Short: "Configure EKS cluster authentication",
-	Long: `Discover EKS clusters in your AWS account and update ~/.kube/config with authentication.
+	Long: `Discover EKS clusters in your AWS account and update your kubeconfig with authentication.
 This command will:
-- List all EKS clusters in the specified region (or all regions if not specified)
+- List all EKS clusters in the specified region (or all enabled regions if not specified)
 - Add new clusters to your kubeconfig
 - Update existing cluster configurations with current AWS data
-- Support multiple AWS accounts when switching profiles`,
+- Support multiple AWS accounts when switching profiles
+
+With --all-profiles or --profiles, clusters are discovered in every selected SSO
+profile concurrently. Each generated kubeconfig user passes the profile to
+'synacklab eks token' so that tokens are always obtained for the right account,
+and contexts are prefixed with the profile name.
+
+Examples:
+  synacklab auth eks-config
+  synacklab auth eks-config --region us-west-2
+  synacklab auth eks-config --all-profiles
+  synacklab auth eks-config --all-profiles --role ReadOnlyAccess
+  synacklab auth eks-config --profiles dev-admin,prod-admin
+  synacklab auth eks-config --context-template '${account}-${cluster}' --namespace platform
+  synacklab auth eks-config --all-profiles --prune
+  synacklab auth eks-config --kubeconfig ~/.kube/synacklab.yaml
+  synacklab auth eks-config --all-profiles --select
+
+Context names default to <cluster>-<region>, or <profile>-<cluster>-<region> with
+profiles. Templates may use ${cluster}, ${region}, ${profile} and ${account}, and
+aliases from the aws.eks section of the configuration file take precedence.
+
+With --prune, synacklab-managed entries are removed for clusters that no longer
+exist in the searched accounts and regions.
+
+Clusters are written to --kubeconfig or aws.eks.kubeconfig if set, so that a
+hand-maintained kubeconfig stays untouched. Otherwise they are written to the
+file kubectl writes to: the first existing file in KUBECONFIG, or ~/.kube/config.
+
+With --select, the discovered clusters are listed in an interactive picker and
+only the chosen ones are added or updated.`,
 	RunE: runEKSConfig,
 }
 
 func init() {
 	eksConfigCmd.Flags().StringVarP(&eksRegion, "region", "r", "", "AWS region to search for EKS clusters (searches all regions if not specified)")
 	eksConfigCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be done without making changes")
+	eksConfigCmd.Flags().BoolVar(&eksAllProfiles, "all-profiles", false, "Discover clusters in every synced SSO account (one profile per account)")
+	eksConfigCmd.Flags().StringSliceVar(&eksProfiles, "profiles", nil, "Comma-separated AWS profiles to discover clusters with")
+	eksConfigCmd.Flags().StringVar(&eksProfileRole, "role", "", "Only use SSO profiles with this role name (with --all-profiles)")
+	eksConfigCmd.Flags().StringVar(&eksContextTmpl, "context-template", "", "Context name template using ${cluster}, ${region}, ${profile} and ${account}")
+	eksConfigCmd.Flags().StringVar(&eksNamespace, "namespace", "", "Default namespace for newly created contexts")
+	eksConfigCmd.Flags().BoolVar(&eksPrune, "prune", false, "Remove synacklab-managed entries for clusters that no longer exist")
+	eksConfigCmd.Flags().StringVar(&eksKubeconfig, "kubeconfig", "", "Kubeconfig file to write clusters to (e.g. ~/.kube/synacklab.yaml)")
+	eksConfigCmd.Flags().BoolVar(&eksSelect, "select", false, "Interactively choose which discovered clusters to add")
+	eksConfigCmd.MarkFlagsMutuallyExclusive("all-profiles", "profiles")
 }
 
 // EKSCluster represents an EKS cluster configuration
@@ -43,9 +94,16 @@ type EKSCluster struct {
 	Endpoint string
 	ARN      string
 	Status   string
+	Version  string
+	Profile  string
+	// CertificateAuthority is the base64 encoded CA bundle of the API server
+	CertificateAuthority string
+	// Context is the kubeconfig context name, set once naming rules are applied
+	Context string
 }
 
-// KubeConfig represents the structure of a kubeconfig file
+// KubeConfig represents the structure of a kubeconfig file. Fields that are
+// not modelled are kept in Extra so that they survive a load and save.
 type KubeConfig struct {
 	APIVersion     string         `yaml:"apiVersion"`
 	Kind           string         `yaml:"kind"`
@@ -54,36 +112,52 @@ type KubeConfig struct {
 	Contexts       []KubeContext  `yaml:"contexts"`
 	Users          []KubeUser     `yaml:"users"`
 	Preferences    map[string]any `yaml:"preferences,omitempty"`
+	Extra          map[string]any `yaml:",inline"`
 }
 
 type KubeCluster struct {
 	Name    string            `yaml:"name"`
 	Cluster KubeClusterConfig `yaml:"cluster"`
+	Extra   map[string]any    `yaml:",inline"`
 }
 
 type KubeClusterConfig struct {
-	Server                   string `yaml:"server"`
-	CertificateAuthorityData string `yaml:"certificate-authority-data,omitempty"`
+	Server                   string               `yaml:"server"`
+	CertificateAuthorityData string               `yaml:"certificate-authority-data,omitempty"`
+	Extensions               []KubeNamedExtension `yaml:"extensions,omitempty"`
+	Extra                    map[string]any       `yaml:",inline"`
+}
+
+// KubeNamedExtension is a named extension attached to a kubeconfig entry
+type KubeNamedExtension struct {
+	Name      string         `yaml:"name"`
+	Extension map[string]any `yaml:"extension"`
 }
 
 type KubeContext struct {
 	Name    string            `yaml:"name"`
 	Context KubeContextConfig `yaml:"context"`
+	Extra   map[string]any    `yaml:",inline"`
 }
 
 type KubeContextConfig struct {
-	Cluster   string `yaml:"cluster"`
-	User      string `yaml:"user"`
-	Namespace string `yaml:"namespace,omitempty"`
+	Cluster   string         `yaml:"cluster"`
+	User      string         `yaml:"user"`
+	Namespace string         `yaml:"namespace,omitempty"`
+	Extra     map[string]any `yaml:",inline"`
 }
 
 type KubeUser struct {
-	Name string       `yaml:"name"`
-	User KubeUserExec `yaml:"user"`
+	Name  string         `yaml:"name"`
+	User  KubeUserExec   `yaml:"user"`
+	Extra map[string]any `yaml:",inline"`
 }
 
+// KubeUserExec holds the user credentials. Only exec plugins are modelled;
+// tokens, client certificates and other methods are kept in Extra.
 type KubeUserExec struct {
-	Exec KubeExecConfig `yaml:"exec"`
+	Exec  KubeExecConfig `yaml:"exec,omitempty"`
+	Extra map[string]any `yaml:",inline"`
 }
 
 type KubeExecConfig struct {
@@ -91,6 +165,7 @@ type KubeExecConfig struct {
 	Command    string           `yaml:"command"`
 	Args       []string         `yaml:"args"`
 	Env        []KubeExecEnvVar `yaml:"env,omitempty"`
+	Extra      map[string]any   `yaml:",inline"`
 }
 
 type KubeExecEnvVar struct {
@@ -101,69 +176,203 @@ type KubeExecEnvVar struct {
 func runEKSConfig(_ *cobra.Command, _ []string) error {
 	fmt.Println("🔍 Discovering EKS clusters...")
 
-	// Get current AWS configuration
-	cfg, err := awsconfig.LoadDefaultConfig(context.TODO())
+	targets, err := resolveEKSDiscoveryTargets(context.Background())
 	if err != nil {
-		return fmt.Errorf("failed to load AWS config: %w", err)
+		return err
 	}
 
-	// Get regions to search
-	regions, err := getRegionsToSearch(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to determine regions to search: %w", err)
+	if len(targets) == 0 {
+		fmt.Println("❌ No AWS profiles available for discovery")
+		return nil
 	}
 
-	fmt.Printf("🌍 Searching for EKS clusters in %d region(s)...\n", len(regions))
+	// Determine the enabled regions of every account
+	jobs := planEKSDiscovery(targets)
 
-	// Discover clusters across all regions
-	var allClusters []EKSCluster
-	for _, region := range regions {
-		clusters, err := discoverEKSClusters(cfg, region)
-		if err != nil {
-			fmt.Printf("⚠️  Warning: Failed to discover clusters in region %s: %v\n", region, err)
-			continue
-		}
-		allClusters = append(allClusters, clusters...)
+	if len(targets) > 1 {
+		fmt.Printf("🌍 Searching for EKS clusters in %d account(s), %d region(s) in total...\n", len(targets), len(jobs))
+	} else {
+		fmt.Printf("🌍 Searching for EKS clusters in %d region(s)...\n", len(jobs))
+	}
+
+	// Discover clusters across all accounts and regions
+	allClusters, completed := discoverEKSClustersConcurrently(jobs, discoverEKSClusters)
+
+	naming, err := loadEKSNaming()
+	if err != nil {
+		return err
+	}
+	applyEKSContextNames(allClusters, naming)
+
+	// Cache the results so that eks-ctx can show cluster details offline
+	if !dryRun {
+		recordEKSInventory(allClusters, completed)
 	}
 
-	if len(allClusters) == 0 {
+	if len(allClusters) == 0 && !eksPrune {
 		fmt.Println("❌ No EKS clusters found in the specified region(s)")
 		return nil
 	}
 
 	fmt.Printf("📋 Found %d EKS cluster(s):\n", len(allClusters))
 	for _, cluster := range allClusters {
-		fmt.Printf("  • %s (%s) - %s\n", cluster.Name, cluster.Region, cluster.Status)
+		if cluster.Profile != "" {
+			fmt.Printf("  • %s (%s, %s) - %s %s\n", cluster.Name, cluster.Profile, cluster.Region, cluster.Status, cluster.Version)
+		} else {
+			fmt.Printf("  • %s (%s) - %s %s\n", cluster.Name, cluster.Region, cluster.Status, cluster.Version)
+		}
+	}
+
+	selectedClusters := allClusters
+	if eksSelect && len(allClusters) > 0 {
+		selectedClusters, err = selectEKSClusters(allClusters)
+		if err != nil {
+			return fmt.Errorf("failed to select clusters: %w", err)
+		}
+		fmt.Printf("📋 Selected %d EKS cluster(s)\n", len(selectedClusters))
+	}
+
+	kubeConfigPath, err := resolveEKSKubeconfigPath(naming.Kubeconfig)
+	if err != nil {
+		return err
 	}
 
 	if dryRun {
-		fmt.Println("\n🔍 Dry run mode - no changes will be made")
+		fmt.Printf("\n🔍 Dry run mode - no changes will be made to %s\n", kubeConfigPath)
 		return nil
 	}
 
 	// Update kubeconfig
-	err = updateKubeConfig(allClusters)
+	err = updateKubeConfig(selectedClusters, kubeConfigUpdateOptions{
+		Path:       kubeConfigPath,
+		Namespace:  naming.Namespace,
+		Prune:      eksPrune,
+		Completed:  completed,
+		Discovered: allClusters,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update kubeconfig: %w", err)
 	}
 
-	fmt.Printf("✅ Successfully updated kubeconfig with %d EKS cluster(s)\n", len(allClusters))
+	fmt.Printf("✅ Successfully updated %s with %d EKS cluster(s)\n", kubeConfigPath, len(selectedClusters))
+	fmt.Print(kubeConfigHint(kubeConfigPath))
 	return nil
 }
 
-func getRegionsToSearch(_ aws.Config) ([]string, error) {
+// selectEKSClusters asks which of the discovered clusters to add to the kubeconfig
+func selectEKSClusters(clusters []EKSCluster) ([]EKSCluster, error) {
+	finder := fuzzy.NewFzf("🔍 Select clusters to add (Tab to toggle, Ctrl+A for all):")
+	finder.SetPreview(previewKubeContext)
+
+	options := make([]fuzzy.Option, 0, len(clusters))
+	for _, cluster := range clusters {
+		location := cluster.Region
+		if cluster.Profile != "" {
+			location = cluster.Profile + ", " + cluster.Region
+		}
+
+		options = append(options, fuzzy.Option{
+			Value:       eksContextName(cluster),
+			Description: fmt.Sprintf("%s (%s) - %s %s", cluster.Name, location, cluster.Status, cluster.Version),
+			Metadata: map[string]string{
+				"cluster": cluster.Name,
+				"server":  cluster.Endpoint,
+				"region":  cluster.Region,
+				"status":  cluster.Status,
+				"version": cluster.Version,
+			},
+		})
+	}
+
+	if err := finder.SetOptions(options); err != nil {
+		return nil, fmt.Errorf("failed to set finder options: %w", err)
+	}
+
+	names, err := finder.SelectMany()
+	if err != nil {
+		return nil, err
+	}
+
+	return filterEKSClusters(clusters, names), nil
+}
+
+// filterEKSClusters keeps the clusters whose context names were selected
+func filterEKSClusters(clusters []EKSCluster, contextNames []string) []EKSCluster {
+	keep := make(map[string]bool, len(contextNames))
+	for _, name := range contextNames {
+		keep[name] = true
+	}
+
+	var filtered []EKSCluster
+	for _, cluster := range clusters {
+		if keep[eksContextName(cluster)] {
+			filtered = append(filtered, cluster)
+		}
+	}
+	return filtered
+}
+
+// resolveEKSDiscoveryTargets returns the credentials to discover clusters with:
+// the default AWS credentials, or one set per selected SSO profile
+func resolveEKSDiscoveryTargets(ctx context.Context) ([]eksDiscoveryTarget, error) {
+	if !eksAllProfiles && len(eksProfiles) == 0 {
+		// Get current AWS configuration
+		cfg, err := awsconfig.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		return []eksDiscoveryTarget{{Config: cfg}}, nil
+	}
+
+	appConfig, err := loadCredsAppConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	awsCfg, err := loadAWSConfigForCreds()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := selectEKSProfiles(listAWSProfiles(awsCfg, appConfig), eksAllProfiles, eksProfiles, eksProfileRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(profiles) == 0 {
+		fmt.Println("💡 Run 'synacklab auth sync' first to create profiles from AWS SSO")
+		return nil, nil
+	}
+
+	authManager, err := auth.NewManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize authentication manager: %w", err)
+	}
+
+	if err := ensureAWSAuthenticated(ctx, authManager); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("🔑 Obtaining credentials for %d profile(s)...\n", len(profiles))
+	return buildEKSProfileTargets(ctx, authManager, awsCfg, appConfig, profiles), nil
+}
+
+// getRegionsToSearch returns the --region flag, or the regions enabled for the
+// account. The common EKS regions are searched if they cannot be listed.
+func getRegionsToSearch(cfg aws.Config) ([]string, error) {
 	if eksRegion != "" {
 		return []string{eksRegion}, nil
 	}
 
-	// If no region specified, use common AWS regions where EKS is available
-	// In a production tool, you might want to dynamically fetch this list
-	return []string{
-		"us-east-1", "us-east-2", "us-west-1", "us-west-2",
-		"eu-west-1", "eu-west-2", "eu-west-3", "eu-central-1",
-		"ap-southeast-1", "ap-southeast-2", "ap-northeast-1", "ap-northeast-2",
-		"ca-central-1", "sa-east-1",
-	}, nil
+	regions, err := describeEnabledRegions(context.TODO(), cfg)
+	if err != nil {
+		if cfg.Credentials != nil {
+			fmt.Printf("⚠️  Warning: Could not list enabled regions, searching common regions instead: %v\n", err)
+		}
+		return append([]string(nil), fallbackEKSRegions...), nil
+	}
+
+	return regions, nil
 }
 
 func discoverEKSClusters(cfg aws.Config, region string) ([]EKSCluster, error) {
@@ -172,60 +381,90 @@ func discoverEKSClusters(cfg aws.Config, region string) ([]EKSCluster, error) {
 		o.Region = region
 	})
 
-	// List clusters
-	listResp, err := eksClient.ListClusters(context.TODO(), &eks.ListClustersInput{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list clusters in region %s: %w", region, err)
-	}
-
-	var clusters []EKSCluster
-	for _, clusterName := range listResp.Clusters {
-		// Get cluster details
-		describeResp, err := eksClient.DescribeCluster(context.TODO(), &eks.DescribeClusterInput{
-			Name: aws.String(clusterName),
-		})
+	// List clusters across all pages
+	var clusterNames []string
+	paginator := eks.NewListClustersPaginator(eksClient, &eks.ListClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
 		if err != nil {
-			fmt.Printf("⚠️  Warning: Failed to describe cluster %s: %v\n", clusterName, err)
-			continue
+			return nil, fmt.Errorf("failed to list clusters in region %s: %w", region, err)
 		}
+		clusterNames = append(clusterNames, page.Clusters...)
+	}
+
+	// Describe clusters in parallel
+	results := make([]*EKSCluster, len(clusterNames))
+	sem := make(chan struct{}, maxEKSDescribeConcurrency)
+
+	var wg sync.WaitGroup
+	for i, clusterName := range clusterNames {
+		wg.Add(1)
+		go func(i int, clusterName string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			describeResp, err := eksClient.DescribeCluster(context.TODO(), &eks.DescribeClusterInput{
+				Name: aws.String(clusterName),
+			})
+			if err != nil {
+				fmt.Printf("⚠️  Warning: Failed to describe cluster %s: %v\n", clusterName, err)
+				return
+			}
 
-		cluster := EKSCluster{
-			Name:     clusterName,
-			Region:   region,
-			Endpoint: *describeResp.Cluster.Endpoint,
-			ARN:      *describeResp.Cluster.Arn,
-			Status:   string(describeResp.Cluster.Status),
+			results[i] = &EKSCluster{
+				Name:     clusterName,
+				Region:   region,
+				Endpoint: aws.ToString(describeResp.Cluster.Endpoint),
+				ARN:      aws.ToString(describeResp.Cluster.Arn),
+				Status:   string(describeResp.Cluster.Status),
+				Version:  aws.ToString(describeResp.Cluster.Version),
+			}
+			if describeResp.Cluster.CertificateAuthority != nil {
+				results[i].CertificateAuthority = aws.ToString(describeResp.Cluster.CertificateAuthority.Data)
+			}
+		}(i, clusterName)
+	}
+	wg.Wait()
+
+	var clusters []EKSCluster
+	for _, cluster := range results {
+		if cluster != nil {
+			clusters = append(clusters, *cluster)
 		}
-		clusters = append(clusters, cluster)
 	}
 
 	return clusters, nil
 }
 
-func updateKubeConfig(clusters []EKSCluster) error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
-	}
+// kubeConfigUpdateOptions controls how discovered clusters are written to kubeconfig
+type kubeConfigUpdateOptions struct {
+	// Path is the kubeconfig file to write
+	Path string
+	// Namespace is set on contexts that do not have one yet
+	Namespace string
+	// Prune removes managed entries for clusters that no longer exist
+	Prune bool
+	// Completed lists the accounts and regions that were searched successfully
+	Completed []eksDiscoveryJob
+	// Discovered lists every cluster found, including those not selected for
+	// writing, so that pruning keeps them. Defaults to the written clusters.
+	Discovered []EKSCluster
+}
 
-	kubeDir := filepath.Join(homeDir, ".kube")
-	configPath := filepath.Join(kubeDir, "config")
+func updateKubeConfig(clusters []EKSCluster, opts kubeConfigUpdateOptions) error {
+	configPath := opts.Path
 
-	// Create .kube directory if it doesn't exist
-	if err := os.MkdirAll(kubeDir, 0755); err != nil {
-		return fmt.Errorf("failed to create .kube directory: %w", err)
+	// Create the kubeconfig directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("failed to create kubeconfig directory: %w", err)
 	}
 
 	// Load existing kubeconfig or create new one
 	var kubeConfig *KubeConfig
+	var err error
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		kubeConfig = &KubeConfig{
-			APIVersion: "v1",
-			Kind:       "Config",
-			Clusters:   []KubeCluster{},
-			Contexts:   []KubeContext{},
-			Users:      []KubeUser{},
-		}
+		kubeConfig = newKubeConfig()
 	} else {
 		kubeConfig, err = loadKubeConfig(configPath)
 		if err != nil {
@@ -233,11 +472,51 @@ func updateKubeConfig(clusters []EKSCluster) error {
 		}
 	}
 
+	addedCount, updatedCount := mergeEKSClusters(kubeConfig, clusters, opts.Namespace)
+
+	if opts.Prune {
+		discovered := opts.Discovered
+		if discovered == nil {
+			discovered = clusters
+		}
+		pruned := pruneStaleEKSEntries(kubeConfig, discovered, opts.Completed)
+		for _, name := range pruned {
+			fmt.Printf("🧹 Pruned stale cluster: %s\n", name)
+		}
+		if len(pruned) > 0 {
+			fmt.Printf("🧹 Pruned %d stale cluster(s)\n", len(pruned))
+		}
+	}
+
+	// Sort entries for consistent output
+	sort.Slice(kubeConfig.Clusters, func(i, j int) bool {
+		return kubeConfig.Clusters[i].Name < kubeConfig.Clusters[j].Name
+	})
+	sort.Slice(kubeConfig.Contexts, func(i, j int) bool {
+		return kubeConfig.Contexts[i].Name < kubeConfig.Contexts[j].Name
+	})
+	sort.Slice(kubeConfig.Users, func(i, j int) bool {
+		return kubeConfig.Users[i].Name < kubeConfig.Users[j].Name
+	})
+
+	// Save kubeconfig
+	err = saveKubeConfig(kubeConfig, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to save kubeconfig: %w", err)
+	}
+
+	fmt.Printf("📊 Added %d new clusters, updated %d existing clusters\n", addedCount, updatedCount)
+	return nil
+}
+
+// mergeEKSClusters adds or updates the cluster, user and context entries for
+// each discovered cluster and returns how many clusters were added and updated
+func mergeEKSClusters(kubeConfig *KubeConfig, clusters []EKSCluster, namespace string) (int, int) {
 	addedCount := 0
 	updatedCount := 0
 
 	for _, cluster := range clusters {
-		contextName := fmt.Sprintf("%s-%s", cluster.Name, cluster.Region)
+		contextName := eksContextName(cluster)
 		clusterName := contextName
 		userName := contextName
 
@@ -247,6 +526,8 @@ func updateKubeConfig(clusters []EKSCluster) error {
 			if existingCluster.Name == clusterName {
 				// Update existing cluster
 				kubeConfig.Clusters[i].Cluster.Server = cluster.Endpoint
+				kubeConfig.Clusters[i].Cluster.CertificateAuthorityData = cluster.CertificateAuthority
+				setManagedExtension(&kubeConfig.Clusters[i].Cluster, cluster)
 				clusterExists = true
 				updatedCount++
 				break
@@ -255,11 +536,14 @@ func updateKubeConfig(clusters []EKSCluster) error {
 
 		if !clusterExists {
 			// Add new cluster
+			clusterConfig := KubeClusterConfig{
+				Server:                   cluster.Endpoint,
+				CertificateAuthorityData: cluster.CertificateAuthority,
+			}
+			setManagedExtension(&clusterConfig, cluster)
 			kubeConfig.Clusters = append(kubeConfig.Clusters, KubeCluster{
-				Name: clusterName,
-				Cluster: KubeClusterConfig{
-					Server: cluster.Endpoint,
-				},
+				Name:    clusterName,
+				Cluster: clusterConfig,
 			})
 			addedCount++
 		}
@@ -269,15 +553,7 @@ func updateKubeConfig(clusters []EKSCluster) error {
 		for i, existingUser := range kubeConfig.Users {
 			if existingUser.Name == userName {
 				// Update existing user
-				kubeConfig.Users[i].User.Exec = KubeExecConfig{
-					APIVersion: "client.authentication.k8s.io/v1beta1",
-					Command:    "aws",
-					Args: []string{
-						"eks", "get-token",
-						"--cluster-name", cluster.Name,
-						"--region", cluster.Region,
-					},
-				}
+				kubeConfig.Users[i].User.Exec = eksExecConfig(cluster)
 				userExists = true
 				break
 			}
@@ -288,15 +564,7 @@ func updateKubeConfig(clusters []EKSCluster) error {
 			kubeConfig.Users = append(kubeConfig.Users, KubeUser{
 				Name: userName,
 				User: KubeUserExec{
-					Exec: KubeExecConfig{
-						APIVersion: "client.authentication.k8s.io/v1beta1",
-						Command:    "aws",
-						Args: []string{
-							"eks", "get-token",
-							"--cluster-name", cluster.Name,
-							"--region", cluster.Region,
-						},
-					},
+					Exec: eksExecConfig(cluster),
 				},
 			})
 		}
@@ -305,9 +573,12 @@ func updateKubeConfig(clusters []EKSCluster) error {
 		contextExists := false
 		for i, existingContext := range kubeConfig.Contexts {
 			if existingContext.Name == contextName {
-				// Update existing context
+				// Update existing context, keeping a namespace chosen by the user
 				kubeConfig.Contexts[i].Context.Cluster = clusterName
 				kubeConfig.Contexts[i].Context.User = userName
+				if kubeConfig.Contexts[i].Context.Namespace == "" {
+					kubeConfig.Contexts[i].Context.Namespace = namespace
+				}
 				contextExists = true
 				break
 			}
@@ -318,32 +589,50 @@ func updateKubeConfig(clusters []EKSCluster) error {
 			kubeConfig.Contexts = append(kubeConfig.Contexts, KubeContext{
 				Name: contextName,
 				Context: KubeContextConfig{
-					Cluster: clusterName,
-					User:    userName,
+					Cluster:   clusterName,
+					User:      userName,
+					Namespace: namespace,
 				},
 			})
 		}
 	}
 
-	// Sort entries for consistent output
-	sort.Slice(kubeConfig.Clusters, func(i, j int) bool {
-		return kubeConfig.Clusters[i].Name < kubeConfig.Clusters[j].Name
-	})
-	sort.Slice(kubeConfig.Contexts, func(i, j int) bool {
-		return kubeConfig.Contexts[i].Name < kubeConfig.Contexts[j].Name
-	})
-	sort.Slice(kubeConfig.Users, func(i, j int) bool {
-		return kubeConfig.Users[i].Name < kubeConfig.Users[j].Name
-	})
+	return addedCount, updatedCount
+}
 
-	// Save kubeconfig
-	err = saveKubeConfig(kubeConfig, configPath)
-	if err != nil {
-		return fmt.Errorf("failed to save kubeconfig: %w", err)
+// eksContextName returns the kubeconfig context name for a cluster. Unless a
+// naming rule has set the name, clusters discovered through a profile are
+// prefixed with it to keep accounts apart.
+func eksContextName(cluster EKSCluster) string {
+	if cluster.Context != "" {
+		return cluster.Context
+	}
+	if cluster.Profile != "" {
+		return fmt.Sprintf("%s-%s-%s", cluster.Profile, cluster.Name, cluster.Region)
 	}
+	return fmt.Sprintf("%s-%s", cluster.Name, cluster.Region)
+}
 
-	fmt.Printf("📊 Added %d new clusters, updated %d existing clusters\n", addedCount, updatedCount)
-	return nil
+// eksExecConfig returns the exec credential plugin configuration for a cluster.
+// Tokens are generated by 'synacklab eks token', so the AWS CLI is not required.
+// Tokens for clusters found without a profile use the selected profile or, when
+// none is selected, the default credentials.
+func eksExecConfig(cluster EKSCluster) KubeExecConfig {
+	execConfig := KubeExecConfig{
+		APIVersion: execCredentialAPIVersion,
+		Command:    "synacklab",
+		Args: []string{
+			"eks", "token",
+			"--cluster", cluster.Name,
+			"--region", cluster.Region,
+		},
+	}
+
+	if cluster.Profile != "" {
+		execConfig.Args = append(execConfig.Args, "--profile", cluster.Profile)
+	}
+
+	return execConfig
 }
 
 func loadKubeConfig(configPath string) (*KubeConfig, error) {
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/spf13/cobra"
 )
 
@@ -183,3 +187,65 @@ func TestCommandExecution(t *testing.T) {
 	dryRun = false
 	eksRegion = ""
 }
+
+func TestGetRegionsToSearchFallback(t *testing.T) {
+	originalRegion := eksRegion
+	eksRegion = ""
+	defer func() { eksRegion = originalRegion }()
+
+	// Without credentials the enabled regions cannot be listed
+	regions, err := getRegionsToSearch(aws.Config{})
+	if err != nil {
+		t.Fatalf("getRegionsToSearch failed: %v", err)
+	}
+
+	for _, expected := range []string{"ap-south-1", "eu-north-1"} {
+		found := false
+		for _, region := range regions {
+			if region == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected fallback regions to include %s", expected)
+		}
+	}
+}
+
+// fakeRegionDescriber returns fixed DescribeRegions results
+type fakeRegionDescriber struct {
+	output *ec2.DescribeRegionsOutput
+	err    error
+}
+
+func (f *fakeRegionDescriber) DescribeRegions(_ context.Context, _ *ec2.DescribeRegionsInput, _ ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error) {
+	return f.output, f.err
+}
+
+func TestListEnabledRegions(t *testing.T) {
+	client := &fakeRegionDescriber{output: &ec2.DescribeRegionsOutput{
+		Regions: []ec2types.Region{
+			{RegionName: aws.String("us-east-1")},
+			{RegionName: aws.String("ap-south-1")},
+		},
+	}}
+
+	regions, err := listEnabledRegions(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Failed to list regions: %v", err)
+	}
+
+	expected := []string{"ap-south-1", "us-east-1"}
+	if len(regions) != len(expected) || regions[0] != expected[0] || regions[1] != expected[1] {
+		t.Errorf("Expected %v, got %v", expected, regions)
+	}
+
+	if _, err := listEnabledRegions(context.Background(), &fakeRegionDescriber{output: &ec2.DescribeRegionsOutput{}}); err == nil {
+		t.Error("Expected error for a response without regions")
+	}
+
+	if _, err := listEnabledRegions(context.Background(), &fakeRegionDescriber{err: errors.New("access denied")}); err == nil {
+		t.Error("Expected DescribeRegions errors to be returned")
+	}
+}
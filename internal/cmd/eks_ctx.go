@@ -2,18 +2,25 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"synacklab/pkg/fuzzy"
+	"synacklab/pkg/state"
 )
 
 var (
 	listContexts bool
 	useFilter    bool
+	eksCtxPin    string
+	eksCtxUnpin  string
+	eksCtxShell  bool
+	eksCtxKube   string
+	eksCtxQuery  string
+	eksCtxFirst  bool
+	eksCtxPrint  bool
 )
 
 var eksCtxCmd = &cobra.Command{
@@ -21,16 +28,47 @@ var eksCtxCmd = &cobra.Command{
 	Short: "Switch Kubernetes context interactively",
 	Long: `Switch between Kubernetes contexts using an interactive fuzzy finder.
 This command will:
-- Parse your ~/.kube/config file
+- Parse your kubeconfig files (KUBECONFIG, or ~/.kube/config)
 - Display all available contexts
 - Allow you to select a context interactively
-- Set the selected context as the current context`,
+- Set the selected context as the current context
+
+Favourite contexts are listed first, followed by recently and frequently used ones.
+Pass a context name to switch directly, or '-' to switch back to the previous context.
+
+When KUBECONFIG lists several files they are merged the way kubectl does, and
+the current context is written to the first existing file.
+
+With --shell, the shared kubeconfig is left untouched: the context is written to
+a per-shell kubeconfig in ~/.synacklab/kube and an export statement that puts it
+in front of KUBECONFIG is printed for eval, so other terminals keep their context.
+
+For scripts, --query selects a context with the fuzzy matcher instead of the
+picker. It fails with exit code 3 when nothing matches and exit code 4 when
+several contexts match, unless --first is given to take the best match. --print
+writes the selected context to stdout without switching.
+
+Examples:
+  synacklab auth eks-ctx
+  synacklab auth eks-ctx prod-cluster
+  synacklab auth eks-ctx -
+  synacklab auth eks-ctx --pin prod-cluster
+  eval "$(synacklab auth eks-ctx --shell prod-cluster)"
+  kubectl --context "$(synacklab auth eks-ctx --query prod --first --print)" get pods`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runEKSCtx,
 }
 
 func init() {
 	eksCtxCmd.Flags().BoolVarP(&listContexts, "list", "l", false, "List all available contexts without switching")
 	eksCtxCmd.Flags().BoolVarP(&useFilter, "filter", "f", false, "Use filtering mode for better search experience")
+	eksCtxCmd.Flags().StringVar(&eksCtxPin, "pin", "", "Pin a context to the top of the picker")
+	eksCtxCmd.Flags().StringVar(&eksCtxUnpin, "unpin", "", "Remove a context from the pinned favourites")
+	eksCtxCmd.Flags().BoolVar(&eksCtxShell, "shell", false, "Print 'export KUBECONFIG=...' for eval instead of changing the shared kubeconfig")
+	eksCtxCmd.Flags().StringVar(&eksCtxKube, "kubeconfig", "", "Path to the kubeconfig file to use instead of KUBECONFIG or ~/.kube/config")
+	eksCtxCmd.Flags().StringVarP(&eksCtxQuery, "query", "q", "", "Select the context matching a fuzzy query without the picker")
+	eksCtxCmd.Flags().BoolVar(&eksCtxFirst, "first", false, "With --query, take the best match instead of failing when several contexts match")
+	eksCtxCmd.Flags().BoolVar(&eksCtxPrint, "print", false, "Print the selected context to stdout instead of switching to it")
 }
 
 // KubeContext represents a Kubernetes context with additional metadata
@@ -40,16 +78,41 @@ type KubeContextInfo struct {
 	User      string
 	Namespace string
 	IsCurrent bool
+	Server    string
+	Region    string
+	Status    string
+	Version   string
 }
 
-func runEKSCtx(_ *cobra.Command, _ []string) error {
-	fmt.Println("🔍 Loading Kubernetes contexts...")
+func runEKSCtx(_ *cobra.Command, args []string) error {
+	// In shell and print mode stdout is consumed by the caller, so progress output goes to stderr
+	out := progressOutput(eksCtxShell || eksCtxPrint)
 
-	// Load kubeconfig
-	kubeConfig, configPath, err := loadKubeConfigForCtx()
+	store := loadPickerState(out)
+	if eksCtxPin != "" {
+		return updateFavourite(out, store, state.PickerKubeContext, eksCtxPin, true)
+	}
+	if eksCtxUnpin != "" {
+		return updateFavourite(out, store, state.PickerKubeContext, eksCtxUnpin, false)
+	}
+
+	if err := validateQueryFlags(args, eksCtxQuery, eksCtxFirst); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "🔍 Loading Kubernetes contexts...")
+
+	// Load and merge kubeconfig files
+	paths, err := kubeConfigPaths(eksCtxKube)
+	if err != nil {
+		return err
+	}
+
+	files, err := loadKubeConfigFiles(paths)
 	if err != nil {
 		return fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
+	kubeConfig := files.Merged()
 
 	// Parse contexts
 	contexts, err := parseContexts(kubeConfig)
@@ -58,58 +121,90 @@ func runEKSCtx(_ *cobra.Command, _ []string) error {
 	}
 
 	if len(contexts) == 0 {
-		fmt.Println("❌ No Kubernetes contexts found in kubeconfig")
+		fmt.Fprintln(out, "❌ No Kubernetes contexts found in kubeconfig")
 		return nil
 	}
 
-	fmt.Printf("📋 Found %d Kubernetes context(s)\n", len(contexts))
+	fmt.Fprintf(out, "📋 Found %d Kubernetes context(s)\n", len(contexts))
+
+	// Add cluster status and version from the eks-config inventory
+	annotateContextsFromInventory(contexts, loadEKSInventoryForDisplay())
 
 	// If list mode, just display contexts and exit
 	if listContexts {
-		return displayContexts(contexts)
+		return displayContexts(out, contexts)
 	}
 
-	// Use fuzzy finder to select context
-	selectedContext, err := selectContextWithFuzzyFinder(contexts)
-	if err != nil {
-		return fmt.Errorf("failed to select context: %w", err)
+	var selectedContext string
+	switch {
+	case len(args) == 1 && args[0] == "-":
+		selectedContext, err = previousSelection(store, state.PickerKubeContext, "Kubernetes context")
+		if err != nil {
+			return err
+		}
+	case len(args) == 1:
+		selectedContext = args[0]
+	case eksCtxQuery != "":
+		options := rankOptions(store, state.PickerKubeContext, buildContextOptions(contexts))
+		selectedContext, err = fuzzy.Resolve(options, eksCtxQuery, eksCtxFirst)
+		if err != nil {
+			return err
+		}
+	default:
+		// Use fuzzy finder to select context
+		selectedContext, err = selectContextWithFuzzyFinder(store, contexts)
+		if err != nil {
+			return fmt.Errorf("failed to select context: %w", err)
+		}
 	}
 
-	// Update current context in kubeconfig
-	err = updateCurrentContext(kubeConfig, selectedContext, configPath)
-	if err != nil {
-		return fmt.Errorf("failed to update current context: %w", err)
+	if eksCtxPrint {
+		if _, err := findKubeContext(kubeConfig, selectedContext); err != nil {
+			return err
+		}
+		fmt.Println(selectedContext)
+		return nil
 	}
 
-	fmt.Printf("✅ Successfully switched to context: %s\n", selectedContext)
-	return nil
-}
+	// Remember the context being replaced so that '-' can switch back to it
+	store.Observe(state.PickerKubeContext, kubeConfig.CurrentContext)
 
-func loadKubeConfigForCtx() (*KubeConfig, string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to get home directory: %w", err)
-	}
+	if eksCtxShell {
+		if _, err := findKubeContext(kubeConfig, selectedContext); err != nil {
+			return err
+		}
 
-	configPath := filepath.Join(homeDir, ".kube", "config")
+		// An explicit --kubeconfig starts a new per-shell kubeconfig on top of it
+		shellConfig, shellConfigPath := loadShellKubeConfig(eksCtxKube == "")
+		shellConfig.CurrentContext = selectedContext
 
-	// Check if kubeconfig exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, "", fmt.Errorf("kubeconfig not found at %s", configPath)
-	}
+		if shellConfigPath, err = writeShellKubeConfig(shellConfig, shellConfigPath); err != nil {
+			return err
+		}
 
-	// Load kubeconfig
-	kubeConfig, err := loadKubeConfig(configPath)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to load kubeconfig from %s: %w", configPath, err)
+		fmt.Println(formatShellExport("KUBECONFIG", shellKubeConfigEnv(shellConfigPath, sharedKubeConfigPaths(paths))))
+	} else {
+		// Update current context in kubeconfig
+		err = updateCurrentContext(files, selectedContext)
+		if err != nil {
+			return fmt.Errorf("failed to update current context: %w", err)
+		}
 	}
 
-	return kubeConfig, configPath, nil
+	recordSelection(out, store, state.PickerKubeContext, selectedContext)
+
+	fmt.Fprintf(out, "✅ Successfully switched to context: %s\n", selectedContext)
+	return nil
 }
 
 func parseContexts(kubeConfig *KubeConfig) ([]KubeContextInfo, error) {
 	var contexts []KubeContextInfo
 
+	servers := make(map[string]string, len(kubeConfig.Clusters))
+	for _, cluster := range kubeConfig.Clusters {
+		servers[cluster.Name] = cluster.Cluster.Server
+	}
+
 	for _, context := range kubeConfig.Contexts {
 		contextInfo := KubeContextInfo{
 			Name:      context.Name,
@@ -117,6 +212,7 @@ func parseContexts(kubeConfig *KubeConfig) ([]KubeContextInfo, error) {
 			User:      context.Context.User,
 			Namespace: context.Context.Namespace,
 			IsCurrent: context.Name == kubeConfig.CurrentContext,
+			Server:    servers[context.Context.Cluster],
 		}
 
 		// Set default namespace if not specified
@@ -130,9 +226,9 @@ func parseContexts(kubeConfig *KubeConfig) ([]KubeContextInfo, error) {
 	return contexts, nil
 }
 
-func displayContexts(contexts []KubeContextInfo) error {
-	fmt.Println("\n📋 Available Kubernetes contexts:")
-	fmt.Println(strings.Repeat("-", 60))
+func displayContexts(out io.Writer, contexts []KubeContextInfo) error {
+	fmt.Fprintln(out, "\n📋 Available Kubernetes contexts:")
+	fmt.Fprintln(out, strings.Repeat("-", 60))
 
 	for _, ctx := range contexts {
 		currentMarker := " "
@@ -140,14 +236,14 @@ func displayContexts(contexts []KubeContextInfo) error {
 			currentMarker = "*"
 		}
 
-		fmt.Printf("%s %-25s | Cluster: %-20s | Namespace: %s\n",
-			currentMarker, ctx.Name, ctx.Cluster, ctx.Namespace)
+		fmt.Fprintf(out, "%s %-25s | Cluster: %-20s | Namespace: %s%s\n",
+			currentMarker, ctx.Name, ctx.Cluster, ctx.Namespace, formatClusterDetails(ctx))
 	}
 
 	// Find and display current context
 	for _, ctx := range contexts {
 		if ctx.IsCurrent {
-			fmt.Printf("\nCurrent context: %s\n", ctx.Name)
+			fmt.Fprintf(out, "\nCurrent context: %s\n", ctx.Name)
 			break
 		}
 	}
@@ -155,15 +251,34 @@ func displayContexts(contexts []KubeContextInfo) error {
 	return nil
 }
 
-func selectContextWithFuzzyFinder(contexts []KubeContextInfo) (string, error) {
+func selectContextWithFuzzyFinder(store *state.Store, contexts []KubeContextInfo) (string, error) {
 	// Create fzf-based fuzzy finder
 	finder := fuzzy.NewFzf("🔍 Select Kubernetes context:")
+	finder.SetPreview(previewKubeContext)
+
+	// Favourites and recently used contexts first
+	options := rankOptions(store, state.PickerKubeContext, buildContextOptions(contexts))
+
+	// Set options and select
+	if err := finder.SetOptions(options); err != nil {
+		return "", fmt.Errorf("failed to set finder options: %w", err)
+	}
+
+	selectedContext, err := finder.Select()
+	if err != nil {
+		return "", fmt.Errorf("context selection failed: %w", err)
+	}
 
+	return selectedContext, nil
+}
+
+// buildContextOptions returns picker options for Kubernetes contexts
+func buildContextOptions(contexts []KubeContextInfo) []fuzzy.Option {
 	// Build options with consistent metadata
 	var options []fuzzy.Option
 	for _, ctx := range contexts {
 		// Build description with cluster and namespace info
-		description := fmt.Sprintf("Cluster: %s, Namespace: %s", ctx.Cluster, ctx.Namespace)
+		description := fmt.Sprintf("Cluster: %s, Namespace: %s%s", ctx.Cluster, ctx.Namespace, formatClusterDetails(ctx))
 		if ctx.IsCurrent {
 			description += " (current)"
 		}
@@ -174,6 +289,10 @@ func selectContextWithFuzzyFinder(contexts []KubeContextInfo) (string, error) {
 			"user":      ctx.User,
 			"namespace": ctx.Namespace,
 			"current":   fmt.Sprintf("%t", ctx.IsCurrent),
+			"server":    ctx.Server,
+			"region":    ctx.Region,
+			"status":    ctx.Status,
+			"version":   ctx.Version,
 		}
 
 		options = append(options, fuzzy.Option{
@@ -183,39 +302,62 @@ func selectContextWithFuzzyFinder(contexts []KubeContextInfo) (string, error) {
 		})
 	}
 
-	// Set options and select
-	if err := finder.SetOptions(options); err != nil {
-		return "", fmt.Errorf("failed to set finder options: %w", err)
-	}
-
-	selectedContext, err := finder.Select()
-	if err != nil {
-		return "", fmt.Errorf("context selection failed: %w", err)
-	}
+	return options
+}
 
-	return selectedContext, nil
+// previewKubeContext renders the preview pane for a Kubernetes context option
+func previewKubeContext(option fuzzy.Option) string {
+	return fuzzy.FormatPreview(
+		fuzzy.PreviewField{Label: "Context", Value: option.Value},
+		fuzzy.PreviewField{Label: "Cluster", Value: option.Metadata["cluster"]},
+		fuzzy.PreviewField{Label: "Endpoint", Value: option.Metadata["server"]},
+		fuzzy.PreviewField{Label: "Region", Value: option.Metadata["region"]},
+		fuzzy.PreviewField{Label: "Status", Value: option.Metadata["status"]},
+		fuzzy.PreviewField{Label: "Version", Value: option.Metadata["version"]},
+		fuzzy.PreviewField{Label: "User", Value: option.Metadata["user"]},
+		fuzzy.PreviewField{Label: "Namespace", Value: option.Metadata["namespace"]},
+	)
 }
 
-func updateCurrentContext(kubeConfig *KubeConfig, contextName, configPath string) error {
-	// Verify the context exists
-	contextExists := false
-	for _, context := range kubeConfig.Contexts {
-		if context.Name == contextName {
-			contextExists = true
-			break
+// annotateContextsFromInventory adds cached cluster details to contexts
+func annotateContextsFromInventory(contexts []KubeContextInfo, inventory *eksInventory) {
+	for i := range contexts {
+		if entry, ok := inventory.lookup(contexts[i].Name); ok {
+			contexts[i].Region = entry.Region
+			contexts[i].Status = entry.Status
+			contexts[i].Version = entry.Version
+			if contexts[i].Server == "" {
+				contexts[i].Server = entry.Endpoint
+			}
 		}
 	}
+}
+
+// formatClusterDetails renders the cached cluster status and version, if known
+func formatClusterDetails(ctx KubeContextInfo) string {
+	switch {
+	case ctx.Status != "" && ctx.Version != "":
+		return fmt.Sprintf(" | %s v%s", ctx.Status, ctx.Version)
+	case ctx.Status != "":
+		return " | " + ctx.Status
+	default:
+		return ""
+	}
+}
 
-	if !contextExists {
-		return fmt.Errorf("context '%s' not found in kubeconfig", contextName)
+// updateCurrentContext sets the current context in the file kubectl would write it to
+func updateCurrentContext(files *kubeConfigFiles, contextName string) error {
+	// Verify the context exists
+	if _, err := findKubeContext(files.Merged(), contextName); err != nil {
+		return err
 	}
 
 	// Update current context
-	kubeConfig.CurrentContext = contextName
+	path := files.DefaultFile()
+	files.File(path).CurrentContext = contextName
 
 	// Save kubeconfig
-	err := saveKubeConfig(kubeConfig, configPath)
-	if err != nil {
+	if err := files.Save(path); err != nil {
 		return fmt.Errorf("failed to save kubeconfig: %w", err)
 	}
 
@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"synacklab/pkg/fuzzy"
 )
 
 func TestEKSCtxCommand(t *testing.T) {
@@ -322,3 +328,97 @@ func TestUpdateCurrentContext(t *testing.T) {
 		t.Error("Context 'non-existent-context' should not exist in test kubeconfig")
 	}
 }
+
+func TestPreviewKubeContext(t *testing.T) {
+	kubeConfig := &KubeConfig{
+		Clusters: []KubeCluster{{Name: "cluster1", Cluster: KubeClusterConfig{Server: "https://example.eks.amazonaws.com"}}},
+		Contexts: []KubeContext{{Name: "prod", Context: KubeContextConfig{Cluster: "cluster1", User: "user1"}}},
+	}
+
+	contexts, err := parseContexts(kubeConfig)
+	if err != nil {
+		t.Fatalf("parseContexts failed: %v", err)
+	}
+	annotateContextsFromInventory(contexts, &eksInventory{Clusters: []eksInventoryEntry{
+		{Context: "prod", Region: "eu-west-1", Status: "ACTIVE", Version: "1.29"},
+	}})
+
+	ctx := contexts[0]
+	got := previewKubeContext(fuzzy.Option{Value: ctx.Name, Metadata: map[string]string{
+		"cluster":   ctx.Cluster,
+		"user":      ctx.User,
+		"namespace": ctx.Namespace,
+		"server":    ctx.Server,
+		"region":    ctx.Region,
+		"status":    ctx.Status,
+		"version":   ctx.Version,
+	}})
+
+	for _, want := range []string{
+		"Endpoint:   https://example.eks.amazonaws.com",
+		"Region:     eu-west-1",
+		"Status:     ACTIVE",
+		"Version:    1.29",
+		"Namespace:  default",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Preview missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunEKSCtxQueryPrint(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	kubeConfig := newKubeConfig()
+	kubeConfig.CurrentContext = "staging"
+	kubeConfig.Contexts = []KubeContext{{Name: "prod-eu"}, {Name: "prod-us"}, {Name: "staging"}}
+	path := filepath.Join(home, "kubeconfig")
+	if err := saveKubeConfig(kubeConfig, path); err != nil {
+		t.Fatalf("saveKubeConfig failed: %v", err)
+	}
+	t.Setenv("KUBECONFIG", path)
+
+	defer func() { eksCtxQuery, eksCtxFirst, eksCtxPrint = "", false, false }()
+	eksCtxQuery, eksCtxPrint = "prod", true
+
+	if err := runEKSCtx(eksCtxCmd, nil); exitCode(err) != exitAmbiguous {
+		t.Errorf("Expected an ambiguous match, got %v", err)
+	}
+
+	eksCtxQuery = "preu"
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	var buf bytes.Buffer
+	done := make(chan bool)
+	go func() {
+		_, _ = buf.ReadFrom(r)
+		done <- true
+	}()
+
+	err := runEKSCtx(eksCtxCmd, nil)
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+	<-done
+
+	if err != nil {
+		t.Fatalf("runEKSCtx failed: %v", err)
+	}
+	if buf.String() != "prod-eu\n" {
+		t.Errorf("Expected only the context on stdout, got %q", buf.String())
+	}
+
+	// Printing must not switch the context
+	saved, err := loadKubeConfig(path)
+	if err != nil {
+		t.Fatalf("loadKubeConfig failed: %v", err)
+	}
+	if saved.CurrentContext != "staging" {
+		t.Errorf("Expected current context to stay 'staging', got %q", saved.CurrentContext)
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// eksInventoryEntry is a discovered cluster as recorded in the inventory cache
+type eksInventoryEntry struct {
+	Context   string    `json:"context"`
+	Name      string    `json:"name"`
+	Region    string    `json:"region"`
+	Profile   string    `json:"profile,omitempty"`
+	Status    string    `json:"status"`
+	Version   string    `json:"version,omitempty"`
+	Endpoint  string    `json:"endpoint"`
+	ARN       string    `json:"arn"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// eksInventory caches the clusters found by eks-config so that other commands
+// can show cluster details without calling AWS
+type eksInventory struct {
+	Clusters []eksInventoryEntry `json:"clusters"`
+}
+
+// getEKSInventoryPath returns the path of the cluster inventory cache
+func getEKSInventoryPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".synacklab", "eks_inventory.json"), nil
+}
+
+// loadEKSInventory reads the inventory cache. A missing file yields an empty inventory.
+func loadEKSInventory(path string) (*eksInventory, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &eksInventory{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster inventory: %w", err)
+	}
+
+	var inventory eksInventory
+	if err := json.Unmarshal(data, &inventory); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster inventory: %w", err)
+	}
+
+	return &inventory, nil
+}
+
+// saveEKSInventory writes the inventory cache
+func saveEKSInventory(path string, inventory *eksInventory) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create inventory directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster inventory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cluster inventory: %w", err)
+	}
+
+	return nil
+}
+
+// update replaces the entries of every searched account and region with the clusters found there
+func (inv *eksInventory) update(clusters []EKSCluster, completed []eksDiscoveryJob, now time.Time) {
+	searched := make(map[string]bool, len(completed))
+	for _, job := range completed {
+		searched[job.Target.Profile+"|"+job.Region] = true
+	}
+
+	var kept []eksInventoryEntry
+	for _, entry := range inv.Clusters {
+		if !searched[entry.Profile+"|"+entry.Region] {
+			kept = append(kept, entry)
+		}
+	}
+
+	for _, cluster := range clusters {
+		kept = append(kept, eksInventoryEntry{
+			Context:   eksContextName(cluster),
+			Name:      cluster.Name,
+			Region:    cluster.Region,
+			Profile:   cluster.Profile,
+			Status:    cluster.Status,
+			Version:   cluster.Version,
+			Endpoint:  cluster.Endpoint,
+			ARN:       cluster.ARN,
+			UpdatedAt: now,
+		})
+	}
+
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].Context < kept[j].Context
+	})
+	inv.Clusters = kept
+}
+
+// lookup returns the inventory entry for a kubeconfig context
+func (inv *eksInventory) lookup(contextName string) (*eksInventoryEntry, bool) {
+	for i := range inv.Clusters {
+		if inv.Clusters[i].Context == contextName {
+			return &inv.Clusters[i], true
+		}
+	}
+	return nil, false
+}
+
+// recordEKSInventory stores the discovery results in the inventory cache
+func recordEKSInventory(clusters []EKSCluster, completed []eksDiscoveryJob) {
+	path, err := getEKSInventoryPath()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: %v\n", err)
+		return
+	}
+
+	inventory, err := loadEKSInventory(path)
+	if err != nil {
+		// A corrupt cache is rebuilt from this run
+		inventory = &eksInventory{}
+	}
+
+	inventory.update(clusters, completed, time.Now())
+
+	if err := saveEKSInventory(path, inventory); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to update cluster inventory: %v\n", err)
+	}
+}
+
+// loadEKSInventoryForDisplay loads the inventory cache, returning an empty one on any error
+func loadEKSInventoryForDisplay() *eksInventory {
+	path, err := getEKSInventoryPath()
+	if err != nil {
+		return &eksInventory{}
+	}
+
+	inventory, err := loadEKSInventory(path)
+	if err != nil {
+		return &eksInventory{}
+	}
+
+	return inventory
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEKSInventoryUpdate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	inventory := &eksInventory{
+		Clusters: []eksInventoryEntry{
+			{Context: "prod-old-us-east-1", Name: "old", Region: "us-east-1", Profile: "prod"},
+			{Context: "prod-main-eu-west-1", Name: "main", Region: "eu-west-1", Profile: "prod", Status: "ACTIVE"},
+		},
+	}
+
+	// us-east-1 was searched again and "old" is gone; eu-west-1 failed and is kept
+	completed := []eksDiscoveryJob{{Target: eksDiscoveryTarget{Profile: "prod"}, Region: "us-east-1"}}
+	clusters := []EKSCluster{{Name: "new", Region: "us-east-1", Profile: "prod", Status: "CREATING", Version: "1.30"}}
+
+	inventory.update(clusters, completed, now)
+
+	var contexts []string
+	for _, entry := range inventory.Clusters {
+		contexts = append(contexts, entry.Context)
+	}
+	expected := []string{"prod-main-eu-west-1", "prod-new-us-east-1"}
+	if !reflect.DeepEqual(contexts, expected) {
+		t.Errorf("Expected %v, got %v", expected, contexts)
+	}
+
+	entry, ok := inventory.lookup("prod-new-us-east-1")
+	if !ok {
+		t.Fatal("Expected new cluster in inventory")
+	}
+	if entry.Version != "1.30" || entry.Status != "CREATING" || !entry.UpdatedAt.Equal(now) {
+		t.Errorf("Unexpected inventory entry: %+v", entry)
+	}
+}
+
+func TestEKSInventorySaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "eks_inventory.json")
+
+	empty, err := loadEKSInventory(path)
+	if err != nil {
+		t.Fatalf("Loading a missing inventory should not fail: %v", err)
+	}
+	if len(empty.Clusters) != 0 {
+		t.Errorf("Expected empty inventory, got %d clusters", len(empty.Clusters))
+	}
+
+	inventory := &eksInventory{Clusters: []eksInventoryEntry{{Context: "main-us-east-1", Status: "ACTIVE", Version: "1.29"}}}
+	if err := saveEKSInventory(path, inventory); err != nil {
+		t.Fatalf("Failed to save inventory: %v", err)
+	}
+
+	loaded, err := loadEKSInventory(path)
+	if err != nil {
+		t.Fatalf("Failed to load inventory: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, inventory) {
+		t.Errorf("Expected %+v, got %+v", inventory, loaded)
+	}
+}
+
+func TestAnnotateContextsFromInventory(t *testing.T) {
+	inventory := &eksInventory{Clusters: []eksInventoryEntry{{Context: "main-us-east-1", Status: "ACTIVE", Version: "1.29"}}}
+	contexts := []KubeContextInfo{{Name: "main-us-east-1"}, {Name: "minikube"}}
+
+	annotateContextsFromInventory(contexts, inventory)
+
+	if got := formatClusterDetails(contexts[0]); got != " | ACTIVE v1.29" {
+		t.Errorf("Unexpected details for EKS context: %q", got)
+	}
+	if got := formatClusterDetails(contexts[1]); got != "" {
+		t.Errorf("Expected no details for unknown context, got %q", got)
+	}
+}
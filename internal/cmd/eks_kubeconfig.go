@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// kubeExtensionName marks kubeconfig clusters written by eks-config
+const kubeExtensionName = "synacklab"
+
+// eksNaming holds the rules used to name, configure and write generated contexts
+type eksNaming struct {
+	Template   string
+	Aliases    map[string]string
+	Namespace  string
+	Kubeconfig string
+}
+
+// loadEKSNaming combines the aws.eks configuration with command-line flags
+func loadEKSNaming() (eksNaming, error) {
+	appConfig, err := loadCredsAppConfig()
+	if err != nil {
+		return eksNaming{}, err
+	}
+
+	if eksContextTmpl != "" {
+		appConfig.AWS.EKS.ContextTemplate = eksContextTmpl
+	}
+	if eksNamespace != "" {
+		appConfig.AWS.EKS.DefaultNamespace = eksNamespace
+	}
+	if eksKubeconfig != "" {
+		appConfig.AWS.EKS.Kubeconfig = eksKubeconfig
+	}
+
+	if err := appConfig.ValidateEKS(); err != nil {
+		return eksNaming{}, fmt.Errorf("invalid EKS configuration: %w", err)
+	}
+
+	return eksNaming{
+		Template:   appConfig.AWS.EKS.ContextTemplate,
+		Aliases:    appConfig.AWS.EKS.Aliases,
+		Namespace:  appConfig.AWS.EKS.DefaultNamespace,
+		Kubeconfig: appConfig.AWS.EKS.Kubeconfig,
+	}, nil
+}
+
+// resolveEKSKubeconfigPath returns the file discovered clusters are written to: the
+// configured file, or the file kubectl writes to by default
+func resolveEKSKubeconfigPath(configured string) (string, error) {
+	if configured != "" {
+		return expandHomePath(configured), nil
+	}
+
+	paths, err := kubeConfigPaths("")
+	if err != nil {
+		return "", err
+	}
+
+	// Never write clusters to a per-shell kubeconfig
+	shared := sharedKubeConfigPaths(paths)
+	if len(shared) == 0 {
+		if shared, err = defaultKubeConfigPaths(); err != nil {
+			return "", err
+		}
+	}
+
+	for _, path := range shared {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return shared[len(shared)-1], nil
+}
+
+// kubeConfigHint suggests a KUBECONFIG value when the written file is not read by kubectl
+func kubeConfigHint(path string) string {
+	paths, err := kubeConfigPaths("")
+	if err != nil {
+		return ""
+	}
+
+	shared := sharedKubeConfigPaths(paths)
+	for _, existing := range shared {
+		if filepath.Clean(existing) == filepath.Clean(path) {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("💡 %s is not in KUBECONFIG. To use these contexts run:\n   %s\n",
+		path, formatShellExport("KUBECONFIG", strings.Join(append(shared, path), string(os.PathListSeparator))))
+}
+
+// applyEKSContextNames sets the context name of every cluster from the template and aliases
+func applyEKSContextNames(clusters []EKSCluster, naming eksNaming) {
+	for i := range clusters {
+		clusters[i].Context = ""
+		name := renderEKSContextName(naming.Template, clusters[i])
+
+		if alias, ok := naming.Aliases[clusters[i].ARN]; ok {
+			name = alias
+		} else if alias, ok := naming.Aliases[name]; ok {
+			name = alias
+		}
+
+		clusters[i].Context = name
+	}
+}
+
+// renderEKSContextName expands a context name template for a cluster
+func renderEKSContextName(template string, cluster EKSCluster) string {
+	if template == "" {
+		return eksContextName(cluster)
+	}
+
+	replacer := strings.NewReplacer(
+		"${cluster}", cluster.Name,
+		"${region}", cluster.Region,
+		"${profile}", cluster.Profile,
+		"${account}", eksAccountID(cluster.ARN),
+	)
+
+	// Drop separators left over from empty placeholders such as ${profile}
+	return strings.Trim(replacer.Replace(template), "-_.:")
+}
+
+// eksAccountID extracts the account ID from an EKS cluster ARN
+func eksAccountID(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[4]
+}
+
+// setManagedExtension records the cluster identity on a kubeconfig cluster entry
+func setManagedExtension(clusterConfig *KubeClusterConfig, cluster EKSCluster) {
+	extension := KubeNamedExtension{
+		Name: kubeExtensionName,
+		Extension: map[string]any{
+			"arn":     cluster.ARN,
+			"name":    cluster.Name,
+			"region":  cluster.Region,
+			"profile": cluster.Profile,
+		},
+	}
+
+	for i, existing := range clusterConfig.Extensions {
+		if existing.Name == kubeExtensionName {
+			clusterConfig.Extensions[i] = extension
+			return
+		}
+	}
+	clusterConfig.Extensions = append(clusterConfig.Extensions, extension)
+}
+
+// managedExtension returns the synacklab extension of a kubeconfig cluster entry
+func managedExtension(clusterConfig KubeClusterConfig) (map[string]string, bool) {
+	for _, extension := range clusterConfig.Extensions {
+		if extension.Name != kubeExtensionName {
+			continue
+		}
+
+		values := make(map[string]string, len(extension.Extension))
+		for key, value := range extension.Extension {
+			if str, ok := value.(string); ok {
+				values[key] = str
+			}
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// pruneStaleEKSEntries removes managed clusters that were not found in a searched
+// account and region, or that are now written under a different name, together
+// with their contexts and users. It returns the names of the removed clusters.
+func pruneStaleEKSEntries(kubeConfig *KubeConfig, clusters []EKSCluster, completed []eksDiscoveryJob) []string {
+	current := make(map[string]string, len(clusters))
+	for _, cluster := range clusters {
+		current[cluster.ARN] = eksContextName(cluster)
+	}
+
+	searched := make(map[string]bool, len(completed))
+	for _, job := range completed {
+		searched[job.Target.Profile+"|"+job.Region] = true
+	}
+
+	stale := make(map[string]bool)
+	var keptClusters []KubeCluster
+	for _, kubeCluster := range kubeConfig.Clusters {
+		info, managed := managedExtension(kubeCluster.Cluster)
+		if managed {
+			if name, found := current[info["arn"]]; found {
+				managed = name != kubeCluster.Name
+			} else {
+				managed = searched[info["profile"]+"|"+info["region"]]
+			}
+		}
+
+		if managed {
+			stale[kubeCluster.Name] = true
+			continue
+		}
+		keptClusters = append(keptClusters, kubeCluster)
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+
+	// Drop contexts of stale clusters and remember their users
+	candidateUsers := make(map[string]bool)
+	var keptContexts []KubeContext
+	for _, kubeContext := range kubeConfig.Contexts {
+		if stale[kubeContext.Context.Cluster] {
+			candidateUsers[kubeContext.Context.User] = true
+			if kubeConfig.CurrentContext == kubeContext.Name {
+				kubeConfig.CurrentContext = ""
+			}
+			continue
+		}
+		keptContexts = append(keptContexts, kubeContext)
+	}
+
+	// Keep users that are still referenced by another context
+	for _, kubeContext := range keptContexts {
+		delete(candidateUsers, kubeContext.Context.User)
+	}
+
+	var keptUsers []KubeUser
+	for _, user := range kubeConfig.Users {
+		if !candidateUsers[user.Name] {
+			keptUsers = append(keptUsers, user)
+		}
+	}
+
+	kubeConfig.Clusters = keptClusters
+	kubeConfig.Contexts = keptContexts
+	kubeConfig.Users = keptUsers
+
+	pruned := make([]string, 0, len(stale))
+	for name := range stale {
+		pruned = append(pruned, name)
+	}
+	sort.Strings(pruned)
+
+	return pruned
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestApplyEKSContextNames(t *testing.T) {
+	clusters := []EKSCluster{
+		{Name: "main", Region: "us-east-1", Profile: "prod", ARN: "arn:aws:eks:us-east-1:123456789012:cluster/main"},
+		{Name: "batch", Region: "eu-west-1", ARN: "arn:aws:eks:eu-west-1:210987654321:cluster/batch"},
+		{Name: "legacy", Region: "us-west-2", ARN: "arn:aws:eks:us-west-2:210987654321:cluster/legacy"},
+	}
+
+	naming := eksNaming{
+		Template: "${profile}-${account}-${cluster}",
+		Aliases: map[string]string{
+			"arn:aws:eks:us-east-1:123456789012:cluster/main": "prod",
+			"210987654321-legacy":                             "old",
+		},
+	}
+
+	applyEKSContextNames(clusters, naming)
+
+	expected := []string{"prod", "210987654321-batch", "old"}
+	for i, cluster := range clusters {
+		if eksContextName(cluster) != expected[i] {
+			t.Errorf("Cluster %s: expected context %s, got %s", cluster.Name, expected[i], eksContextName(cluster))
+		}
+	}
+
+	// Without a template the default naming is kept
+	applyEKSContextNames(clusters, eksNaming{})
+	if got := eksContextName(clusters[0]); got != "prod-main-us-east-1" {
+		t.Errorf("Expected default context name, got %s", got)
+	}
+}
+
+func TestMergeEKSClusters(t *testing.T) {
+	kubeConfig := &KubeConfig{
+		Contexts: []KubeContext{
+			{Name: "main-us-east-1", Context: KubeContextConfig{Cluster: "main-us-east-1", User: "main-us-east-1", Namespace: "payments"}},
+		},
+	}
+
+	clusters := []EKSCluster{
+		{Name: "main", Region: "us-east-1", Endpoint: "https://main", ARN: "arn:main", CertificateAuthority: "Q0EtREFUQQ=="},
+		{Name: "batch", Region: "us-east-1", Endpoint: "https://batch", ARN: "arn:batch", CertificateAuthority: "QkFUQ0g="},
+	}
+
+	added, updated := mergeEKSClusters(kubeConfig, clusters, "platform")
+	if added != 2 || updated != 0 {
+		t.Errorf("Expected 2 added and 0 updated, got %d and %d", added, updated)
+	}
+
+	for _, kubeCluster := range kubeConfig.Clusters {
+		if kubeCluster.Cluster.CertificateAuthorityData == "" {
+			t.Errorf("Cluster %s should have certificate authority data", kubeCluster.Name)
+		}
+		if info, ok := managedExtension(kubeCluster.Cluster); !ok || info["arn"] == "" {
+			t.Errorf("Cluster %s should be marked as managed", kubeCluster.Name)
+		}
+	}
+
+	namespaces := map[string]string{}
+	for _, kubeContext := range kubeConfig.Contexts {
+		namespaces[kubeContext.Name] = kubeContext.Context.Namespace
+	}
+	if namespaces["main-us-east-1"] != "payments" {
+		t.Errorf("Existing namespace should be kept, got %s", namespaces["main-us-east-1"])
+	}
+	if namespaces["batch-us-east-1"] != "platform" {
+		t.Errorf("New context should use the default namespace, got %s", namespaces["batch-us-east-1"])
+	}
+}
+
+func TestPruneStaleEKSEntries(t *testing.T) {
+	kubeConfig := &KubeConfig{CurrentContext: "gone-us-east-1"}
+	existing := []EKSCluster{
+		{Name: "main", Region: "us-east-1", ARN: "arn:main"},
+		{Name: "gone", Region: "us-east-1", ARN: "arn:gone"},
+		{Name: "elsewhere", Region: "eu-west-1", ARN: "arn:elsewhere"},
+	}
+	mergeEKSClusters(kubeConfig, existing, "")
+
+	// A cluster added by hand is never pruned
+	kubeConfig.Clusters = append(kubeConfig.Clusters, KubeCluster{Name: "minikube"})
+	kubeConfig.Contexts = append(kubeConfig.Contexts, KubeContext{Name: "minikube", Context: KubeContextConfig{Cluster: "minikube", User: "minikube"}})
+
+	// "main" was renamed, "gone" was deleted, eu-west-1 was not searched
+	found := []EKSCluster{{Name: "main", Region: "us-east-1", ARN: "arn:main", Context: "prod"}}
+	mergeEKSClusters(kubeConfig, found, "")
+
+	completed := []eksDiscoveryJob{{Region: "us-east-1"}}
+	pruned := pruneStaleEKSEntries(kubeConfig, found, completed)
+
+	if !reflect.DeepEqual(pruned, []string{"gone-us-east-1", "main-us-east-1"}) {
+		t.Errorf("Unexpected pruned clusters: %v", pruned)
+	}
+
+	var remaining []string
+	for _, kubeContext := range kubeConfig.Contexts {
+		remaining = append(remaining, kubeContext.Name)
+	}
+	if !reflect.DeepEqual(remaining, []string{"elsewhere-eu-west-1", "minikube", "prod"}) {
+		t.Errorf("Unexpected remaining contexts: %v", remaining)
+	}
+	if len(kubeConfig.Users) != 2 {
+		t.Errorf("Expected 2 remaining users, got %d", len(kubeConfig.Users))
+	}
+	if kubeConfig.CurrentContext != "" {
+		t.Errorf("Current context of a pruned cluster should be cleared, got %s", kubeConfig.CurrentContext)
+	}
+}
+
+func TestKubeConfigPreservesUnknownFields(t *testing.T) {
+	original := `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+    insecure-skip-tls-verify: true
+    proxy-url: http://proxy:3128
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev
+    extensions:
+    - name: team
+      extension: platform
+users:
+- name: dev
+  user:
+    token: secret-token
+- name: oidc
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: kubelogin
+      args: [get-token]
+      interactiveMode: IfAvailable
+extensions:
+- name: tooling
+  extension: {owner: sre}
+`
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+
+	kubeConfig, err := loadKubeConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load kubeconfig: %v", err)
+	}
+	if err := saveKubeConfig(kubeConfig, path); err != nil {
+		t.Fatalf("Failed to save kubeconfig: %v", err)
+	}
+
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read kubeconfig: %v", err)
+	}
+
+	var want, got map[string]any
+	if err := yaml.Unmarshal([]byte(original), &want); err != nil {
+		t.Fatalf("Failed to parse original: %v", err)
+	}
+	if err := yaml.Unmarshal(saved, &got); err != nil {
+		t.Fatalf("Failed to parse saved kubeconfig: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Kubeconfig changed on round trip:\n%s", saved)
+	}
+}
+
+func TestUpdateKubeConfigKeepsUnselectedClusters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	discovered := []EKSCluster{
+		{Name: "main", Region: "us-east-1", ARN: "arn:main"},
+		{Name: "batch", Region: "us-east-1", ARN: "arn:batch"},
+	}
+	completed := []eksDiscoveryJob{{Region: "us-east-1"}}
+
+	if err := updateKubeConfig(discovered, kubeConfigUpdateOptions{Path: path, Completed: completed}); err != nil {
+		t.Fatalf("updateKubeConfig failed: %v", err)
+	}
+
+	// Only "batch" is selected; pruning must not treat "main" as stale
+	selected := filterEKSClusters(discovered, []string{"batch-us-east-1"})
+	if len(selected) != 1 || selected[0].Name != "batch" {
+		t.Fatalf("Unexpected selected clusters: %v", selected)
+	}
+
+	err := updateKubeConfig(selected, kubeConfigUpdateOptions{Path: path, Prune: true, Completed: completed, Discovered: discovered})
+	if err != nil {
+		t.Fatalf("updateKubeConfig failed: %v", err)
+	}
+
+	kubeConfig, err := loadKubeConfig(path)
+	if err != nil {
+		t.Fatalf("loadKubeConfig failed: %v", err)
+	}
+
+	var contexts []string
+	for _, kubeContext := range kubeConfig.Contexts {
+		contexts = append(contexts, kubeContext.Name)
+	}
+	if !reflect.DeepEqual(contexts, []string{"batch-us-east-1", "main-us-east-1"}) {
+		t.Errorf("Unexpected contexts: %v", contexts)
+	}
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"synacklab/pkg/fuzzy"
+	"synacklab/pkg/state"
+)
+
+var (
+	eksNsContext string
+	eksNsList    bool
+	eksNsShell   bool
+	eksNsKube    string
+)
+
+var eksNsCmd = &cobra.Command{
+	Use:   "eks-ns [namespace|-]",
+	Short: "Switch Kubernetes namespace interactively",
+	Long: `Switch the namespace of a Kubernetes context using an interactive fuzzy finder.
+
+Namespaces are listed from the cluster through the Kubernetes API, using the
+credentials of the context's kubeconfig user. The selected namespace is written
+into the context, in the kubeconfig file that defines it. Pass a namespace to switch directly, or '-' to switch back to
+the previous namespace.
+
+With --shell, the namespace is only changed for the current shell: the context
+is copied to a per-shell kubeconfig in ~/.synacklab/kube and an export statement
+that puts it in front of KUBECONFIG is printed for eval. 'synacklab shell-init' installs a shell function that does
+this automatically.
+
+Examples:
+  synacklab eks-ns
+  synacklab eks-ns kube-system
+  synacklab eks-ns -
+  synacklab eks-ns --context prod-cluster --list
+  eval "$(synacklab eks-ns --shell monitoring)"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEKSNs,
+}
+
+func init() {
+	eksNsCmd.Flags().StringVar(&eksNsContext, "context", "", "Context to change (defaults to the current context)")
+	eksNsCmd.Flags().BoolVarP(&eksNsList, "list", "l", false, "List namespaces without switching")
+	eksNsCmd.Flags().BoolVar(&eksNsShell, "shell", false, "Print 'export KUBECONFIG=...' for eval instead of changing the shared kubeconfig")
+	eksNsCmd.Flags().StringVar(&eksNsKube, "kubeconfig", "", "Path to the kubeconfig file to use instead of KUBECONFIG or ~/.kube/config")
+}
+
+func runEKSNs(_ *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// In shell mode stdout is consumed by eval, so progress output goes to stderr
+	out := progressOutput(eksNsShell)
+
+	paths, err := kubeConfigPaths(eksNsKube)
+	if err != nil {
+		return err
+	}
+
+	files, err := loadKubeConfigFiles(paths)
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	kubeConfig := files.Merged()
+
+	contextName := eksNsContext
+	if contextName == "" {
+		contextName = kubeConfig.CurrentContext
+	}
+	if contextName == "" {
+		return fmt.Errorf("no current context set: use --context or run 'synacklab auth eks-ctx'")
+	}
+
+	kubeContext, err := findKubeContext(kubeConfig, contextName)
+	if err != nil {
+		return err
+	}
+
+	currentNamespace := kubeContext.Context.Namespace
+	if currentNamespace == "" {
+		currentNamespace = "default"
+	}
+
+	store := loadPickerState(out)
+
+	var namespace string
+	switch {
+	case len(args) == 1 && args[0] == "-" && !eksNsList:
+		namespace, err = previousSelection(store, state.PickerKubeNamespace, "namespace")
+		if err != nil {
+			return err
+		}
+	case len(args) == 1 && !eksNsList:
+		namespace = args[0]
+	default:
+		fmt.Fprintf(out, "🔍 Loading namespaces for context %s...\n", contextName)
+
+		client, err := newKubeClient(ctx, kubeConfig, contextName)
+		if err != nil {
+			return err
+		}
+
+		namespaces, err := client.listNamespaces(ctx)
+		if err != nil {
+			return err
+		}
+
+		if eksNsList {
+			return displayNamespaces(out, namespaces, currentNamespace)
+		}
+
+		if len(namespaces) == 0 {
+			fmt.Fprintln(out, "❌ No namespaces found in cluster")
+			return nil
+		}
+
+		namespace, err = selectNamespaceWithFuzzyFinder(store, namespaces, currentNamespace)
+		if err != nil {
+			return fmt.Errorf("failed to select namespace: %w", err)
+		}
+	}
+
+	// Remember the namespace being replaced so that '-' can switch back to it
+	store.Observe(state.PickerKubeNamespace, currentNamespace)
+
+	if eksNsShell {
+		// Override the context in a per-shell kubeconfig, pinning the current context
+		shellConfig, shellConfigPath := loadShellKubeConfig(eksNsKube == "")
+		if shellConfig.CurrentContext == "" {
+			shellConfig.CurrentContext = kubeConfig.CurrentContext
+		}
+		if err := overrideContextNamespace(shellConfig, kubeConfig, contextName, namespace); err != nil {
+			return err
+		}
+
+		if shellConfigPath, err = writeShellKubeConfig(shellConfig, shellConfigPath); err != nil {
+			return err
+		}
+
+		fmt.Println(formatShellExport("KUBECONFIG", shellKubeConfigEnv(shellConfigPath, sharedKubeConfigPaths(paths))))
+	} else {
+		// Like kubectl, change the context in the file that defines it
+		path, _ := files.ContextFile(contextName)
+		if err := setContextNamespace(files.File(path), contextName, namespace); err != nil {
+			return err
+		}
+		if err := files.Save(path); err != nil {
+			return fmt.Errorf("failed to save kubeconfig: %w", err)
+		}
+	}
+
+	recordSelection(out, store, state.PickerKubeNamespace, namespace)
+
+	fmt.Fprintf(out, "✅ Successfully switched context %s to namespace: %s\n", contextName, namespace)
+	return nil
+}
+
+// displayNamespaces prints the namespaces of a cluster, marking the current one
+func displayNamespaces(out io.Writer, namespaces []string, current string) error {
+	fmt.Fprintln(out, "\n📋 Available namespaces:")
+	for _, namespace := range namespaces {
+		marker := " "
+		if namespace == current {
+			marker = "*"
+		}
+		fmt.Fprintf(out, "%s %s\n", marker, namespace)
+	}
+	return nil
+}
+
+// selectNamespaceWithFuzzyFinder lets the user pick a namespace
+func selectNamespaceWithFuzzyFinder(store *state.Store, namespaces []string, current string) (string, error) {
+	finder := fuzzy.NewFzf("🔍 Select namespace:")
+
+	var options []fuzzy.Option
+	for _, namespace := range namespaces {
+		option := fuzzy.Option{Value: namespace}
+		if namespace == current {
+			option.Description = "(current)"
+		}
+		options = append(options, option)
+	}
+
+	// Favourites and recently used namespaces first
+	options = rankOptions(store, state.PickerKubeNamespace, options)
+
+	if err := finder.SetOptions(options); err != nil {
+		return "", fmt.Errorf("failed to set finder options: %w", err)
+	}
+
+	selected, err := finder.Select()
+	if err != nil {
+		return "", fmt.Errorf("namespace selection failed: %w", err)
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"gopkg.in/ini.v1"
+
+	"synacklab/pkg/config"
+)
+
+// maxEKSDiscoveryConcurrency bounds the number of concurrent AWS calls made during cluster discovery
+const maxEKSDiscoveryConcurrency = 16
+
+// maxEKSDescribeConcurrency bounds concurrent DescribeCluster calls within one region
+const maxEKSDescribeConcurrency = 8
+
+// eksDiscoveryTarget is a set of AWS credentials to discover clusters with
+type eksDiscoveryTarget struct {
+	Profile string
+	Config  aws.Config
+}
+
+// eksDiscoveryJob is a single account and region to search for clusters
+type eksDiscoveryJob struct {
+	Target eksDiscoveryTarget
+	Region string
+}
+
+// selectEKSProfiles returns the profiles to discover clusters with. With all set,
+// every synced SSO profile is used, one per account, optionally filtered by role name.
+func selectEKSProfiles(profiles []awsProfileInfo, all bool, names []string, role string) ([]string, error) {
+	if !all {
+		known := make(map[string]bool, len(profiles))
+		for _, profile := range profiles {
+			known[profile.name] = true
+		}
+
+		var selected []string
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !known[name] {
+				return nil, fmt.Errorf("profile '%s' not found in AWS config", name)
+			}
+			selected = append(selected, name)
+		}
+		return selected, nil
+	}
+
+	var selected []string
+	seenAccounts := make(map[string]bool)
+	for _, profile := range profiles {
+		// Only SSO profiles map directly to an account
+		if profile.accountID == "" || profile.roleName == "" || profile.roleARN != "" {
+			continue
+		}
+		if role != "" && !strings.EqualFold(profile.roleName, role) {
+			continue
+		}
+		if seenAccounts[profile.accountID] {
+			continue
+		}
+
+		seenAccounts[profile.accountID] = true
+		selected = append(selected, profile.name)
+	}
+
+	return selected, nil
+}
+
+// buildEKSProfileTargets obtains credentials for each profile concurrently.
+// Profiles whose credentials cannot be obtained are reported and skipped.
+func buildEKSProfileTargets(ctx context.Context, provider roleCredentialsProvider, cfg *ini.File, appConfig *config.Config, profiles []string) []eksDiscoveryTarget {
+	results := make([]*eksDiscoveryTarget, len(profiles))
+	sem := make(chan struct{}, maxEKSDiscoveryConcurrency)
+
+	var wg sync.WaitGroup
+	for i, profile := range profiles {
+		wg.Add(1)
+		go func(i int, profile string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			creds, err := resolveProfileCredentials(ctx, provider, cfg, appConfig, profile)
+			if err != nil {
+				fmt.Printf("⚠️  Warning: Skipping profile %s: %v\n", profile, err)
+				return
+			}
+
+			region := resolveProfileRegion(cfg, appConfig, profile)
+			if region == "" {
+				region = "us-east-1"
+			}
+
+			results[i] = &eksDiscoveryTarget{
+				Profile: profile,
+				Config: aws.Config{
+					Region:      region,
+					Credentials: aws.NewCredentialsCache(creds.CredentialsProvider()),
+				},
+			}
+		}(i, profile)
+	}
+	wg.Wait()
+
+	var targets []eksDiscoveryTarget
+	for _, target := range results {
+		if target != nil {
+			targets = append(targets, *target)
+		}
+	}
+
+	return targets
+}
+
+// planEKSDiscovery determines the regions to search for every target concurrently
+func planEKSDiscovery(targets []eksDiscoveryTarget) []eksDiscoveryJob {
+	results := make([][]eksDiscoveryJob, len(targets))
+	sem := make(chan struct{}, maxEKSDiscoveryConcurrency)
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target eksDiscoveryTarget) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			regions, err := getRegionsToSearch(target.Config)
+			if err != nil {
+				fmt.Printf("⚠️  Warning: Failed to determine regions for %s: %v\n", describeEKSTarget(target), err)
+				return
+			}
+
+			for _, region := range regions {
+				results[i] = append(results[i], eksDiscoveryJob{Target: target, Region: region})
+			}
+		}(i, target)
+	}
+	wg.Wait()
+
+	var jobs []eksDiscoveryJob
+	for _, targetJobs := range results {
+		jobs = append(jobs, targetJobs...)
+	}
+
+	return jobs
+}
+
+// discoverEKSClustersConcurrently runs the discovery jobs in parallel and merges
+// the results in a stable order. It also returns the jobs that completed, so
+// that callers can tell an empty region from one that could not be searched.
+func discoverEKSClustersConcurrently(jobs []eksDiscoveryJob, discover func(aws.Config, string) ([]EKSCluster, error)) ([]EKSCluster, []eksDiscoveryJob) {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		clusters  []EKSCluster
+		completed []eksDiscoveryJob
+	)
+	sem := make(chan struct{}, maxEKSDiscoveryConcurrency)
+
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job eksDiscoveryJob) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			found, err := discover(job.Target.Config, job.Region)
+			if err != nil {
+				fmt.Printf("⚠️  Warning: Failed to discover clusters for %s in region %s: %v\n", describeEKSTarget(job.Target), job.Region, err)
+				return
+			}
+
+			for i := range found {
+				found[i].Profile = job.Target.Profile
+			}
+
+			mu.Lock()
+			clusters = append(clusters, found...)
+			completed = append(completed, job)
+			mu.Unlock()
+		}(job)
+	}
+	wg.Wait()
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Profile != clusters[j].Profile {
+			return clusters[i].Profile < clusters[j].Profile
+		}
+		if clusters[i].Region != clusters[j].Region {
+			return clusters[i].Region < clusters[j].Region
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	return clusters, completed
+}
+
+// describeEKSTarget returns a short label for a discovery target used in messages
+func describeEKSTarget(target eksDiscoveryTarget) string {
+	if target.Profile == "" {
+		return "default credentials"
+	}
+	return "profile " + target.Profile
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"synacklab/pkg/config"
+)
+
+func TestSelectEKSProfiles(t *testing.T) {
+	profiles := []awsProfileInfo{
+		{name: "dev-admin", accountID: "111111111111", roleName: "AdministratorAccess"},
+		{name: "dev-readonly", accountID: "111111111111", roleName: "ReadOnlyAccess"},
+		{name: "prod-readonly", accountID: "222222222222", roleName: "ReadOnlyAccess"},
+		{name: "prod-deploy", sourceProfile: "prod-readonly", roleARN: "arn:aws:iam::222222222222:role/Deploy"},
+	}
+
+	tests := []struct {
+		name     string
+		all      bool
+		names    []string
+		role     string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "all profiles, one per account",
+			all:      true,
+			expected: []string{"dev-admin", "prod-readonly"},
+		},
+		{
+			name:     "all profiles filtered by role",
+			all:      true,
+			role:     "readonlyaccess",
+			expected: []string{"dev-readonly", "prod-readonly"},
+		},
+		{
+			name:     "explicit profiles including chained",
+			names:    []string{"dev-admin", " prod-deploy"},
+			expected: []string{"dev-admin", "prod-deploy"},
+		},
+		{
+			name:    "unknown profile",
+			names:   []string{"missing"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectEKSProfiles(profiles, tt.all, tt.names, tt.role)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuildEKSProfileTargets(t *testing.T) {
+	cfg := createTestAWSConfigForCreds(t)
+	appConfig := &config.Config{}
+	provider := &mockRoleCredentialsProvider{}
+
+	targets := buildEKSProfileTargets(context.Background(), provider, cfg, appConfig, []string{"shared-admin", "missing", "legacy-deploy"})
+
+	if len(targets) != 2 {
+		t.Fatalf("Expected 2 targets, got %d", len(targets))
+	}
+	if targets[0].Profile != "shared-admin" || targets[1].Profile != "legacy-deploy" {
+		t.Errorf("Unexpected target order: %s, %s", targets[0].Profile, targets[1].Profile)
+	}
+	if targets[0].Config.Region != "us-east-1" {
+		t.Errorf("Expected region us-east-1, got %s", targets[0].Config.Region)
+	}
+
+	creds, err := targets[1].Config.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to retrieve credentials: %v", err)
+	}
+	if creds.AccessKeyID != "SSO-111111111111>arn:aws:iam::333333333333:role/Legacy" {
+		t.Errorf("Unexpected chained credentials: %s", creds.AccessKeyID)
+	}
+}
+
+func TestDiscoverEKSClustersConcurrently(t *testing.T) {
+	originalRegion := eksRegion
+	eksRegion = "eu-west-1"
+	defer func() { eksRegion = originalRegion }()
+
+	targets := []eksDiscoveryTarget{
+		{Profile: "prod", Config: aws.Config{Region: "us-east-1"}},
+		{Profile: "dev", Config: aws.Config{Region: "us-east-1"}},
+	}
+
+	discover := func(_ aws.Config, region string) ([]EKSCluster, error) {
+		return []EKSCluster{{Name: "main", Region: region}, {Name: "batch", Region: region}}, nil
+	}
+
+	jobs := planEKSDiscovery(targets)
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 discovery jobs, got %d", len(jobs))
+	}
+
+	clusters, completed := discoverEKSClustersConcurrently(jobs, discover)
+	if len(completed) != len(jobs) {
+		t.Errorf("Expected all %d jobs to complete, got %d", len(jobs), len(completed))
+	}
+
+	var got []string
+	for _, cluster := range clusters {
+		got = append(got, eksContextName(cluster))
+	}
+
+	expected := []string{
+		"dev-batch-eu-west-1",
+		"dev-main-eu-west-1",
+		"prod-batch-eu-west-1",
+		"prod-main-eu-west-1",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestEKSExecConfig(t *testing.T) {
+	withoutProfile := eksExecConfig(EKSCluster{Name: "main", Region: "us-east-1"})
+	expected := []string{"eks", "token", "--cluster", "main", "--region", "us-east-1"}
+	if withoutProfile.Command != "synacklab" || !reflect.DeepEqual(withoutProfile.Args, expected) {
+		t.Errorf("Unexpected exec config without profile: %s %v", withoutProfile.Command, withoutProfile.Args)
+	}
+	if len(withoutProfile.Env) != 0 {
+		t.Errorf("Expected no env without profile, got %v", withoutProfile.Env)
+	}
+	if eksContextName(EKSCluster{Name: "main", Region: "us-east-1"}) != "main-us-east-1" {
+		t.Error("Context name without profile should keep the cluster-region format")
+	}
+
+	withProfile := eksExecConfig(EKSCluster{Name: "main", Region: "us-east-1", Profile: "prod-admin"})
+	expected = append(expected, "--profile", "prod-admin")
+	if !reflect.DeepEqual(withProfile.Args, expected) {
+		t.Errorf("Expected args %v, got %v", expected, withProfile.Args)
+	}
+}
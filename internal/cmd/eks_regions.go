@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// fallbackEKSRegions is searched when the enabled regions cannot be listed
+var fallbackEKSRegions = []string{
+	"us-east-1", "us-east-2", "us-west-1", "us-west-2",
+	"ca-central-1", "sa-east-1",
+	"eu-west-1", "eu-west-2", "eu-west-3", "eu-central-1", "eu-north-1",
+	"ap-south-1", "ap-southeast-1", "ap-southeast-2", "ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+}
+
+// describeRegionsTimeout bounds the region lookup so that discovery can fall back quickly
+const describeRegionsTimeout = 10 * time.Second
+
+// regionDescriber is the part of the EC2 API used to list regions
+type regionDescriber interface {
+	DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error)
+}
+
+// describeEnabledRegions lists the regions enabled for the account behind cfg.
+// The EC2 client resolves the endpoint, so other partitions, FIPS endpoints and
+// custom endpoints are honoured.
+func describeEnabledRegions(ctx context.Context, cfg aws.Config) ([]string, error) {
+	if cfg.Credentials == nil {
+		return nil, fmt.Errorf("no AWS credentials available")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, describeRegionsTimeout)
+	defer cancel()
+
+	client := ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+		if o.Region == "" {
+			o.Region = "us-east-1"
+		}
+	})
+
+	return listEnabledRegions(ctx, client)
+}
+
+// listEnabledRegions returns the sorted names of the regions enabled for the account
+func listEnabledRegions(ctx context.Context, client regionDescriber) ([]string, error) {
+	output, err := client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("DescribeRegions failed: %w", err)
+	}
+
+	var regions []string
+	for _, region := range output.Regions {
+		if name := aws.ToString(region.RegionName); name != "" {
+			regions = append(regions, name)
+		}
+	}
+
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("DescribeRegions returned no regions")
+	}
+
+	sort.Strings(regions)
+	return regions, nil
+}
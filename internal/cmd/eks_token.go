@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/spf13/cobra"
+	"gopkg.in/ini.v1"
+
+	"synacklab/internal/auth"
+)
+
+const (
+	// eksTokenPrefix is the prefix expected by the EKS authenticator
+	eksTokenPrefix = "k8s-aws-v1."
+	// eksClusterIDHeader binds the presigned request to a cluster
+	eksClusterIDHeader = "x-k8s-aws-id"
+	// eksTokenPresignExpiry is the validity requested for the presigned URL
+	eksTokenPresignExpiry = "60"
+	// eksTokenLifetime is how long EKS accepts a token, less a minute of clock skew
+	eksTokenLifetime = 14 * time.Minute
+	// eksTokenRefreshMargin avoids handing out tokens that are about to expire
+	eksTokenRefreshMargin = time.Minute
+	// execCredentialAPIVersion is the client authentication API version used in kubeconfig
+	execCredentialAPIVersion = "client.authentication.k8s.io/v1beta1"
+)
+
+var (
+	eksTokenCluster string
+	eksTokenRegion  string
+	eksTokenProfile string
+	eksTokenNoCache bool
+)
+
+var eksTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Print an EKS authentication token for kubectl",
+	Long: `Print an authentication token for an EKS cluster in the ExecCredential format
+used by kubectl exec credential plugins.
+
+The token is a presigned STS GetCallerIdentity request signed with role
+credentials obtained through your AWS SSO session, so the AWS CLI is not
+needed. Tokens are cached in ~/.synacklab until shortly before they expire.
+
+If --profile is not given, AWS_PROFILE or the profile selected with
+'synacklab auth aws-ctx' is used. Without any of them the default AWS
+credentials are used, such as environment variables or an instance role.
+
+'synacklab auth eks-config' configures kubeconfig users to run this command.
+
+Examples:
+  synacklab eks token --cluster main --region eu-west-1 --profile prod-admin`,
+	RunE: runEKSToken,
+}
+
+func init() {
+	eksTokenCmd.Flags().StringVar(&eksTokenCluster, "cluster", "", "EKS cluster name")
+	eksTokenCmd.Flags().StringVarP(&eksTokenRegion, "region", "r", "", "AWS region of the cluster (defaults to the profile region)")
+	eksTokenCmd.Flags().StringVarP(&eksTokenProfile, "profile", "p", "", "AWS profile to authenticate with")
+	eksTokenCmd.Flags().BoolVar(&eksTokenNoCache, "no-cache", false, "Always generate a new token")
+	_ = eksTokenCmd.MarkFlagRequired("cluster")
+}
+
+// execCredential is the client.authentication.k8s.io ExecCredential object
+type execCredential struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Spec       map[string]any        `json:"spec"`
+	Status     *execCredentialStatus `json:"status"`
+}
+
+type execCredentialStatus struct {
+	ExpirationTimestamp string `json:"expirationTimestamp"`
+	Token               string `json:"token"`
+}
+
+// eksCachedToken is a token stored in the token cache
+type eksCachedToken struct {
+	Token      string    `json:"token"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// eksTokenCache stores generated tokens keyed by profile, region and cluster
+type eksTokenCache struct {
+	Tokens map[string]eksCachedToken `json:"tokens"`
+}
+
+func runEKSToken(_ *cobra.Command, _ []string) error {
+	ctx := context.Background()
+
+	cfg, err := loadAWSConfigForCreds()
+	if err != nil {
+		return err
+	}
+
+	profileName := selectEKSTokenProfile(cfg)
+	if profileName == "" {
+		// Clusters discovered with default credentials, like 'aws eks get-token'
+		token, err := eksTokenFromDefaultCredentials(ctx, eksTokenRegion, eksTokenCluster)
+		if err != nil {
+			return err
+		}
+		return printExecCredential(token)
+	}
+
+	appConfig, err := loadCredsAppConfig()
+	if err != nil {
+		return err
+	}
+
+	if _, err := lookupAWSProfile(cfg, appConfig, profileName); err != nil {
+		return err
+	}
+
+	region := eksTokenRegion
+	if region == "" {
+		region = resolveProfileRegion(cfg, appConfig, profileName)
+	}
+	if region == "" {
+		return fmt.Errorf("no region configured for profile '%s': use --region", profileName)
+	}
+
+	now := time.Now()
+	key := eksTokenCacheKey(profileName, region, eksTokenCluster)
+
+	cachePath, err := getEKSTokenCachePath()
+	if err != nil {
+		return err
+	}
+
+	cache := loadEKSTokenCache(cachePath)
+	if !eksTokenNoCache {
+		if cached, ok := cache.lookup(key, now); ok {
+			return printExecCredential(cached)
+		}
+	}
+
+	authManager, err := auth.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize authentication manager: %w", err)
+	}
+	// Stdout carries the ExecCredential kubectl parses
+	authManager.SetOutput(os.Stderr)
+
+	if err := ensureAWSAuthenticated(ctx, authManager); err != nil {
+		return err
+	}
+
+	creds, err := resolveProfileCredentials(ctx, authManager, cfg, appConfig, profileName)
+	if err != nil {
+		return reportCredentialsError(err)
+	}
+
+	token, err := generateEKSToken(ctx, creds.CredentialsProvider(), region, eksTokenCluster)
+	if err != nil {
+		return err
+	}
+
+	// The presigned request is only valid while the signing credentials are
+	cached := eksCachedToken{Token: token, Expiration: now.Add(eksTokenLifetime)}
+	if !creds.Expiration.IsZero() && creds.Expiration.Before(cached.Expiration) {
+		cached.Expiration = creds.Expiration
+	}
+
+	cache.store(key, cached, now)
+	if err := saveEKSTokenCache(cachePath, cache); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to cache EKS token: %v\n", err)
+	}
+
+	return printExecCredential(cached)
+}
+
+// selectEKSTokenProfile returns the profile given with --profile, AWS_PROFILE
+// or the profile selected with aws-ctx, or "" when the default credentials
+// are to be used
+func selectEKSTokenProfile(cfg *ini.File) string {
+	profileName := eksTokenProfile
+	if profileName == "" {
+		profileName = os.Getenv("AWS_PROFILE")
+	}
+	if profileName == "" {
+		profileName = matchDefaultProfile(cfg)
+	}
+	if profileName == "default" {
+		return ""
+	}
+	return profileName
+}
+
+// eksTokenFromDefaultCredentials generates a token signed with the default AWS
+// credential chain: environment variables, the default profile, web identity or
+// the instance role. These tokens are not cached, as the credentials may change
+// between calls without a profile name to tell them apart.
+func eksTokenFromDefaultCredentials(ctx context.Context, region, cluster string) (eksCachedToken, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return eksCachedToken{}, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	if region == "" {
+		region = awsCfg.Region
+	}
+	if region == "" {
+		return eksCachedToken{}, fmt.Errorf("no AWS region configured: use --region")
+	}
+
+	creds, err := awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return eksCachedToken{}, fmt.Errorf("no AWS credentials found: use --profile, run 'synacklab auth aws-ctx' or configure default credentials: %w", err)
+	}
+
+	token, err := generateEKSToken(ctx, awsCfg.Credentials, region, cluster)
+	if err != nil {
+		return eksCachedToken{}, err
+	}
+
+	// The presigned request is only valid while the signing credentials are
+	cached := eksCachedToken{Token: token, Expiration: time.Now().Add(eksTokenLifetime)}
+	if creds.CanExpire && creds.Expires.Before(cached.Expiration) {
+		cached.Expiration = creds.Expires
+	}
+
+	return cached, nil
+}
+
+// generateEKSToken presigns an STS GetCallerIdentity request bound to a cluster,
+// which the EKS authenticator accepts as a bearer token
+func generateEKSToken(ctx context.Context, credentials aws.CredentialsProvider, region, cluster string) (string, error) {
+	client := sts.New(sts.Options{
+		Region:      region,
+		Credentials: credentials,
+	})
+
+	presigned, err := sts.NewPresignClient(client).PresignGetCallerIdentity(ctx, &sts.GetCallerIdentityInput{},
+		func(opts *sts.PresignOptions) {
+			opts.ClientOptions = append(opts.ClientOptions, func(o *sts.Options) {
+				o.APIOptions = append(o.APIOptions,
+					smithyhttp.SetHeaderValue(eksClusterIDHeader, cluster),
+					smithyhttp.SetHeaderValue("X-Amz-Expires", eksTokenPresignExpiry),
+				)
+			})
+		})
+	if err != nil {
+		return "", fmt.Errorf("failed to presign STS request: %w", err)
+	}
+
+	return eksTokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(presigned.URL)), nil
+}
+
+// printExecCredential writes a token to stdout in the ExecCredential format
+func printExecCredential(token eksCachedToken) error {
+	data, err := json.Marshal(execCredential{
+		APIVersion: execCredentialAPIVersion,
+		Kind:       "ExecCredential",
+		Spec:       map[string]any{},
+		Status: &execCredentialStatus{
+			ExpirationTimestamp: token.Expiration.UTC().Format(time.RFC3339),
+			Token:               token.Token,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to format exec credential: %w", err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+// eksTokenCacheKey identifies a cached token
+func eksTokenCacheKey(profile, region, cluster string) string {
+	return profile + "|" + region + "|" + cluster
+}
+
+// getEKSTokenCachePath returns the path of the EKS token cache
+func getEKSTokenCachePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".synacklab", "eks_tokens.json"), nil
+}
+
+// loadEKSTokenCache reads the token cache. A missing or corrupt file yields an empty cache.
+func loadEKSTokenCache(path string) *eksTokenCache {
+	cache := &eksTokenCache{Tokens: make(map[string]eksCachedToken)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+
+	if err := json.Unmarshal(data, cache); err != nil || cache.Tokens == nil {
+		return &eksTokenCache{Tokens: make(map[string]eksCachedToken)}
+	}
+
+	return cache
+}
+
+// saveEKSTokenCache writes the token cache atomically, since kubectl may run
+// several plugins at once
+func saveEKSTokenCache(path string, cache *eksTokenCache) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal token cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".eks_tokens-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name()) // Already renamed on success
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+
+	return nil
+}
+
+// lookup returns a cached token that is still valid for at least the refresh margin
+func (c *eksTokenCache) lookup(key string, now time.Time) (eksCachedToken, bool) {
+	token, ok := c.Tokens[key]
+	if !ok || !now.Add(eksTokenRefreshMargin).Before(token.Expiration) {
+		return eksCachedToken{}, false
+	}
+	return token, true
+}
+
+// store adds a token to the cache and drops expired ones
+func (c *eksTokenCache) store(key string, token eksCachedToken, now time.Time) {
+	for existing, cached := range c.Tokens {
+		if !now.Before(cached.Expiration) {
+			delete(c.Tokens, existing)
+		}
+	}
+	c.Tokens[key] = token
+}
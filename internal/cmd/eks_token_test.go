@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"synacklab/internal/auth"
+)
+
+func TestEKSTokenCommand(t *testing.T) {
+	if eksTokenCmd.Use != "token" {
+		t.Errorf("Expected command use to be 'token', got '%s'", eksTokenCmd.Use)
+	}
+
+	for _, flag := range []string{"cluster", "region", "profile", "no-cache"} {
+		if eksTokenCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("%s flag should be defined", flag)
+		}
+	}
+
+	found := false
+	for _, cmd := range eksCmd.Commands() {
+		if cmd == eksTokenCmd {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("token should be a subcommand of eks")
+	}
+}
+
+func TestGenerateEKSToken(t *testing.T) {
+	creds := &auth.RoleCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+		Expiration:      time.Now().Add(time.Hour),
+	}
+
+	token, err := generateEKSToken(context.Background(), creds.CredentialsProvider(), "eu-west-1", "main")
+	if err != nil {
+		t.Fatalf("generateEKSToken failed: %v", err)
+	}
+
+	if !strings.HasPrefix(token, eksTokenPrefix) {
+		t.Fatalf("Token should start with %s, got %s", eksTokenPrefix, token)
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, eksTokenPrefix))
+	if err != nil {
+		t.Fatalf("Token payload is not base64url: %v", err)
+	}
+
+	presigned, err := url.Parse(string(decoded))
+	if err != nil {
+		t.Fatalf("Token payload is not a URL: %v", err)
+	}
+
+	if presigned.Host != "sts.eu-west-1.amazonaws.com" {
+		t.Errorf("Expected regional STS endpoint, got %s", presigned.Host)
+	}
+
+	query := presigned.Query()
+	if query.Get("Action") != "GetCallerIdentity" {
+		t.Errorf("Expected GetCallerIdentity action, got %s", query.Get("Action"))
+	}
+	if query.Get("X-Amz-Expires") != eksTokenPresignExpiry {
+		t.Errorf("Expected X-Amz-Expires %s, got %s", eksTokenPresignExpiry, query.Get("X-Amz-Expires"))
+	}
+	if !strings.Contains(query.Get("X-Amz-SignedHeaders"), eksClusterIDHeader) {
+		t.Errorf("Cluster header should be signed, got %s", query.Get("X-Amz-SignedHeaders"))
+	}
+	if query.Get("X-Amz-Security-Token") != "session" {
+		t.Error("Session token should be included in the presigned URL")
+	}
+}
+
+func TestEKSTokenCache(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "eks_tokens.json")
+
+	cache := loadEKSTokenCache(path)
+	key := eksTokenCacheKey("prod", "eu-west-1", "main")
+
+	cache.store("stale", eksCachedToken{Token: "old", Expiration: now.Add(-time.Minute)}, now)
+	cache.store(key, eksCachedToken{Token: "fresh", Expiration: now.Add(eksTokenLifetime)}, now)
+
+	if err := saveEKSTokenCache(path, cache); err != nil {
+		t.Fatalf("Failed to save token cache: %v", err)
+	}
+
+	loaded := loadEKSTokenCache(path)
+	if token, ok := loaded.lookup(key, now); !ok || token.Token != "fresh" {
+		t.Errorf("Expected cached token, got %v (%v)", token, ok)
+	}
+
+	// Expired tokens are dropped on the next store
+	loaded.store("other", eksCachedToken{Token: "other", Expiration: now.Add(time.Hour)}, now)
+	if _, ok := loaded.Tokens["stale"]; ok {
+		t.Error("Expired token should be removed from the cache")
+	}
+
+	// Tokens about to expire are not reused
+	if _, ok := loaded.lookup(key, now.Add(eksTokenLifetime-eksTokenRefreshMargin/2)); ok {
+		t.Error("Token within the refresh margin should not be reused")
+	}
+	if _, ok := loaded.lookup("missing", now); ok {
+		t.Error("Unknown key should not be found")
+	}
+}
+
+func TestEKSTokenCacheCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "eks_tokens.json")
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatalf("Failed to write token cache: %v", err)
+	}
+
+	cache := loadEKSTokenCache(path)
+	if cache.Tokens == nil || len(cache.Tokens) != 0 {
+		t.Fatalf("Corrupt cache should load as empty, got %v", cache.Tokens)
+	}
+}
+
+func TestSelectEKSTokenProfile(t *testing.T) {
+	sso, err := ini.Load([]byte(`[default]
+sso_account_id = 111111111111
+sso_role_name = Admin
+
+[profile prod-admin]
+sso_account_id = 111111111111
+sso_role_name = Admin
+`))
+	if err != nil {
+		t.Fatalf("Failed to load AWS config: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		cfg        *ini.File
+		flag       string
+		envProfile string
+		expected   string
+	}{
+		{name: "flag", cfg: ini.Empty(), flag: "staging", envProfile: "dev", expected: "staging"},
+		{name: "AWS_PROFILE", cfg: sso, envProfile: "dev", expected: "dev"},
+		{name: "aws-ctx selection", cfg: sso, expected: "prod-admin"},
+		{name: "no profile", cfg: ini.Empty(), expected: ""},
+		{name: "default profile", cfg: ini.Empty(), envProfile: "default", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eksTokenProfile = tt.flag
+			defer func() { eksTokenProfile = "" }()
+			t.Setenv("AWS_PROFILE", tt.envProfile)
+
+			if actual := selectEKSTokenProfile(tt.cfg); actual != tt.expected {
+				t.Errorf("selectEKSTokenProfile() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEKSTokenFromDefaultCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+	t.Setenv("AWS_REGION", "us-west-2")
+
+	token, err := eksTokenFromDefaultCredentials(context.Background(), "", "main")
+	if err != nil {
+		t.Fatalf("eksTokenFromDefaultCredentials failed: %v", err)
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token.Token, eksTokenPrefix))
+	if err != nil {
+		t.Fatalf("Token payload is not base64url: %v", err)
+	}
+	presigned, err := url.Parse(string(decoded))
+	if err != nil {
+		t.Fatalf("Token payload is not a URL: %v", err)
+	}
+
+	if presigned.Host != "sts.us-west-2.amazonaws.com" {
+		t.Errorf("Expected the region from the environment, got %s", presigned.Host)
+	}
+	if !strings.HasPrefix(presigned.Query().Get("X-Amz-Credential"), "AKIDEXAMPLE/") {
+		t.Errorf("Expected the token to be signed with the environment credentials, got %s", presigned.Query().Get("X-Amz-Credential"))
+	}
+	if token.Expiration.Before(time.Now()) {
+		t.Errorf("Expected a future expiration, got %v", token.Expiration)
+	}
+
+	t.Setenv("AWS_REGION", "")
+	if _, err := eksTokenFromDefaultCredentials(context.Background(), "", "main"); err == nil {
+		t.Error("Expected an error without a region")
+	}
+}
+
+func TestRunEKSTokenAfterSessionExpired(t *testing.T) {
+	setupExpiredSSOSession(t)
+
+	oldProfile, oldCluster := eksTokenProfile, eksTokenCluster
+	eksTokenProfile, eksTokenCluster = "dev", "main"
+	defer func() { eksTokenProfile, eksTokenCluster = oldProfile, oldCluster }()
+
+	stdout, err := captureStdout(t, func() error { return runEKSToken(eksTokenCmd, nil) })
+	if err != nil {
+		t.Fatalf("runEKSToken failed: %v", err)
+	}
+
+	// The sign-in instructions go to stderr, stdout is parsed by kubectl
+	var credential execCredential
+	if err := json.Unmarshal([]byte(stdout), &credential); err != nil {
+		t.Fatalf("stdout is not JSON only: %v\n%s", err, stdout)
+	}
+	if credential.Kind != "ExecCredential" || !strings.HasPrefix(credential.Status.Token, eksTokenPrefix) {
+		t.Errorf("Unexpected exec credential: %+v", credential)
+	}
+}
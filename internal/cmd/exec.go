@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"synacklab/internal/auth"
+	"synacklab/pkg/config"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec [profile] -- <command> [args...]",
+	Short: "Run a command with AWS SSO role credentials",
+	Long: `Run a command with temporary AWS credentials for an SSO profile.
+
+The profile is resolved from ~/.aws/config and role credentials are obtained
+through your AWS SSO session. Chained profiles (source_profile/role_arn) are
+resolved with STS AssumeRole. The command is started with the following
+environment variables set:
+
+  AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN,
+  AWS_REGION, AWS_DEFAULT_REGION, AWS_CREDENTIAL_EXPIRATION
+
+If no profile is given, an interactive fuzzy finder is shown to pick one.
+If no command is given, your $SHELL is started.
+
+Examples:
+  synacklab exec prod-admin -- aws s3 ls
+  synacklab exec -- terraform plan
+  synacklab exec staging-readonly`,
+	RunE: runExec,
+}
+
+// execEnvVars lists the environment variables that are replaced in the child environment
+var execEnvVars = []string{
+	"AWS_ACCESS_KEY_ID",
+	"AWS_SECRET_ACCESS_KEY",
+	"AWS_SESSION_TOKEN",
+	"AWS_SECURITY_TOKEN",
+	"AWS_REGION",
+	"AWS_DEFAULT_REGION",
+	"AWS_PROFILE",
+	"AWS_DEFAULT_PROFILE",
+	"AWS_CREDENTIAL_EXPIRATION",
+	"SYNACKLAB_PROFILE",
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	profileName, command, err := parseExecArgs(args, cmd.ArgsLenAtDash())
+	if err != nil {
+		return err
+	}
+	if len(command) == 0 {
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			return fmt.Errorf("no command specified and $SHELL is not set")
+		}
+		command = []string{shell}
+	}
+
+	appConfig, err := loadCredsAppConfig()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadAWSConfigForCreds()
+	if err != nil {
+		return err
+	}
+
+	// Pick a profile interactively if none was given
+	if profileName == "" {
+		options := buildAWSProfileOptions(listAWSProfiles(cfg, appConfig))
+		if len(options) == 0 {
+			fmt.Fprintln(os.Stderr, "💡 Run 'synacklab auth sync' first to create profiles from AWS SSO")
+			return fmt.Errorf("no profiles found in AWS config")
+		}
+
+		profileName, err = selectAWSProfile("🔍 Select AWS profile to run with:", options)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := lookupAWSProfile(cfg, appConfig, profileName); err != nil {
+		return err
+	}
+
+	authManager, err := auth.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize authentication manager: %w", err)
+	}
+	// Stdout belongs to the command being run
+	authManager.SetOutput(os.Stderr)
+
+	if err := ensureAWSAuthenticated(ctx, authManager); err != nil {
+		return err
+	}
+
+	creds, err := resolveProfileCredentials(ctx, authManager, cfg, appConfig, profileName)
+	if err != nil {
+		return reportCredentialsError(err)
+	}
+
+	region := resolveProfileRegion(cfg, appConfig, profileName)
+	env := buildExecEnv(os.Environ(), profileName, region, creds)
+
+	if err := runExecChild(command, env); err != nil {
+		// Propagate the exit code of the child process
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		return err
+	}
+
+	return nil
+}
+
+// parseExecArgs splits command-line arguments into the profile name and the command to run
+func parseExecArgs(args []string, dashIndex int) (string, []string, error) {
+	switch {
+	case dashIndex == 0:
+		return "", args, nil
+	case dashIndex > 1:
+		return "", nil, fmt.Errorf("unexpected arguments before --: %s (only the profile name may precede the command)", strings.Join(args[1:dashIndex], " "))
+	case dashIndex == 1:
+		return args[0], args[dashIndex:], nil
+	case len(args) > 0:
+		return args[0], args[1:], nil
+	default:
+		return "", nil, nil
+	}
+}
+
+// buildExecEnv returns the child environment with role credentials injected
+func buildExecEnv(environ []string, profileName, region string, creds *auth.RoleCredentials) []string {
+	var env []string
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if isExecEnvVar(name) {
+			continue
+		}
+		env = append(env, entry)
+	}
+
+	env = append(env,
+		"AWS_ACCESS_KEY_ID="+creds.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY="+creds.SecretAccessKey,
+		"AWS_SESSION_TOKEN="+creds.SessionToken,
+		"AWS_CREDENTIAL_EXPIRATION="+creds.Expiration.UTC().Format(time.RFC3339),
+		"SYNACKLAB_PROFILE="+profileName,
+	)
+
+	if region != "" {
+		env = append(env,
+			"AWS_REGION="+region,
+			"AWS_DEFAULT_REGION="+region,
+		)
+	}
+
+	return env
+}
+
+// isExecEnvVar reports whether an environment variable is managed by exec
+func isExecEnvVar(name string) bool {
+	for _, managed := range execEnvVars {
+		if name == managed {
+			return true
+		}
+	}
+	return false
+}
+
+// runExecChild runs the command with the given environment, attached to the current terminal
+func runExecChild(command []string, env []string) error {
+	path, err := exec.LookPath(command[0])
+	if err != nil {
+		return fmt.Errorf("command not found: %s", command[0])
+	}
+
+	child := exec.Command(path, command[1:]...)
+	child.Env = env
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+
+	return child.Run()
+}
+
+// ensureAWSAuthenticated checks the AWS SSO session and starts authentication if needed
+func ensureAWSAuthenticated(ctx context.Context, authManager auth.Manager) error {
+	isAuthenticated, err := authManager.IsAuthenticated(ctx)
+	if err != nil {
+		var authErr *auth.Error
+		if errors.As(err, &authErr) {
+			fmt.Fprintf(os.Stderr, "❌ %s%s\n", authErr.Message, authErr.GetTroubleshootingMessage())
+			return fmt.Errorf("authentication check failed")
+		}
+		return fmt.Errorf("failed to check authentication status: %w", err)
+	}
+
+	if isAuthenticated {
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, "🔐 You are not authenticated to AWS SSO")
+	fmt.Fprintln(os.Stderr, "🚀 Starting automatic authentication...")
+
+	appConfig, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if _, err := authManager.Authenticate(ctx, appConfig); err != nil {
+		var authErr *auth.Error
+		if errors.As(err, &authErr) {
+			fmt.Fprintf(os.Stderr, "❌ %s%s\n", authErr.Message, authErr.GetTroubleshootingMessage())
+			return fmt.Errorf("authentication failed")
+		}
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	return nil
+}
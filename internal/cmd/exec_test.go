@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"synacklab/internal/auth"
+)
+
+func TestExecCommand(t *testing.T) {
+	if execCmd == nil {
+		t.Fatal("execCmd should not be nil")
+	}
+
+	if !strings.HasPrefix(execCmd.Use, "exec") {
+		t.Errorf("Expected command use to start with 'exec', got '%s'", execCmd.Use)
+	}
+
+	if execCmd.RunE == nil {
+		t.Error("Command should have a RunE function")
+	}
+
+	found := false
+	for _, cmd := range rootCmd.Commands() {
+		if cmd == execCmd {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("exec command not registered on root command")
+	}
+}
+
+func TestParseExecArgs(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		dashIndex       int
+		expectedProfile string
+		expectedCommand []string
+		expectError     bool
+	}{
+		{
+			name:            "profile and command after dash",
+			args:            []string{"prod-admin", "aws", "s3", "ls"},
+			dashIndex:       1,
+			expectedProfile: "prod-admin",
+			expectedCommand: []string{"aws", "s3", "ls"},
+		},
+		{
+			name:            "command only after dash",
+			args:            []string{"terraform", "plan"},
+			dashIndex:       0,
+			expectedProfile: "",
+			expectedCommand: []string{"terraform", "plan"},
+		},
+		{
+			name:            "profile and command without dash",
+			args:            []string{"dev", "env"},
+			dashIndex:       -1,
+			expectedProfile: "dev",
+			expectedCommand: []string{"env"},
+		},
+		{
+			name:            "profile only",
+			args:            []string{"dev"},
+			dashIndex:       -1,
+			expectedProfile: "dev",
+			expectedCommand: []string{},
+		},
+		{
+			name:            "no arguments",
+			args:            nil,
+			dashIndex:       -1,
+			expectedProfile: "",
+			expectedCommand: nil,
+		},
+		{
+			name:        "extra arguments before dash",
+			args:        []string{"prod", "extra", "aws", "s3", "ls"},
+			dashIndex:   2,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, command, err := parseExecArgs(tt.args, tt.dashIndex)
+			if tt.expectError {
+				if err == nil || !strings.Contains(err.Error(), "extra") {
+					t.Errorf("Expected an error naming the extra argument, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if profile != tt.expectedProfile {
+				t.Errorf("Expected profile '%s', got '%s'", tt.expectedProfile, profile)
+			}
+			if strings.Join(command, " ") != strings.Join(tt.expectedCommand, " ") {
+				t.Errorf("Expected command %v, got %v", tt.expectedCommand, command)
+			}
+		})
+	}
+}
+
+func TestBuildExecEnv(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin",
+		"AWS_PROFILE=old-profile",
+		"AWS_ACCESS_KEY_ID=OLDKEY",
+		"HOME=/home/test",
+	}
+
+	creds := &auth.RoleCredentials{
+		AccessKeyID:     "AKIATEST",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	env := buildExecEnv(environ, "prod-admin", "eu-west-1", creds)
+
+	envMap := make(map[string]string)
+	for _, entry := range env {
+		name, value, _ := strings.Cut(entry, "=")
+		if _, exists := envMap[name]; exists {
+			t.Errorf("Environment variable %s is set more than once", name)
+		}
+		envMap[name] = value
+	}
+
+	expected := map[string]string{
+		"PATH":                      "/usr/bin",
+		"HOME":                      "/home/test",
+		"AWS_ACCESS_KEY_ID":         "AKIATEST",
+		"AWS_SECRET_ACCESS_KEY":     "secret",
+		"AWS_SESSION_TOKEN":         "token",
+		"AWS_REGION":                "eu-west-1",
+		"AWS_DEFAULT_REGION":        "eu-west-1",
+		"AWS_CREDENTIAL_EXPIRATION": "2030-01-01T00:00:00Z",
+		"SYNACKLAB_PROFILE":         "prod-admin",
+	}
+
+	for name, value := range expected {
+		if envMap[name] != value {
+			t.Errorf("Expected %s=%s, got %s=%s", name, value, name, envMap[name])
+		}
+	}
+
+	if _, exists := envMap["AWS_PROFILE"]; exists {
+		t.Error("AWS_PROFILE should be removed from the child environment")
+	}
+}
+
+func TestFindAWSProfile(t *testing.T) {
+	cfg := ini.Empty()
+	section, err := cfg.NewSection("profile dev-readonly")
+	if err != nil {
+		t.Fatalf("Failed to create profile section: %v", err)
+	}
+	section.Key("sso_account_id").SetValue("210987654321")
+	section.Key("sso_role_name").SetValue("ReadOnly")
+	section.Key("region").SetValue("us-west-2")
+
+	profile, err := findAWSProfile(cfg, "dev-readonly")
+	if err != nil {
+		t.Fatalf("findAWSProfile failed: %v", err)
+	}
+
+	if profile.accountID != "210987654321" || profile.roleName != "ReadOnly" || profile.region != "us-west-2" {
+		t.Errorf("Unexpected profile: %+v", profile)
+	}
+
+	if _, err := findAWSProfile(cfg, "missing"); err == nil {
+		t.Error("Expected error for missing profile")
+	}
+}
@@ -12,6 +12,7 @@ var githubCmd = &cobra.Command{
 Available commands:
   apply    - Apply repository configuration to GitHub
   validate - Validate repository configuration file
+  schema   - Generate a JSON Schema for configuration files
   
 Supports both single repository and multi-repository configuration formats:
 
@@ -4,18 +4,24 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"synacklab/pkg/config"
+	"synacklab/pkg/fuzzy"
 	"synacklab/pkg/github"
 )
 
 var (
-	githubDryRun bool
-	githubOwner  string
-	githubRepos  []string
+	githubDryRun           bool
+	githubOwner            string
+	githubRepos            []string
+	githubSelector         []string
+	githubSelect           bool
+	githubSkipPreflight    bool
+	githubTokenPermissions map[string]string
 )
 
 var githubApplyCmd = &cobra.Command{
@@ -37,15 +43,37 @@ Multi-Repository Format:
   Supports selective operations using the --repos flag to process specific repositories.
   Global defaults are merged with repository-specific settings for consistency.
 
+Composed Configuration:
+  Pass a directory or a quoted glob pattern instead of a file to merge every
+  matching YAML file into one multi-repository configuration. Files and
+  directories starting with "_" or "." are skipped. Repositories can pull in
+  shared fragments with include: and opt into named profiles: layered, in
+  order, between the global defaults and their own settings.
+
 MULTI-REPOSITORY FEATURES:
 
 • Batch Operations: Process multiple repositories in a single command
 • Global Defaults: Define common settings applied to all repositories
-• Selective Processing: Use --repos flag to operate on specific repositories only
+• Profiles and Includes: Reuse named settings and YAML fragments across repositories
+• Repository Sets: Apply settings to every organization repository matching a query
+• Variables: Use ${repo.name}, ${repo.owner}, ${env.NAME} and vars: in string settings
+• Selective Processing: Use --repos and --selector to operate on matching repositories only
+• Interactive Selection: Use --select to pick repositories (from --repos, if given) in a picker
 • Independent Processing: Failures in one repository don't stop others
 • Comprehensive Reporting: Detailed success/failure status for each repository
 • Rate Limiting: Intelligent GitHub API rate limit handling across repositories
 
+PERMISSION PREFLIGHT:
+
+Before applying, every planned operation is checked against the access it
+needs: the OAuth scopes of classic tokens, or the permissions of fine-grained
+and GitHub App installation tokens, and the admin role on existing
+repositories. Operations that would fail are listed by repository and nothing
+is changed. GitHub does not list the permissions of fine-grained and
+installation tokens, so read-only probe requests find the permissions they
+lack and installation tokens are checked against the repositories of the
+installation. Pass --token-permissions to check permission levels as well.
+
 Examples:
   # Single repository operations
   synacklab github apply my-repo.yaml
@@ -54,15 +82,23 @@ Examples:
   # Multi-repository operations
   synacklab github apply multi-repos.yaml
   synacklab github apply multi-repos.yaml --owner myorg
+  synacklab github apply repos/
+  synacklab github apply "repos/*.yaml"
 
   # Selective multi-repository operations
   synacklab github apply multi-repos.yaml --repos repo1,repo2
   synacklab github apply multi-repos.yaml --repos "user-service,payment-service"
+  synacklab github apply multi-repos.yaml --repos "svc-*,!svc-legacy"
+  synacklab github apply multi-repos.yaml --selector topic=backend,tier=critical
+  synacklab github apply multi-repos.yaml --select
 
   # Preview multi-repository changes
   synacklab github apply multi-repos.yaml --dry-run
   synacklab github apply multi-repos.yaml --dry-run --repos repo1,repo2
 
+  # Check a fine-grained or installation token before applying
+  synacklab github apply multi-repos.yaml --token-permissions administration=write,webhooks=write
+
 Configuration Examples:
   See examples/ directory for sample configurations:
   • examples/github-simple-repo.yaml - Single repository format
@@ -76,7 +112,11 @@ Configuration Examples:
 func init() {
 	githubApplyCmd.Flags().BoolVar(&githubDryRun, "dry-run", false, "Preview changes without applying them (shows planned changes for all repositories)")
 	githubApplyCmd.Flags().StringVar(&githubOwner, "owner", "", "Repository owner (organization or user) - required for team operations")
-	githubApplyCmd.Flags().StringSliceVar(&githubRepos, "repos", nil, "Comma-separated list of repository names to process from multi-repository configuration (e.g., --repos repo1,repo2)")
+	githubApplyCmd.Flags().StringSliceVar(&githubRepos, "repos", nil, "Comma-separated list of repository names or globs to process from multi-repository configuration (e.g., --repos repo1,svc-*)")
+	githubApplyCmd.Flags().StringSliceVar(&githubSelector, "selector", nil, "Comma-separated selector terms: topic=x, label=value, name globs and !negations (e.g., --selector topic=backend,tier!=experimental)")
+	githubApplyCmd.Flags().BoolVar(&githubSelect, "select", false, "Interactively choose which repositories to process (narrows --repos when given)")
+	githubApplyCmd.Flags().BoolVar(&githubSkipPreflight, "skip-preflight", false, "Apply even when the permission preflight finds operations the token cannot perform")
+	githubApplyCmd.Flags().StringToStringVar(&githubTokenPermissions, "token-permissions", nil, "Permissions of a fine-grained or GitHub App installation token, checked by the preflight instead of probing (e.g., administration=write,webhooks=write)")
 	githubCmd.AddCommand(githubApplyCmd)
 }
 
@@ -84,7 +124,7 @@ func runGitHubApply(_ *cobra.Command, args []string) error {
 	configFile := args[0]
 
 	// Load configuration and detect format first (before authentication)
-	configData, configFormat, err := github.LoadConfigFromFile(configFile)
+	configData, configFormat, err := github.LoadConfigFromPath(configFile)
 	if err != nil {
 		return fmt.Errorf("failed to load repository config: %w", err)
 	}
@@ -114,7 +154,10 @@ func runGitHubApply(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("✓ Authenticated as %s\n", tokenInfo.User)
+	displayAuthenticated(tokenInfo)
+	if len(githubTokenPermissions) > 0 {
+		tokenInfo.Permissions = githubTokenPermissions
+	}
 
 	// Create GitHub client
 	token, err := authManager.GetToken(cfg)
@@ -126,9 +169,14 @@ func runGitHubApply(_ *cobra.Command, args []string) error {
 
 	switch configFormat {
 	case github.FormatSingleRepository:
-		return runSingleRepositoryApply(client, repoOwner, configData.(*github.RepositoryConfig))
+		repoConfig := configData.(*github.RepositoryConfig)
+		repoConfig, err = github.InterpolateRepository(repoConfig, repoOwner, repoConfig.Vars)
+		if err != nil {
+			return fmt.Errorf("failed to load repository config: %w", err)
+		}
+		return runSingleRepositoryApply(client, tokenInfo, repoOwner, repoConfig)
 	case github.FormatMultiRepository:
-		return runMultiRepositoryApply(client, repoOwner, configData.(*github.MultiRepositoryConfig))
+		return runMultiRepositoryApply(client, tokenInfo, repoOwner, configData.(*github.MultiRepositoryConfig))
 	default:
 		return fmt.Errorf("unsupported configuration format: %s", configFormat)
 	}
@@ -444,7 +492,7 @@ func stringSlicesEqual(a, b []string) bool {
 }
 
 // runSingleRepositoryApply handles single repository configuration
-func runSingleRepositoryApply(client github.APIClient, repoOwner string, repoConfig *github.RepositoryConfig) error {
+func runSingleRepositoryApply(client github.APIClient, tokenInfo *github.TokenInfo, repoOwner string, repoConfig *github.RepositoryConfig) error {
 	// Create single repository reconciler
 	reconciler := github.NewReconciler(client, repoOwner)
 
@@ -466,6 +514,11 @@ func runSingleRepositoryApply(client github.APIClient, repoOwner string, repoCon
 		return fmt.Errorf("failed to display plan: %w", err)
 	}
 
+	// Check the token can perform the planned operations
+	if err := runPreflight(client, repoOwner, map[string]*github.ReconciliationPlan{repoConfig.Name: plan}, tokenInfo); err != nil {
+		return err
+	}
+
 	// If dry-run, stop here
 	if githubDryRun {
 		fmt.Printf("\n✓ Dry-run completed. No changes were applied.\n")
@@ -491,12 +544,21 @@ func runSingleRepositoryApply(client github.APIClient, repoOwner string, repoCon
 }
 
 // runMultiRepositoryApply handles multi-repository configuration
-func runMultiRepositoryApply(client github.APIClient, repoOwner string, multiConfig *github.MultiRepositoryConfig) error {
+func runMultiRepositoryApply(client github.APIClient, tokenInfo *github.TokenInfo, repoOwner string, multiConfig *github.MultiRepositoryConfig) error {
+	repoFilter := repositoryFilter()
+	if githubSelect {
+		selected, err := selectRepositories(multiConfig, repoFilter)
+		if err != nil {
+			return fmt.Errorf("failed to select repositories: %w", err)
+		}
+		repoFilter = selected
+	}
+
 	// Create multi-repository reconciler
 	multiReconciler := github.NewMultiReconciler(client, repoOwner)
 
 	// Validate configuration
-	validationResult, err := multiReconciler.ValidateAll(multiConfig, githubRepos)
+	validationResult, err := multiReconciler.ValidateAll(multiConfig, repoFilter)
 	if err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
@@ -514,7 +576,7 @@ func runMultiRepositoryApply(client github.APIClient, repoOwner string, multiCon
 	fmt.Printf("✓ Configuration validated for %d repositories\n", validationResult.Summary.ValidCount)
 
 	// Create reconciliation plans
-	plans, planErr := multiReconciler.PlanAll(multiConfig, githubRepos)
+	plans, planErr := multiReconciler.PlanAll(multiConfig, repoFilter)
 
 	// For dry-run mode, continue even if there are planning errors to show what we can
 	if planErr != nil && !githubDryRun {
@@ -526,6 +588,11 @@ func runMultiRepositoryApply(client github.APIClient, repoOwner string, multiCon
 		return fmt.Errorf("failed to display plans: %w", err)
 	}
 
+	// Check the token can perform the planned operations
+	if err := runPreflight(client, repoOwner, plans, tokenInfo); err != nil {
+		return err
+	}
+
 	// If there were planning errors during dry-run, report them after showing successful plans
 	if planErr != nil && githubDryRun {
 		fmt.Printf("\n⚠️  Planning errors encountered during dry-run:\n")
@@ -566,6 +633,76 @@ func runMultiRepositoryApply(client github.APIClient, repoOwner string, multiCon
 	return nil
 }
 
+// displayAuthenticated shows who the token authenticates as. Installation
+// tokens act as an app rather than a user.
+func displayAuthenticated(tokenInfo *github.TokenInfo) {
+	if tokenInfo.Type == github.TokenTypeInstallation {
+		fmt.Printf("✓ Authenticated with an installation token for %d repositories\n", len(tokenInfo.Repositories))
+		return
+	}
+	fmt.Printf("✓ Authenticated as %s\n", tokenInfo.User)
+}
+
+// runPreflight checks the planned operations against the access of the
+// token, failing before anything is applied when some would fail. Dry runs
+// only report them. The permissions of fine-grained and installation
+// tokens are probed unless given with --token-permissions.
+func runPreflight(client github.APIClient, owner string, plans map[string]*github.ReconciliationPlan, tokenInfo *github.TokenInfo) error {
+	if githubSkipPreflight || tokenInfo == nil {
+		return nil
+	}
+
+	if tokenInfo.Type != github.TokenTypeClassic && tokenInfo.Type != "" && len(tokenInfo.Permissions) == 0 {
+		if prober, ok := client.(github.PermissionProber); ok {
+			missing, err := prober.ProbePermissions(owner, plans)
+			if err != nil {
+				fmt.Printf("⚠️  Warning: failed to probe token permissions: %v\n", err)
+			}
+			tokenInfo.MissingPermissions = missing
+		}
+	}
+
+	report := github.Preflight(plans, tokenInfo)
+	displayPreflightReport(report)
+
+	failures := report.Failures()
+	if len(failures) == 0 || githubDryRun {
+		return nil
+	}
+	return fmt.Errorf("permission preflight failed: %d operations on %d repositories would fail (use --skip-preflight to apply anyway)",
+		len(failures), len(report.FailedRepositories()))
+}
+
+// displayPreflightReport shows which planned operations the token cannot perform
+func displayPreflightReport(report *github.PreflightReport) {
+	if len(report.Checks) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🔐 Permission preflight (%s token):\n", report.TokenType)
+
+	failures := report.Failures()
+	if len(failures) == 0 {
+		fmt.Printf("   ✓ %d planned operations allowed\n", len(report.Checks))
+	} else {
+		fmt.Printf("   ❌ %d of %d planned operations would fail:\n", len(failures), len(report.Checks))
+		repository := ""
+		for _, failure := range failures {
+			if failure.Repository != repository {
+				repository = failure.Repository
+				fmt.Printf("   • %s:\n", repository)
+			}
+			fmt.Printf("     - %s: %s\n", failure.Operation, failure.Problem)
+		}
+	}
+
+	if report.Unverified {
+		fmt.Printf("   ⚠️  GitHub does not list the permissions of %s tokens, so only missing ones were found\n", report.TokenType)
+		fmt.Printf("      The planned operations need: %s\n", strings.Join(report.RequiredPermissions(), ", "))
+		fmt.Printf("      Pass --token-permissions to check their levels\n")
+	}
+}
+
 // displayMultiRepoValidationResults displays validation results for multiple repositories
 func displayMultiRepoValidationResults(result *github.MultiRepoValidationResult) error {
 	if result.Summary.InvalidCount > 0 {
@@ -849,3 +986,54 @@ func countPlanChanges(plan *github.ReconciliationPlan) int {
 	count += len(plan.Webhooks)
 	return count
 }
+
+// repositoryFilter returns the selector terms given with --repos and --selector
+func repositoryFilter() []string {
+	var filter []string
+	for _, term := range append(slices.Clone(githubRepos), githubSelector...) {
+		if term = strings.TrimSpace(term); term != "" {
+			filter = append(filter, term)
+		}
+	}
+	return filter
+}
+
+// repositorySelectionOptions returns picker options for the configured
+// repositories, limited to those selected by filter when it is not empty
+func repositorySelectionOptions(multiConfig *github.MultiRepositoryConfig, filter []string) ([]fuzzy.Option, error) {
+	repos, err := github.SelectRepositories(multiConfig, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]fuzzy.Option, 0, len(repos))
+	for _, repo := range repos {
+		visibility := "public"
+		if repo.Private {
+			visibility = "private"
+		}
+		description := visibility
+		if repo.Description != "" {
+			description = fmt.Sprintf("%s | %s", visibility, repo.Description)
+		}
+
+		options = append(options, fuzzy.Option{Value: repo.Name, Description: description})
+	}
+
+	return options, nil
+}
+
+// selectRepositories asks which of the configured repositories to process
+func selectRepositories(multiConfig *github.MultiRepositoryConfig, filter []string) ([]string, error) {
+	options, err := repositorySelectionOptions(multiConfig, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	finder := fuzzy.NewFzf("🔍 Select repositories (Tab to toggle, Ctrl+A for all):")
+	if err := finder.SetOptions(options); err != nil {
+		return nil, fmt.Errorf("failed to set finder options: %w", err)
+	}
+
+	return finder.SelectMany()
+}
@@ -1781,3 +1781,141 @@ func TestDisplayRepositoryPlanChanges(t *testing.T) {
 		})
 	}
 }
+
+func TestRepositorySelectionOptions(t *testing.T) {
+	multiConfig := &github.MultiRepositoryConfig{
+		Repositories: []github.RepositoryConfig{
+			{Name: "api", Description: "Public API", Private: true},
+			{Name: "docs"},
+			{Name: "web"},
+		},
+	}
+
+	options, err := repositorySelectionOptions(multiConfig, nil)
+	require.NoError(t, err)
+	require.Len(t, options, 3)
+	assert.Equal(t, "private | Public API", options[0].Description)
+	assert.Equal(t, "public", options[1].Description)
+
+	options, err = repositorySelectionOptions(multiConfig, []string{"web", "api"})
+	require.NoError(t, err)
+	require.Len(t, options, 2)
+	assert.Equal(t, "api", options[0].Value)
+	assert.Equal(t, "web", options[1].Value)
+
+	options, err = repositorySelectionOptions(multiConfig, []string{"*", "!docs"})
+	require.NoError(t, err)
+	require.Len(t, options, 2)
+	assert.Equal(t, "api", options[0].Value)
+	assert.Equal(t, "web", options[1].Value)
+
+	_, err = repositorySelectionOptions(multiConfig, []string{"web", "missing"})
+	assert.EqualError(t, err, "repositories not found in configuration: missing")
+}
+
+func TestRunPreflight(t *testing.T) {
+	plans := map[string]*github.ReconciliationPlan{
+		"api": {
+			Repository: &github.RepositoryChange{Type: github.ChangeTypeCreate, After: &github.Repository{Name: "api", Private: true}},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		tokenInfo      *github.TokenInfo
+		dryRun         bool
+		probed         []string
+		expectError    bool
+		expectedOutput []string
+	}{
+		{
+			name:           "token with the required scopes",
+			tokenInfo:      &github.TokenInfo{Type: github.TokenTypeClassic, Scopes: []string{"repo"}},
+			expectedOutput: []string{"🔐 Permission preflight (classic token):", "✓ 1 planned operations allowed"},
+		},
+		{
+			name:        "token missing a scope",
+			tokenInfo:   &github.TokenInfo{Type: github.TokenTypeClassic, Scopes: []string{"public_repo"}},
+			expectError: true,
+			expectedOutput: []string{
+				"❌ 1 of 1 planned operations would fail:",
+				"• api:",
+				"- create repository: token needs the repo scope",
+			},
+		},
+		{
+			name:           "dry run only reports failures",
+			tokenInfo:      &github.TokenInfo{Type: github.TokenTypeClassic},
+			dryRun:         true,
+			expectedOutput: []string{"- create repository: token needs the repo scope"},
+		},
+		{
+			name:      "fine-grained token with unknown permissions",
+			tokenInfo: &github.TokenInfo{Type: github.TokenTypeFineGrained},
+			expectedOutput: []string{
+				"GitHub does not list the permissions of fine-grained tokens",
+				"The planned operations need: administration: write",
+			},
+		},
+		{
+			name:        "fine-grained token missing a probed permission",
+			tokenInfo:   &github.TokenInfo{Type: github.TokenTypeFineGrained},
+			probed:      []string{"administration"},
+			expectError: true,
+			expectedOutput: []string{
+				"- create repository: token does not have the administration permission",
+			},
+		},
+		{
+			name:           "given permissions are not probed",
+			tokenInfo:      &github.TokenInfo{Type: github.TokenTypeFineGrained, Permissions: map[string]string{"administration": "write"}},
+			probed:         []string{"administration"},
+			expectedOutput: []string{"✓ 1 planned operations allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldDryRun := githubDryRun
+			githubDryRun = tt.dryRun
+			defer func() { githubDryRun = oldDryRun }()
+
+			// Capture stdout
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			var buf bytes.Buffer
+			done := make(chan bool)
+			go func() {
+				_, _ = buf.ReadFrom(r)
+				done <- true
+			}()
+
+			err := runPreflight(&fakePermissionProber{missing: tt.probed}, "test-owner", plans, tt.tokenInfo)
+
+			_ = w.Close()
+			os.Stdout = oldStdout
+			<-done
+
+			if tt.expectError {
+				assert.ErrorContains(t, err, "permission preflight failed: 1 operations on 1 repositories would fail")
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, expected := range tt.expectedOutput {
+				assert.Contains(t, buf.String(), expected)
+			}
+		})
+	}
+}
+
+// fakePermissionProber is a client whose probes find fixed permissions missing
+type fakePermissionProber struct {
+	github.APIClient
+	missing []string
+}
+
+func (p *fakePermissionProber) ProbePermissions(string, map[string]*github.ReconciliationPlan) ([]string, error) {
+	return p.missing, nil
+}
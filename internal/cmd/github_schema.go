@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"synacklab/pkg/github"
+)
+
+var githubSchemaOutput string
+
+var githubSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Generate a JSON Schema for repository configuration files",
+	Long: `Generate a JSON Schema describing repository configuration files.
+
+The schema covers both the single repository and the multi-repository formats,
+including defaults, profiles, repository sets, merge strategies and variables.
+Permission levels and webhook events are listed as enums, so editors using the
+schema offer completion and flag invalid values as you type.
+
+EDITOR INTEGRATION:
+
+With the YAML language server (VS Code, Neovim, JetBrains, ...), reference the
+schema from the first line of a configuration file:
+
+  # yaml-language-server: $schema=./synacklab-github.schema.json
+
+or map it to your configuration files in the editor settings, e.g. VS Code:
+
+  "yaml.schemas": {
+    "./synacklab-github.schema.json": ["repos/*.yaml"]
+  }
+
+Examples:
+  # Print the schema
+  synacklab github schema
+
+  # Write the schema next to your configuration
+  synacklab github schema --output synacklab-github.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: runGitHubSchema,
+}
+
+func init() {
+	githubSchemaCmd.Flags().StringVarP(&githubSchemaOutput, "output", "o", "", "Write the schema to a file instead of standard output")
+	githubCmd.AddCommand(githubSchemaCmd)
+}
+
+func runGitHubSchema(_ *cobra.Command, _ []string) error {
+	schema, err := github.GenerateJSONSchema()
+	if err != nil {
+		return fmt.Errorf("failed to generate schema: %w", err)
+	}
+
+	if githubSchemaOutput == "" {
+		fmt.Println(string(schema))
+		return nil
+	}
+
+	if err := os.WriteFile(githubSchemaOutput, append(schema, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+	fmt.Printf("✅ JSON Schema written to %s\n", githubSchemaOutput)
+	return nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaCmd_WritesFile(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "schema.json")
+	githubSchemaOutput = output
+	defer func() { githubSchemaOutput = "" }()
+
+	require.NoError(t, runGitHubSchema(githubSchemaCmd, nil))
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	assert.Contains(t, schema, "$defs")
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+}
+
+func TestSchemaCmd_Registered(t *testing.T) {
+	found := false
+	for _, cmd := range githubCmd.Commands() {
+		if cmd.Name() == "schema" {
+			found = true
+		}
+	}
+	assert.True(t, found, "schema command not registered under github")
+}
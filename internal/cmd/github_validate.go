@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -11,6 +12,13 @@ import (
 	"synacklab/pkg/github"
 )
 
+// githubValidateFormat is how validation errors are reported: text, or github
+// for GitHub Actions annotations on the configuration lines
+var githubValidateFormat string
+
+// githubValidateStrict rejects unknown and duplicate keys in configuration files
+var githubValidateStrict bool
+
 var githubValidateCmd = &cobra.Command{
 	Use:   "validate <config-file.yaml>",
 	Short: "Validate repository configuration file",
@@ -23,6 +31,9 @@ VALIDATION CHECKS:
 
 Offline Validation (always performed):
 • YAML syntax errors and structure validation
+• Unknown fields, with suggestions for likely typos, and duplicate keys
+  (disable with --strict=false)
+• Supported configuration version (version: 1.x)
 • Required fields and valid values
 • Configuration format detection and compatibility
 • Duplicate repository names (multi-repository format)
@@ -37,7 +48,7 @@ Online Validation (when authenticated):
 MULTI-REPOSITORY FEATURES:
 
 • Batch Validation: Validate all repositories in a single command
-• Selective Validation: Use --repos flag to validate specific repositories only
+• Selective Validation: Use --repos and --selector to validate matching repositories only
 • Comprehensive Reporting: Detailed validation results for each repository
 • Merge Validation: Ensures global defaults merge correctly with repository settings
 • Duplicate Detection: Identifies duplicate repository names within configuration
@@ -51,14 +62,25 @@ Examples:
   synacklab github validate multi-repos.yaml --owner myorg
   synacklab github validate multi-repos.yaml
 
+  # Composed configuration (a directory or a quoted glob pattern)
+  synacklab github validate repos/
+  synacklab github validate "repos/*.yaml"
+
   # Selective multi-repository validation
   synacklab github validate multi-repos.yaml --repos repo1,repo2
   synacklab github validate multi-repos.yaml --repos "user-service,payment-service" --owner myorg
+  synacklab github validate multi-repos.yaml --repos "svc-*" --selector "topic=backend,!tier=experimental"
 
   # Validation without authentication (offline only)
   synacklab github validate multi-repos.yaml
   # Note: Will skip user/team existence checks but validate syntax and structure
 
+  # Errors as GitHub Actions annotations, in a workflow
+  synacklab github validate repos/ --format github
+
+Errors are reported with the file, line and column of the value at fault:
+  repos.yaml:42:7: repositories[3].collaborators[2].permission (owner): collaborator 3: permission must be one of: read, write, admin
+
 Configuration Examples:
   See examples/ directory for sample configurations and migration guide:
   • examples/github-simple-repo.yaml - Single repository format
@@ -70,29 +92,34 @@ Configuration Examples:
 
 func init() {
 	githubValidateCmd.Flags().StringVar(&githubOwner, "owner", "", "Repository owner (organization or user) - required for team validation and permissions checks")
-	githubValidateCmd.Flags().StringSliceVar(&githubRepos, "repos", nil, "Comma-separated list of repository names to validate from multi-repository configuration (e.g., --repos repo1,repo2)")
+	githubValidateCmd.Flags().StringSliceVar(&githubRepos, "repos", nil, "Comma-separated list of repository names or globs to validate from multi-repository configuration (e.g., --repos repo1,svc-*)")
+	githubValidateCmd.Flags().StringSliceVar(&githubSelector, "selector", nil, "Comma-separated selector terms: topic=x, label=value, name globs and !negations (e.g., --selector topic=backend,tier!=experimental)")
+	githubValidateCmd.Flags().StringVar(&githubValidateFormat, "format", "text", "Error output format: text, or github for GitHub Actions annotations")
+	githubValidateCmd.Flags().BoolVar(&githubValidateStrict, "strict", true, "Reject unknown and duplicate keys; use --strict=false to ignore them")
 	githubCmd.AddCommand(githubValidateCmd)
 }
 
 func runGitHubValidate(_ *cobra.Command, args []string) error {
 	configFile := args[0]
 
+	if githubValidateFormat != "text" && githubValidateFormat != "github" {
+		return fmt.Errorf("invalid format %q: must be text or github", githubValidateFormat)
+	}
+
 	fmt.Printf("🔍 Validating configuration file: %s\n", configFile)
 
 	// Parse repository filter if provided
-	var repoFilter []string
-	if len(githubRepos) > 0 {
-		// Remove empty strings from the filter
-		for _, repo := range githubRepos {
-			if strings.TrimSpace(repo) != "" {
-				repoFilter = append(repoFilter, strings.TrimSpace(repo))
-			}
-		}
-	}
+	repoFilter := repositoryFilter()
 
 	// Load configuration and detect format
-	configData, format, err := github.LoadConfigFromFile(configFile)
+	configData, format, err := github.LoadConfigFromPathWithOptions(configFile, github.LoadOptions{Strict: githubValidateStrict})
 	if err != nil {
+		var validationErrors github.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			fmt.Printf("\n❌ Configuration errors:\n")
+			displayValidationErrors(validationErrors, "   ")
+			return fmt.Errorf("configuration validation failed with %d errors", len(validationErrors))
+		}
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
@@ -102,7 +129,13 @@ func runGitHubValidate(_ *cobra.Command, args []string) error {
 	// Handle different configuration formats
 	switch format {
 	case github.FormatSingleRepository:
-		return runSingleRepositoryValidation(configData.(*github.RepositoryConfig), configFile)
+		// ${repo.owner} is only resolved when --owner is given
+		repoConfig := configData.(*github.RepositoryConfig)
+		repoConfig, err = github.InterpolateRepository(repoConfig, githubOwner, repoConfig.Vars)
+		if err != nil {
+			return fmt.Errorf("configuration validation failed: %w", err)
+		}
+		return runSingleRepositoryValidation(repoConfig, configFile)
 	case github.FormatMultiRepository:
 		return runMultiRepositoryValidation(configData.(*github.MultiRepositoryConfig), configFile, repoFilter)
 	default:
@@ -146,7 +179,7 @@ func runSingleRepositoryValidation(repoConfig *github.RepositoryConfig, configFi
 		return nil
 	}
 
-	fmt.Printf("✓ Authenticated as %s\n", tokenInfo.User)
+	displayAuthenticated(tokenInfo)
 
 	// Get GitHub token for API validation
 	token, err := authManager.GetToken(cfg)
@@ -181,11 +214,21 @@ func runSingleRepositoryValidation(repoConfig *github.RepositoryConfig, configFi
 	}
 
 	// Perform the actual validation
-	if err := validator.ValidateConfig(repoConfig, repoOwner); err != nil {
+	validationErrors, warnings, err := validator.ValidateAccounts(repoConfig, repoOwner)
+	if err != nil {
 		return fmt.Errorf("GitHub API validation failed: %w", err)
 	}
+	if validationErrors.HasErrors() {
+		fmt.Printf("\n❌ Users and teams:\n")
+		displayValidationErrors(validationErrors, "   ")
+		return fmt.Errorf("GitHub API validation failed with %d errors", len(validationErrors))
+	}
 
 	fmt.Printf("✓ All users and teams exist\n")
+	if len(warnings) > 0 {
+		fmt.Printf("\n⚠️  Validation warnings:\n")
+		displayValidationWarnings(warnings, "   - ")
+	}
 
 	// Validate permissions for the repository if it exists
 	if repoOwner != "" {
@@ -220,29 +263,27 @@ func runSingleRepositoryValidation(repoConfig *github.RepositoryConfig, configFi
 func runMultiRepositoryValidation(multiConfig *github.MultiRepositoryConfig, configFile string, repoFilter []string) error {
 	totalRepos := len(multiConfig.Repositories)
 
-	// Validate repository filter early
-	if len(repoFilter) > 0 {
-		// Create a set of available repository names
-		availableRepos := make(map[string]bool)
-		for _, repo := range multiConfig.Repositories {
-			availableRepos[repo.Name] = true
+	// Validate repository filter early; repositories from repository sets are
+	// only known once they are listed from GitHub
+	switch {
+	case len(multiConfig.RepositorySets) > 0:
+		fmt.Printf("📦 Validating %d repositories and %d repository sets\n", totalRepos, len(multiConfig.RepositorySets))
+		if len(repoFilter) > 0 {
+			fmt.Printf("🎯 Selector: %s\n", strings.Join(repoFilter, ", "))
 		}
-
-		// Check that all filtered repositories exist
-		var invalidRepos []string
-		for _, repoName := range repoFilter {
-			if !availableRepos[repoName] {
-				invalidRepos = append(invalidRepos, repoName)
-			}
+	case len(repoFilter) > 0:
+		selected, err := github.SelectRepositories(multiConfig, repoFilter)
+		if err != nil {
+			return err
 		}
 
-		if len(invalidRepos) > 0 {
-			return fmt.Errorf("repositories not found in configuration: %s", strings.Join(invalidRepos, ", "))
+		names := make([]string, 0, len(selected))
+		for _, repo := range selected {
+			names = append(names, repo.Name)
 		}
-
-		fmt.Printf("📦 Validating %d selected repositories from %d total repositories\n", len(repoFilter), totalRepos)
-		fmt.Printf("🎯 Selected repositories: %s\n", strings.Join(repoFilter, ", "))
-	} else {
+		fmt.Printf("📦 Validating %d selected repositories from %d total repositories\n", len(selected), totalRepos)
+		fmt.Printf("🎯 Selected repositories: %s\n", strings.Join(names, ", "))
+	default:
 		fmt.Printf("📦 Validating %d repositories\n", totalRepos)
 	}
 
@@ -279,7 +320,7 @@ func runMultiRepositoryValidation(multiConfig *github.MultiRepositoryConfig, con
 		return nil
 	}
 
-	fmt.Printf("✓ Authenticated as %s\n", tokenInfo.User)
+	displayAuthenticated(tokenInfo)
 
 	// Get GitHub token for API validation
 	token, err := authManager.GetToken(cfg)
@@ -299,6 +340,10 @@ func runMultiRepositoryValidation(multiConfig *github.MultiRepositoryConfig, con
 	// Validate all repositories
 	result, err := multiReconciler.ValidateAll(multiConfig, repoFilter)
 	if err != nil {
+		if result != nil && len(result.Errors) > 0 {
+			fmt.Printf("\n❌ Configuration errors:\n")
+			displayValidationErrors(result.Errors, "   ")
+		}
 		return fmt.Errorf("multi-repository validation failed: %w", err)
 	}
 
@@ -366,13 +411,7 @@ func displayMultiRepositoryValidationResults(result *github.MultiRepoValidationR
 			if details, exists := result.Details[repo]; exists {
 				if len(details.Errors) > 0 {
 					fmt.Printf("     Errors:\n")
-					for _, validationErr := range details.Errors {
-						if validationErr.Value != "" {
-							fmt.Printf("       - %s (%s): %s\n", validationErr.Field, validationErr.Value, validationErr.Message)
-						} else {
-							fmt.Printf("       - %s: %s\n", validationErr.Field, validationErr.Message)
-						}
-					}
+					displayValidationErrors(details.Errors, "       - ")
 				}
 			}
 		}
@@ -387,13 +426,61 @@ func displayMultiRepositoryValidationResults(result *github.MultiRepoValidationR
 				hasWarnings = true
 			}
 			fmt.Printf("   • %s:\n", repo)
-			for _, warning := range details.Warnings {
-				if warning.Value != "" {
-					fmt.Printf("     - %s (%s): %s\n", warning.Field, warning.Value, warning.Message)
-				} else {
-					fmt.Printf("     - %s: %s\n", warning.Field, warning.Message)
-				}
-			}
+			displayValidationWarnings(details.Warnings, "     - ")
 		}
 	}
 }
+
+// displayValidationErrors prints validation errors, one per line after
+// prefix, or as GitHub Actions annotations with the github format
+func displayValidationErrors(validationErrors []github.ValidationError, prefix string) {
+	for _, validationErr := range validationErrors {
+		if githubValidateFormat == "github" {
+			fmt.Println(githubAnnotation("error", validationErr.Position, validationErr.Field, validationErr.Value, validationErr.Message))
+		} else {
+			fmt.Printf("%s%s\n", prefix, formatValidationProblem(validationErr.Position, validationErr.Field, validationErr.Value, validationErr.Message))
+		}
+	}
+}
+
+// displayValidationWarnings prints validation warnings, one per line after
+// prefix, or as GitHub Actions annotations with the github format
+func displayValidationWarnings(warnings []github.ValidationWarning, prefix string) {
+	for _, warning := range warnings {
+		if githubValidateFormat == "github" {
+			fmt.Println(githubAnnotation("warning", warning.Position, warning.Field, warning.Value, warning.Message))
+		} else {
+			fmt.Printf("%s%s\n", prefix, formatValidationProblem(warning.Position, warning.Field, warning.Value, warning.Message))
+		}
+	}
+}
+
+// formatValidationProblem returns a validation error or warning as
+// file:line:column: field (value): message, leaving out what is unknown
+func formatValidationProblem(position *github.SourcePosition, field, value, message string) string {
+	text := field
+	if value != "" {
+		text += " (" + value + ")"
+	}
+	text += ": " + message
+	if position != nil {
+		text = position.String() + ": " + text
+	}
+	return text
+}
+
+// githubAnnotation returns a validation error or warning as a GitHub Actions
+// workflow command, which annotates the configuration line in pull requests
+func githubAnnotation(level string, position *github.SourcePosition, field, value, message string) string {
+	properties := ""
+	if position != nil && position.File != "" {
+		properties = fmt.Sprintf(" file=%s,line=%d,col=%d", annotationEscaper.Replace(position.File), position.Line, position.Column)
+	}
+	return fmt.Sprintf("::%s%s::%s", level, properties, annotationMessageEscaper.Replace(formatValidationProblem(nil, field, value, message)))
+}
+
+// Workflow command data and properties escape these characters
+var (
+	annotationMessageEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	annotationEscaper        = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
@@ -326,3 +326,72 @@ repositories:
 	assert.Len(t, multiConfig.Defaults.Webhooks, 1)
 	assert.Len(t, multiConfig.Repositories, 2)
 }
+
+func TestValidateCmd_ReportsPositions(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "repos.yaml")
+	config := `repositories:
+  - name: api
+    collaborators:
+      - username: alice
+        permission: owner
+`
+	require.NoError(t, os.WriteFile(configFile, []byte(config), 0644))
+
+	err := runGitHubValidate(githubValidateCmd, []string{configFile})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "configuration validation failed with 1 errors")
+
+	_, _, err = github.LoadConfigFromPath(configFile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), configFile+":5:21: validation error for field 'repositories[0].collaborators[0].permission' (value: owner)")
+}
+
+func TestValidateCmd_InvalidFormat(t *testing.T) {
+	githubValidateFormat = "xml"
+	defer func() { githubValidateFormat = "text" }()
+
+	err := runGitHubValidate(githubValidateCmd, []string{"repos.yaml"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid format")
+}
+
+func TestFormatValidationProblem(t *testing.T) {
+	position := &github.SourcePosition{File: "repos.yaml", Line: 42, Column: 7}
+
+	assert.Equal(t,
+		`repos.yaml:42:7: collaborators[2].permission (owner): permission must be one of: read, write, admin`,
+		formatValidationProblem(position, "collaborators[2].permission", "owner", "permission must be one of: read, write, admin"))
+	assert.Equal(t, "name: repository name is required", formatValidationProblem(nil, "name", "", "repository name is required"))
+}
+
+func TestGitHubAnnotation(t *testing.T) {
+	position := &github.SourcePosition{File: "config/repos,v2.yaml", Line: 42, Column: 7}
+
+	assert.Equal(t,
+		"::error file=config/repos%2Cv2.yaml,line=42,col=7::topics[0] (50%25): topic 1 is invalid%0Atry again",
+		githubAnnotation("error", position, "topics[0]", "50%", "topic 1 is invalid\ntry again"))
+	assert.Equal(t, "::warning::branch_protection: no rules", githubAnnotation("warning", nil, "branch_protection", "", "no rules"))
+}
+
+func TestValidateCmd_StrictByDefault(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "repo.yaml")
+	config := `name: api
+branch_protections:
+  - pattern: main
+`
+	require.NoError(t, os.WriteFile(configFile, []byte(config), 0644))
+
+	assert.True(t, githubValidateStrict)
+	err := runGitHubValidate(githubValidateCmd, []string{configFile})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "configuration validation failed with 1 errors")
+
+	_, _, err = github.LoadConfigFromPathWithOptions(configFile, github.LoadOptions{Strict: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown field "branch_protections", did you mean "branch_protection"?`)
+
+	_, _, err = github.LoadConfigFromPathWithOptions(configFile, github.LoadOptions{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// kubeAPITimeout bounds requests to the Kubernetes API server
+const kubeAPITimeout = 15 * time.Second
+
+// kubeClient is a minimal Kubernetes API client built from a kubeconfig context
+type kubeClient struct {
+	server     string
+	token      string
+	httpClient *http.Client
+}
+
+// newKubeClient builds a client for a kubeconfig context using the credentials of
+// its user: a bearer token, a token file, client certificates or an exec plugin
+func newKubeClient(ctx context.Context, kubeConfig *KubeConfig, contextName string) (*kubeClient, error) {
+	kubeContext, err := findKubeContext(kubeConfig, contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	var cluster *KubeCluster
+	for i := range kubeConfig.Clusters {
+		if kubeConfig.Clusters[i].Name == kubeContext.Context.Cluster {
+			cluster = &kubeConfig.Clusters[i]
+			break
+		}
+	}
+	if cluster == nil {
+		return nil, fmt.Errorf("cluster '%s' referenced by context '%s' not found", kubeContext.Context.Cluster, contextName)
+	}
+	if cluster.Cluster.Server == "" {
+		return nil, fmt.Errorf("cluster '%s' has no server", cluster.Name)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	caData, err := kubeConfigData(cluster.Cluster.CertificateAuthorityData, cluster.Cluster.Extra, "certificate-authority")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate authority of cluster '%s': %w", cluster.Name, err)
+	}
+	if len(caData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("invalid certificate authority data for cluster '%s'", cluster.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if insecure, _ := cluster.Cluster.Extra["insecure-skip-tls-verify"].(bool); insecure {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if serverName := extraString(cluster.Cluster.Extra, "tls-server-name"); serverName != "" {
+		tlsConfig.ServerName = serverName
+	}
+
+	client := &kubeClient{server: strings.TrimRight(cluster.Cluster.Server, "/")}
+
+	for i := range kubeConfig.Users {
+		user := kubeConfig.Users[i].User
+		if kubeConfig.Users[i].Name != kubeContext.Context.User {
+			continue
+		}
+
+		if err := client.configureUser(ctx, user, tlsConfig); err != nil {
+			return nil, fmt.Errorf("failed to obtain credentials for user '%s': %w", kubeConfig.Users[i].Name, err)
+		}
+		break
+	}
+
+	client.httpClient = &http.Client{
+		Timeout:   kubeAPITimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+	}
+
+	return client, nil
+}
+
+// configureUser applies the credentials of a kubeconfig user
+func (c *kubeClient) configureUser(ctx context.Context, user KubeUserExec, tlsConfig *tls.Config) error {
+	certData, err := kubeConfigData(extraString(user.Extra, "client-certificate-data"), user.Extra, "client-certificate")
+	if err != nil {
+		return err
+	}
+	keyData, err := kubeConfigData(extraString(user.Extra, "client-key-data"), user.Extra, "client-key")
+	if err != nil {
+		return err
+	}
+
+	c.token = extraString(user.Extra, "token")
+	if tokenFile := extraString(user.Extra, "tokenFile"); c.token == "" && tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read token file: %w", err)
+		}
+		c.token = strings.TrimSpace(string(data))
+	}
+
+	if user.Exec.Command != "" {
+		status, err := runKubeExecPlugin(ctx, user.Exec)
+		if err != nil {
+			return err
+		}
+		c.token = status.Token
+		if status.ClientCertificateData != "" {
+			certData, keyData = []byte(status.ClientCertificateData), []byte(status.ClientKeyData)
+		}
+	}
+
+	if len(certData) > 0 {
+		cert, err := tls.X509KeyPair(certData, keyData)
+		if err != nil {
+			return fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return nil
+}
+
+// kubeExecStatus is the status of an ExecCredential returned by a credential plugin
+type kubeExecStatus struct {
+	Token                 string `json:"token"`
+	ClientCertificateData string `json:"clientCertificateData"`
+	ClientKeyData         string `json:"clientKeyData"`
+}
+
+// runKubeExecPlugin runs an exec credential plugin the way kubectl does
+func runKubeExecPlugin(ctx context.Context, execConfig KubeExecConfig) (*kubeExecStatus, error) {
+	execInfo, err := json.Marshal(map[string]any{
+		"apiVersion": execConfig.APIVersion,
+		"kind":       "ExecCredential",
+		"spec":       map[string]any{"interactive": false},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode exec info: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, execConfig.Command, execConfig.Args...)
+	cmd.Env = append(os.Environ(), "KUBERNETES_EXEC_INFO="+string(execInfo))
+	for _, env := range execConfig.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	cmd.Stderr = os.Stderr
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("credential plugin '%s' failed: %w", execConfig.Command, err)
+	}
+
+	var credential struct {
+		Status *kubeExecStatus `json:"status"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &credential); err != nil {
+		return nil, fmt.Errorf("failed to parse output of credential plugin '%s': %w", execConfig.Command, err)
+	}
+	if credential.Status == nil {
+		return nil, fmt.Errorf("credential plugin '%s' returned no credentials", execConfig.Command)
+	}
+
+	return credential.Status, nil
+}
+
+// listNamespaces returns the names of the namespaces in the cluster
+func (c *kubeClient) listNamespaces(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server+"/api/v1/namespaces", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read namespace list: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, fmt.Errorf("the cluster rejected the credentials (401 Unauthorized)")
+	case resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("not allowed to list namespaces (403 Forbidden)")
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to list namespaces: %s", resp.Status)
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace list: %w", err)
+	}
+
+	namespaces := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		namespaces = append(namespaces, item.Metadata.Name)
+	}
+	sort.Strings(namespaces)
+
+	return namespaces, nil
+}
+
+// findKubeContext returns the named context of a kubeconfig
+func findKubeContext(kubeConfig *KubeConfig, contextName string) (*KubeContext, error) {
+	for i := range kubeConfig.Contexts {
+		if kubeConfig.Contexts[i].Name == contextName {
+			return &kubeConfig.Contexts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("context '%s' not found in kubeconfig", contextName)
+}
+
+// kubeConfigData returns base64 encoded inline data, or the contents of the file
+// named by fileKey in extra
+func kubeConfigData(inline string, extra map[string]any, fileKey string) ([]byte, error) {
+	if inline != "" {
+		data, err := base64.StdEncoding.DecodeString(inline)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data: %w", err)
+		}
+		return data, nil
+	}
+
+	if path := extraString(extra, fileKey); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return data, nil
+	}
+
+	return nil, nil
+}
+
+// extraString returns a string field kept in an Extra map
+func extraString(extra map[string]any, key string) string {
+	value, _ := extra[key].(string)
+	return value
+}
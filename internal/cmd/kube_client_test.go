@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func newNamespaceServer(t *testing.T, token string) (*httptest.Server, string) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"kind":"NamespaceList","items":[{"metadata":{"name":"kube-system"}},{"metadata":{"name":"default"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	return server, base64.StdEncoding.EncodeToString(caData)
+}
+
+func namespaceKubeConfig(server, caData string, user KubeUserExec) *KubeConfig {
+	return &KubeConfig{
+		Clusters: []KubeCluster{{Name: "test", Cluster: KubeClusterConfig{Server: server + "/", CertificateAuthorityData: caData}}},
+		Contexts: []KubeContext{{Name: "test", Context: KubeContextConfig{Cluster: "test", User: "test"}}},
+		Users:    []KubeUser{{Name: "test", User: user}},
+	}
+}
+
+func TestKubeClientListNamespacesWithToken(t *testing.T) {
+	server, caData := newNamespaceServer(t, "secret")
+	ctx := context.Background()
+
+	kubeConfig := namespaceKubeConfig(server.URL, caData, KubeUserExec{Extra: map[string]any{"token": "secret"}})
+	client, err := newKubeClient(ctx, kubeConfig, "test")
+	if err != nil {
+		t.Fatalf("newKubeClient failed: %v", err)
+	}
+
+	namespaces, err := client.listNamespaces(ctx)
+	if err != nil {
+		t.Fatalf("listNamespaces failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(namespaces, []string{"default", "kube-system"}) {
+		t.Errorf("Unexpected namespaces: %v", namespaces)
+	}
+
+	// A wrong token is reported as rejected credentials
+	kubeConfig = namespaceKubeConfig(server.URL, caData, KubeUserExec{Extra: map[string]any{"token": "wrong"}})
+	client, err = newKubeClient(ctx, kubeConfig, "test")
+	if err != nil {
+		t.Fatalf("newKubeClient failed: %v", err)
+	}
+	if _, err := client.listNamespaces(ctx); err == nil {
+		t.Error("Expected error for rejected credentials")
+	}
+}
+
+func TestKubeClientExecPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exec plugin test uses a shell script")
+	}
+
+	server, caData := newNamespaceServer(t, "from-plugin")
+	ctx := context.Background()
+
+	plugin := filepath.Join(t.TempDir(), "plugin.sh")
+	script := "#!/bin/sh\necho '{\"kind\":\"ExecCredential\",\"status\":{\"token\":\"'\"$PLUGIN_TOKEN\"'\"}}'\n"
+	if err := os.WriteFile(plugin, []byte(script), 0700); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+
+	kubeConfig := namespaceKubeConfig(server.URL, caData, KubeUserExec{
+		Exec: KubeExecConfig{
+			APIVersion: execCredentialAPIVersion,
+			Command:    plugin,
+			Env:        []KubeExecEnvVar{{Name: "PLUGIN_TOKEN", Value: "from-plugin"}},
+		},
+	})
+
+	client, err := newKubeClient(ctx, kubeConfig, "test")
+	if err != nil {
+		t.Fatalf("newKubeClient failed: %v", err)
+	}
+
+	namespaces, err := client.listNamespaces(ctx)
+	if err != nil {
+		t.Fatalf("listNamespaces failed: %v", err)
+	}
+	if len(namespaces) != 2 {
+		t.Errorf("Expected 2 namespaces, got %v", namespaces)
+	}
+}
+
+func TestNewKubeClientErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		kubeConfig *KubeConfig
+	}{
+		{
+			name:       "missing context",
+			kubeConfig: &KubeConfig{},
+		},
+		{
+			name: "missing cluster",
+			kubeConfig: &KubeConfig{
+				Contexts: []KubeContext{{Name: "test", Context: KubeContextConfig{Cluster: "gone"}}},
+			},
+		},
+		{
+			name:       "invalid certificate authority",
+			kubeConfig: namespaceKubeConfig("https://example.com", "bm90IGEgY2VydA==", KubeUserExec{}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newKubeClient(ctx, tt.kubeConfig, "test"); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
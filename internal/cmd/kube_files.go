@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// kubeConfigFiles is a list of kubeconfig files read with kubectl's merge rules:
+// the first file to define a cluster, context or user wins, and so does the first
+// non-empty current-context. Missing files are skipped.
+type kubeConfigFiles struct {
+	Paths []string
+	files map[string]*KubeConfig
+}
+
+// kubeConfigPaths returns the kubeconfig files to use: an explicit path, the
+// KUBECONFIG path list, or ~/.kube/config
+func kubeConfigPaths(explicit string) ([]string, error) {
+	if explicit != "" {
+		return []string{expandHomePath(explicit)}, nil
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, path := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	if len(paths) > 0 {
+		return paths, nil
+	}
+
+	return defaultKubeConfigPaths()
+}
+
+// defaultKubeConfigPaths returns ~/.kube/config
+func defaultKubeConfigPaths() ([]string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return []string{filepath.Join(homeDir, ".kube", "config")}, nil
+}
+
+// loadKubeConfigFiles loads every existing file in the list. It fails if none exist.
+func loadKubeConfigFiles(paths []string) (*kubeConfigFiles, error) {
+	files := &kubeConfigFiles{Paths: paths, files: make(map[string]*KubeConfig)}
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		kubeConfig, err := loadKubeConfig(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig from %s: %w", path, err)
+		}
+		files.files[path] = kubeConfig
+	}
+
+	if len(files.files) == 0 {
+		return nil, fmt.Errorf("kubeconfig not found at %s", strings.Join(paths, string(os.PathListSeparator)))
+	}
+
+	return files, nil
+}
+
+// Merged returns the combined view of all files
+func (f *kubeConfigFiles) Merged() *KubeConfig {
+	merged := &KubeConfig{APIVersion: "v1", Kind: "Config"}
+	clusters := make(map[string]bool)
+	contexts := make(map[string]bool)
+	users := make(map[string]bool)
+
+	for _, path := range f.Paths {
+		kubeConfig, ok := f.files[path]
+		if !ok {
+			continue
+		}
+
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = kubeConfig.CurrentContext
+		}
+		if merged.Preferences == nil {
+			merged.Preferences = kubeConfig.Preferences
+		}
+
+		for _, cluster := range kubeConfig.Clusters {
+			if !clusters[cluster.Name] {
+				clusters[cluster.Name] = true
+				merged.Clusters = append(merged.Clusters, cluster)
+			}
+		}
+		for _, kubeContext := range kubeConfig.Contexts {
+			if !contexts[kubeContext.Name] {
+				contexts[kubeContext.Name] = true
+				merged.Contexts = append(merged.Contexts, kubeContext)
+			}
+		}
+		for _, user := range kubeConfig.Users {
+			if !users[user.Name] {
+				users[user.Name] = true
+				merged.Users = append(merged.Users, user)
+			}
+		}
+	}
+
+	return merged
+}
+
+// ContextFile returns the file that defines a context
+func (f *kubeConfigFiles) ContextFile(contextName string) (string, bool) {
+	for _, path := range f.Paths {
+		kubeConfig, ok := f.files[path]
+		if !ok {
+			continue
+		}
+		if _, err := findKubeContext(kubeConfig, contextName); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// DefaultFile returns the file kubectl writes the current context to: the first
+// existing file in the list, or the last one if none exist. Per-shell
+// kubeconfig files are skipped so that the shared configuration is changed.
+func (f *kubeConfigFiles) DefaultFile() string {
+	paths := sharedKubeConfigPaths(f.Paths)
+	if len(paths) == 0 {
+		paths = f.Paths
+	}
+
+	for _, path := range paths {
+		if _, ok := f.files[path]; ok {
+			return path
+		}
+	}
+	return paths[len(paths)-1]
+}
+
+// File returns the contents of a file in the list, starting an empty kubeconfig
+// if the file does not exist yet
+func (f *kubeConfigFiles) File(path string) *KubeConfig {
+	kubeConfig, ok := f.files[path]
+	if !ok {
+		kubeConfig = newKubeConfig()
+		f.files[path] = kubeConfig
+	}
+	return kubeConfig
+}
+
+// Save writes a file in the list
+func (f *kubeConfigFiles) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create kubeconfig directory: %w", err)
+	}
+	return saveKubeConfig(f.File(path), path)
+}
+
+// loadMergedKubeConfig loads the kubeconfig kubectl uses in this shell
+func loadMergedKubeConfig() (*KubeConfig, error) {
+	paths, err := kubeConfigPaths("")
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := loadKubeConfigFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	return files.Merged(), nil
+}
+
+// newKubeConfig returns an empty kubeconfig
+func newKubeConfig() *KubeConfig {
+	return &KubeConfig{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters:   []KubeCluster{},
+		Contexts:   []KubeContext{},
+		Users:      []KubeUser{},
+	}
+}
+
+// expandHomePath expands a leading ~ to the home directory
+func expandHomePath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+}
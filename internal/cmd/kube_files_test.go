@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeTestKubeConfig(t *testing.T, path string, kubeConfig *KubeConfig) {
+	t.Helper()
+	if err := saveKubeConfig(kubeConfig, path); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestKubeConfigPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	sep := string(os.PathListSeparator)
+	tests := []struct {
+		name       string
+		explicit   string
+		kubeconfig string
+		expected   []string
+	}{
+		{
+			name:     "default",
+			expected: []string{filepath.Join(home, ".kube", "config")},
+		},
+		{
+			name:       "list with empty and duplicate entries",
+			kubeconfig: "/a" + sep + sep + "/b" + sep + "/a",
+			expected:   []string{"/a", "/b"},
+		},
+		{
+			name:       "explicit path wins",
+			explicit:   "~/.kube/synacklab.yaml",
+			kubeconfig: "/a",
+			expected:   []string{filepath.Join(home, ".kube", "synacklab.yaml")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", tt.kubeconfig)
+
+			paths, err := kubeConfigPaths(tt.explicit)
+			if err != nil {
+				t.Fatalf("kubeConfigPaths failed: %v", err)
+			}
+			if !reflect.DeepEqual(paths, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, paths)
+			}
+		})
+	}
+}
+
+func TestKubeConfigFilesMerge(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first")
+	second := filepath.Join(dir, "second")
+	missing := filepath.Join(dir, "missing")
+
+	writeTestKubeConfig(t, first, &KubeConfig{
+		Clusters: []KubeCluster{{Name: "shared", Cluster: KubeClusterConfig{Server: "https://first"}}},
+		Contexts: []KubeContext{{Name: "dev", Context: KubeContextConfig{Cluster: "shared", Namespace: "first"}}},
+	})
+	writeTestKubeConfig(t, second, &KubeConfig{
+		CurrentContext: "prod",
+		Clusters: []KubeCluster{
+			{Name: "shared", Cluster: KubeClusterConfig{Server: "https://second"}},
+			{Name: "prod", Cluster: KubeClusterConfig{Server: "https://prod"}},
+		},
+		Contexts: []KubeContext{
+			{Name: "dev", Context: KubeContextConfig{Cluster: "shared", Namespace: "second"}},
+			{Name: "prod", Context: KubeContextConfig{Cluster: "prod"}},
+		},
+		Users: []KubeUser{{Name: "prod"}},
+	})
+
+	files, err := loadKubeConfigFiles([]string{missing, first, second})
+	if err != nil {
+		t.Fatalf("loadKubeConfigFiles failed: %v", err)
+	}
+
+	merged := files.Merged()
+	if merged.CurrentContext != "prod" {
+		t.Errorf("Expected the first non-empty current context, got %s", merged.CurrentContext)
+	}
+	if len(merged.Clusters) != 2 || merged.Clusters[0].Cluster.Server != "https://first" {
+		t.Errorf("Expected the first definition of a cluster to win, got %v", merged.Clusters)
+	}
+	if len(merged.Contexts) != 2 || merged.Contexts[0].Context.Namespace != "first" {
+		t.Errorf("Expected the first definition of a context to win, got %v", merged.Contexts)
+	}
+	if len(merged.Users) != 1 {
+		t.Errorf("Expected 1 user, got %d", len(merged.Users))
+	}
+
+	if path, ok := files.ContextFile("prod"); !ok || path != second {
+		t.Errorf("Expected prod to be defined in %s, got %s", second, path)
+	}
+	if path, ok := files.ContextFile("dev"); !ok || path != first {
+		t.Errorf("Expected dev to be defined in %s, got %s", first, path)
+	}
+
+	// The current context goes to the first existing file
+	if err := updateCurrentContext(files, "prod"); err != nil {
+		t.Fatalf("updateCurrentContext failed: %v", err)
+	}
+	saved, err := loadKubeConfig(first)
+	if err != nil {
+		t.Fatalf("Failed to reload kubeconfig: %v", err)
+	}
+	if saved.CurrentContext != "prod" {
+		t.Errorf("Expected current context to be written to the first file, got %q", saved.CurrentContext)
+	}
+	if err := updateCurrentContext(files, "missing"); err == nil {
+		t.Error("Expected error for unknown context")
+	}
+
+	if _, err := loadKubeConfigFiles([]string{missing}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestResolveEKSKubeconfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("KUBECONFIG", "")
+
+	path, err := resolveEKSKubeconfigPath("~/.kube/synacklab.yaml")
+	if err != nil {
+		t.Fatalf("resolveEKSKubeconfigPath failed: %v", err)
+	}
+	dedicated := filepath.Join(home, ".kube", "synacklab.yaml")
+	if path != dedicated {
+		t.Errorf("Expected %s, got %s", dedicated, path)
+	}
+	if hint := kubeConfigHint(dedicated); !strings.Contains(hint, "export KUBECONFIG=") {
+		t.Errorf("Expected a KUBECONFIG hint for a dedicated file, got %q", hint)
+	}
+
+	path, err = resolveEKSKubeconfigPath("")
+	if err != nil {
+		t.Fatalf("resolveEKSKubeconfigPath failed: %v", err)
+	}
+	if path != filepath.Join(home, ".kube", "config") {
+		t.Errorf("Expected ~/.kube/config by default, got %s", path)
+	}
+	if hint := kubeConfigHint(path); hint != "" {
+		t.Errorf("Expected no hint for the default kubeconfig, got %q", hint)
+	}
+
+	// The first existing file in KUBECONFIG is used, like kubectl
+	existing := filepath.Join(home, "existing")
+	writeTestKubeConfig(t, existing, newKubeConfig())
+	t.Setenv("KUBECONFIG", filepath.Join(home, "absent")+string(os.PathListSeparator)+existing)
+
+	path, err = resolveEKSKubeconfigPath("")
+	if err != nil {
+		t.Fatalf("resolveEKSKubeconfigPath failed: %v", err)
+	}
+	if path != existing {
+		t.Errorf("Expected %s, got %s", existing, path)
+	}
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// shellPIDEnv names the variable through which the shell hooks pass the PID
+// of the shell a per-shell kubeconfig belongs to
+const shellPIDEnv = "SYNACKLAB_SHELL_PID"
+
+// getShellKubeConfigDir returns the directory holding per-shell kubeconfig files
+func getShellKubeConfigDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".synacklab", "kube"), nil
+}
+
+// isShellKubeConfig reports whether a path is a per-shell kubeconfig
+func isShellKubeConfig(path string) bool {
+	dir, err := getShellKubeConfigDir()
+	if err != nil {
+		return false
+	}
+	return filepath.Dir(filepath.Clean(path)) == dir
+}
+
+// activeShellKubeConfigPath returns the per-shell kubeconfig at the front of
+// KUBECONFIG, if the current shell has one
+func activeShellKubeConfigPath() (string, bool) {
+	paths := filepath.SplitList(os.Getenv("KUBECONFIG"))
+	if len(paths) == 0 || !isShellKubeConfig(paths[0]) {
+		return "", false
+	}
+
+	if _, err := os.Stat(paths[0]); err != nil {
+		return "", false
+	}
+
+	return paths[0], true
+}
+
+// sharedKubeConfigPaths removes per-shell kubeconfig files from a list of paths
+func sharedKubeConfigPaths(paths []string) []string {
+	var shared []string
+	for _, path := range paths {
+		if !isShellKubeConfig(path) {
+			shared = append(shared, path)
+		}
+	}
+	return shared
+}
+
+// shellKubeConfigEnv returns the KUBECONFIG value that layers a per-shell
+// kubeconfig over the shared files
+func shellKubeConfigEnv(shellPath string, shared []string) string {
+	return strings.Join(append([]string{shellPath}, shared...), string(os.PathListSeparator))
+}
+
+// loadShellKubeConfig returns the per-shell kubeconfig of the current shell and
+// its path, or a new one with an empty path. With reuse unset a new one is
+// always returned.
+func loadShellKubeConfig(reuse bool) (*KubeConfig, string) {
+	if path, ok := activeShellKubeConfigPath(); ok && reuse {
+		if kubeConfig, err := loadKubeConfig(path); err == nil {
+			return kubeConfig, path
+		}
+	}
+
+	return newKubeConfig(), ""
+}
+
+// writeShellKubeConfig saves a per-shell kubeconfig. An empty path creates a new
+// file named after the shell it belongs to, and files left behind by shells
+// that have exited are removed.
+func writeShellKubeConfig(kubeConfig *KubeConfig, path string) (string, error) {
+	if path == "" {
+		dir, err := getShellKubeConfigDir()
+		if err != nil {
+			return "", err
+		}
+
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return "", fmt.Errorf("failed to create kubeconfig directory: %w", err)
+		}
+
+		pruneShellKubeConfigs(dir)
+
+		file, err := os.CreateTemp(dir, fmt.Sprintf("shell-%d-*.yaml", shellPID()))
+		if err != nil {
+			return "", fmt.Errorf("failed to create shell kubeconfig: %w", err)
+		}
+		path = file.Name()
+		file.Close()
+	}
+
+	if err := saveKubeConfig(kubeConfig, path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// shellPID returns the PID of the shell running synacklab: the one passed by
+// the shell hooks, or else the parent process
+func shellPID() int {
+	if pid, err := strconv.Atoi(os.Getenv(shellPIDEnv)); err == nil && pid > 0 {
+		return pid
+	}
+	return os.Getppid()
+}
+
+// shellKubeConfigOwner returns the PID of the shell a per-shell kubeconfig file
+// was created for, or false for files that do not record one
+func shellKubeConfigOwner(name string) (int, bool) {
+	rest, ok := strings.CutPrefix(name, "shell-")
+	if !ok {
+		return 0, false
+	}
+
+	owner, _, ok := strings.Cut(rest, "-")
+	if !ok {
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(owner)
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, true
+}
+
+// processRunning reports whether a process with the given PID exists
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	// On Windows finding the process already checked it exists
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// pruneShellKubeConfigs removes per-shell kubeconfig files whose shell has
+// exited. Files that do not record their shell are kept, as a long-lived shell
+// may still use them.
+func pruneShellKubeConfigs(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		pid, ok := shellKubeConfigOwner(entry.Name())
+		if !ok || processRunning(pid) {
+			continue
+		}
+
+		_ = os.Remove(filepath.Join(dir, entry.Name()))
+	}
+}
+
+// overrideContextNamespace copies a context into a per-shell kubeconfig, unless it
+// is already there, and sets its namespace. Since the per-shell kubeconfig comes
+// first in KUBECONFIG, its copy takes precedence for this shell only.
+func overrideContextNamespace(shellConfig, source *KubeConfig, contextName, namespace string) error {
+	if _, err := findKubeContext(shellConfig, contextName); err != nil {
+		kubeContext, err := findKubeContext(source, contextName)
+		if err != nil {
+			return err
+		}
+		shellConfig.Contexts = append(shellConfig.Contexts, *kubeContext)
+	}
+
+	return setContextNamespace(shellConfig, contextName, namespace)
+}
+
+// setContextNamespace sets the namespace of a context
+func setContextNamespace(kubeConfig *KubeConfig, contextName, namespace string) error {
+	kubeContext, err := findKubeContext(kubeConfig, contextName)
+	if err != nil {
+		return err
+	}
+
+	kubeContext.Context.Namespace = namespace
+	return nil
+}
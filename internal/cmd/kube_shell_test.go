@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOverrideContextNamespace(t *testing.T) {
+	source := &KubeConfig{
+		Contexts: []KubeContext{
+			{Name: "dev", Context: KubeContextConfig{Cluster: "dev", User: "dev"}},
+			{Name: "prod", Context: KubeContextConfig{Cluster: "prod", User: "prod", Namespace: "payments"}},
+		},
+	}
+	shellConfig := newKubeConfig()
+
+	if err := overrideContextNamespace(shellConfig, source, "prod", "kube-system"); err != nil {
+		t.Fatalf("overrideContextNamespace failed: %v", err)
+	}
+
+	if len(shellConfig.Contexts) != 1 {
+		t.Fatalf("Expected only the prod context to be copied, got %v", shellConfig.Contexts)
+	}
+	copied := shellConfig.Contexts[0].Context
+	if copied.Cluster != "prod" || copied.User != "prod" || copied.Namespace != "kube-system" {
+		t.Errorf("Unexpected copied context: %+v", copied)
+	}
+	if source.Contexts[1].Context.Namespace != "payments" {
+		t.Error("Source kubeconfig should not be modified")
+	}
+
+	// A second override changes the existing copy
+	if err := overrideContextNamespace(shellConfig, source, "prod", "monitoring"); err != nil {
+		t.Fatalf("overrideContextNamespace failed: %v", err)
+	}
+	if len(shellConfig.Contexts) != 1 || shellConfig.Contexts[0].Context.Namespace != "monitoring" {
+		t.Errorf("Expected the copy to be updated, got %v", shellConfig.Contexts)
+	}
+
+	if err := overrideContextNamespace(shellConfig, source, "missing", "default"); err == nil {
+		t.Error("Expected error for unknown context")
+	}
+}
+
+func TestShellKubeConfigLifecycle(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("KUBECONFIG", "")
+
+	sharedPath := filepath.Join(home, ".kube", "config")
+	if err := os.MkdirAll(filepath.Dir(sharedPath), 0755); err != nil {
+		t.Fatalf("Failed to create kube directory: %v", err)
+	}
+	shared := &KubeConfig{
+		CurrentContext: "dev",
+		Clusters:       []KubeCluster{{Name: "dev"}, {Name: "prod"}},
+		Contexts: []KubeContext{
+			{Name: "dev", Context: KubeContextConfig{Cluster: "dev", User: "dev"}},
+			{Name: "prod", Context: KubeContextConfig{Cluster: "prod", User: "prod"}},
+		},
+	}
+	if err := saveKubeConfig(shared, sharedPath); err != nil {
+		t.Fatalf("Failed to save kubeconfig: %v", err)
+	}
+
+	if _, ok := activeShellKubeConfigPath(); ok {
+		t.Error("No shell kubeconfig should be active without KUBECONFIG")
+	}
+
+	shellConfig, shellPath := loadShellKubeConfig(true)
+	if shellPath != "" {
+		t.Fatalf("Expected a new shell kubeconfig, got %s", shellPath)
+	}
+	shellConfig.CurrentContext = "prod"
+
+	shellPath, err := writeShellKubeConfig(shellConfig, "")
+	if err != nil {
+		t.Fatalf("writeShellKubeConfig failed: %v", err)
+	}
+
+	env := shellKubeConfigEnv(shellPath, []string{sharedPath})
+	if !strings.HasPrefix(env, shellPath+string(os.PathListSeparator)) {
+		t.Errorf("Shell kubeconfig should come first in KUBECONFIG, got %s", env)
+	}
+
+	t.Setenv("KUBECONFIG", env)
+	active, ok := activeShellKubeConfigPath()
+	if !ok || active != shellPath {
+		t.Fatalf("Expected %s to be the active shell kubeconfig", shellPath)
+	}
+
+	merged, err := loadMergedKubeConfig()
+	if err != nil {
+		t.Fatalf("loadMergedKubeConfig failed: %v", err)
+	}
+	if merged.CurrentContext != "prod" || len(merged.Contexts) != 2 {
+		t.Errorf("Expected the shell context over the shared contexts, got %s with %d contexts", merged.CurrentContext, len(merged.Contexts))
+	}
+
+	if _, reused := loadShellKubeConfig(true); reused != shellPath {
+		t.Errorf("Expected the active shell kubeconfig to be reused, got %s", reused)
+	}
+	if _, reused := loadShellKubeConfig(false); reused != "" {
+		t.Error("Expected a new shell kubeconfig when reuse is disabled")
+	}
+
+	// The shared files never include the shell kubeconfig
+	paths, err := kubeConfigPaths("")
+	if err != nil {
+		t.Fatalf("kubeConfigPaths failed: %v", err)
+	}
+	if got := sharedKubeConfigPaths(paths); len(got) != 1 || got[0] != sharedPath {
+		t.Errorf("Unexpected shared paths: %v", got)
+	}
+
+	// Other KUBECONFIG values are left alone
+	t.Setenv("KUBECONFIG", sharedPath+string(os.PathListSeparator)+shellPath)
+	if _, ok := activeShellKubeConfigPath(); ok {
+		t.Error("A shell kubeconfig that is not first in KUBECONFIG should not be active")
+	}
+}
+
+func TestPruneShellKubeConfigs(t *testing.T) {
+	dir := t.TempDir()
+
+	// A process that has exited stands for a closed shell
+	exited := exec.Command(os.Args[0], "-test.run=^$")
+	if err := exited.Run(); err != nil {
+		t.Fatalf("Failed to run process: %v", err)
+	}
+
+	closed := filepath.Join(dir, fmt.Sprintf("shell-%d-123.yaml", exited.Process.Pid))
+	running := filepath.Join(dir, fmt.Sprintf("shell-%d-456.yaml", os.Getpid()))
+	unowned := filepath.Join(dir, "shell-789.yaml")
+	other := filepath.Join(dir, "notes.yaml")
+	for _, path := range []string{closed, running, unowned, other} {
+		if err := os.WriteFile(path, []byte("kind: Config\n"), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	// Files of running shells are kept however long they have been unused
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	if err := os.Chtimes(running, old, old); err != nil {
+		t.Fatalf("Failed to age file: %v", err)
+	}
+
+	pruneShellKubeConfigs(dir)
+
+	if _, err := os.Stat(closed); !os.IsNotExist(err) {
+		t.Error("Shell kubeconfig of an exited shell should be removed")
+	}
+	if _, err := os.Stat(running); err != nil {
+		t.Error("Shell kubeconfig of a running shell should be kept")
+	}
+	if _, err := os.Stat(unowned); err != nil {
+		t.Error("Shell kubeconfig without an owning shell should be kept")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Error("Files not created by synacklab should be kept")
+	}
+}
+
+func TestWriteShellKubeConfigNamesShell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(shellPIDEnv, strconv.Itoa(os.Getpid()))
+
+	path, err := writeShellKubeConfig(newKubeConfig(), "")
+	if err != nil {
+		t.Fatalf("writeShellKubeConfig failed: %v", err)
+	}
+
+	if pid, ok := shellKubeConfigOwner(filepath.Base(path)); !ok || pid != os.Getpid() {
+		t.Errorf("Expected %s to be owned by PID %d, got %d", path, os.Getpid(), pid)
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"synacklab/pkg/fuzzy"
+	"synacklab/pkg/state"
+)
+
+// loadPickerState loads the selection history. Problems with the state file are
+// reported as warnings to out so that they never prevent switching context.
+func loadPickerState(out io.Writer) *state.Store {
+	path, err := state.DefaultPath()
+	if err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: %v\n", err)
+		return state.New("")
+	}
+
+	store, err := state.Load(path)
+	if err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: ignoring selection history: %v\n", err)
+	}
+
+	return store
+}
+
+// recordSelection stores a selection in the history
+func recordSelection(out io.Writer, store *state.Store, picker, value string) {
+	store.Record(picker, value, time.Now())
+	if err := store.Save(); err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: failed to save selection history: %v\n", err)
+	}
+}
+
+// rankOptions orders picker options by favourites and usage history and
+// marks favourites in their description
+func rankOptions(store *state.Store, picker string, options []fuzzy.Option) []fuzzy.Option {
+	byValue := make(map[string]fuzzy.Option, len(options))
+	values := make([]string, 0, len(options))
+	for _, option := range options {
+		byValue[option.Value] = option
+		values = append(values, option.Value)
+	}
+
+	ranked := make([]fuzzy.Option, 0, len(options))
+	for _, value := range store.Rank(picker, values, time.Now()) {
+		option := byValue[value]
+		if store.IsFavourite(picker, value) {
+			option.Description = "⭐ " + option.Description
+		}
+		ranked = append(ranked, option)
+	}
+
+	return ranked
+}
+
+// updateFavourite pins or unpins a value and saves the history
+func updateFavourite(out io.Writer, store *state.Store, picker, value string, pin bool) error {
+	if pin {
+		if store.Pin(picker, value) {
+			fmt.Fprintf(out, "⭐ Pinned '%s' to favourites\n", value)
+		} else {
+			fmt.Fprintf(out, "ℹ️  '%s' is already a favourite\n", value)
+		}
+	} else {
+		if store.Unpin(picker, value) {
+			fmt.Fprintf(out, "🗑️  Removed '%s' from favourites\n", value)
+		} else {
+			fmt.Fprintf(out, "ℹ️  '%s' is not a favourite\n", value)
+		}
+	}
+
+	if err := store.Save(); err != nil {
+		return fmt.Errorf("failed to save favourites: %w", err)
+	}
+
+	return nil
+}
+
+// previousSelection returns the value to switch back to for "-"
+func previousSelection(store *state.Store, picker, kind string) (string, error) {
+	previous := store.Previous(picker)
+	if previous == "" {
+		return "", fmt.Errorf("no previous %s to switch back to", kind)
+	}
+	return previous, nil
+}
+
+// validateQueryFlags checks the combination of a positional selection, --query and --first
+func validateQueryFlags(args []string, query string, first bool) error {
+	if first && query == "" {
+		return fmt.Errorf("--first requires --query")
+	}
+	if query != "" && len(args) > 0 {
+		return fmt.Errorf("--query cannot be combined with the %q argument", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"synacklab/pkg/fuzzy"
+	"synacklab/pkg/state"
+)
+
+func TestRankOptions(t *testing.T) {
+	store := state.New("")
+	store.Record(state.PickerKubeContext, "staging", time.Now())
+	store.Pin(state.PickerKubeContext, "prod")
+
+	options := []fuzzy.Option{
+		{Value: "dev", Description: "Cluster: dev"},
+		{Value: "prod", Description: "Cluster: prod"},
+		{Value: "staging", Description: "Cluster: staging"},
+	}
+
+	ranked := rankOptions(store, state.PickerKubeContext, options)
+
+	expected := []string{"prod", "staging", "dev"}
+	if len(ranked) != len(expected) {
+		t.Fatalf("Expected %d options, got %d", len(expected), len(ranked))
+	}
+	for i, value := range expected {
+		if ranked[i].Value != value {
+			t.Errorf("Position %d: expected %s, got %s", i, value, ranked[i].Value)
+		}
+	}
+
+	if ranked[0].Description != "⭐ Cluster: prod" {
+		t.Errorf("Expected favourite marker, got %q", ranked[0].Description)
+	}
+	if options[1].Description != "Cluster: prod" {
+		t.Error("rankOptions should not modify the input options")
+	}
+}
+
+func TestPreviousSelection(t *testing.T) {
+	store := state.New("")
+
+	if _, err := previousSelection(store, state.PickerAWSProfile, "AWS profile"); err == nil {
+		t.Error("Expected error without history")
+	}
+
+	store.Record(state.PickerAWSProfile, "dev", time.Now())
+	store.Record(state.PickerAWSProfile, "prod", time.Now())
+
+	previous, err := previousSelection(store, state.PickerAWSProfile, "AWS profile")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if previous != "dev" {
+		t.Errorf("Expected dev, got %s", previous)
+	}
+}
+
+func TestUpdateFavourite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := state.New(path)
+
+	if err := updateFavourite(io.Discard, store, state.PickerAWSProfile, "prod", true); err != nil {
+		t.Fatalf("Failed to pin: %v", err)
+	}
+
+	loaded, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if !loaded.IsFavourite(state.PickerAWSProfile, "prod") {
+		t.Error("Expected pinned profile to be saved")
+	}
+
+	if err := updateFavourite(io.Discard, loaded, state.PickerAWSProfile, "prod", false); err != nil {
+		t.Fatalf("Failed to unpin: %v", err)
+	}
+	if loaded.IsFavourite(state.PickerAWSProfile, "prod") {
+		t.Error("Expected profile to be unpinned")
+	}
+}
+
+func TestValidateQueryFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		query   string
+		first   bool
+		wantErr bool
+	}{
+		{name: "picker"},
+		{name: "query", query: "prod"},
+		{name: "query and first", query: "prod", first: true},
+		{name: "positional argument", args: []string{"prod"}},
+		{name: "first without query", first: true, wantErr: true},
+		{name: "query and argument", args: []string{"prod"}, query: "prod", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateQueryFlags(tt.args, tt.query, tt.first)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateQueryFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/ini.v1"
+
+	"synacklab/internal/auth"
+)
+
+// defaultPromptFormat renders "aws:<profile> (<expiry>) k8s:<context>", omitting empty segments
+const defaultPromptFormat = `{{if .AWSProfile}}aws:{{.AWSProfile}}{{if .AWSExpiry}} ({{.AWSExpiry}}){{end}}{{end}}` +
+	`{{if and .AWSProfile .KubeContext}} {{end}}` +
+	`{{if .KubeContext}}k8s:{{.KubeContext}}{{if .KubeNamespace}}/{{.KubeNamespace}}{{end}}{{end}}`
+
+var promptFormat string
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the active AWS profile and Kubernetes context for shell prompts",
+	Long: `Print the active AWS profile, SSO session expiry and Kubernetes context on one line.
+
+The command only reads local files and environment variables, so it is fast enough
+to run on every prompt. Missing information is left out silently.
+
+The output is rendered with a Go template. Available fields:
+  .AWSProfile     Active AWS profile (SYNACKLAB_PROFILE, AWS_PROFILE or the profile copied to [default])
+  .AWSExpiry      Remaining credential or SSO session lifetime, e.g. "7h12m" or "expired"
+  .AWSExpired     true when the credentials or SSO session have expired
+  .KubeContext    Current kubeconfig context
+  .KubeNamespace  Namespace of the current context
+
+Examples:
+  # bash
+  PS1='$(synacklab prompt) \$ '
+
+  # zsh
+  setopt PROMPT_SUBST; PROMPT='$(synacklab prompt) %# '
+
+  # starship (~/.config/starship.toml)
+  [custom.synacklab]
+  command = "synacklab prompt"
+  when = true
+
+  synacklab prompt --format '{{.AWSProfile}}{{if .AWSExpired}} ⚠️{{end}}'`,
+	RunE: runPrompt,
+}
+
+func init() {
+	promptCmd.Flags().StringVar(&promptFormat, "format", defaultPromptFormat, "Go template used to render the prompt segment")
+}
+
+// promptInfo holds the context rendered by the prompt command
+type promptInfo struct {
+	AWSProfile    string
+	AWSExpiry     string
+	AWSExpired    bool
+	KubeContext   string
+	KubeNamespace string
+}
+
+func runPrompt(_ *cobra.Command, _ []string) error {
+	tmpl, err := template.New("prompt").Parse(promptFormat)
+	if err != nil {
+		return fmt.Errorf("invalid prompt format: %w", err)
+	}
+
+	info := collectPromptInfo(time.Now())
+
+	if err := tmpl.Execute(os.Stdout, info); err != nil {
+		return fmt.Errorf("failed to render prompt: %w", err)
+	}
+
+	return nil
+}
+
+// collectPromptInfo gathers prompt details from the environment and local files
+func collectPromptInfo(now time.Time) promptInfo {
+	var info promptInfo
+
+	info.AWSProfile = activeAWSProfile()
+
+	if expiresAt, ok := activeAWSExpiry(); ok {
+		info.AWSExpired = now.After(expiresAt)
+		if info.AWSExpired {
+			info.AWSExpiry = "expired"
+		} else {
+			info.AWSExpiry = formatDuration(expiresAt.Sub(now))
+		}
+	}
+
+	if kubeConfig, err := loadMergedKubeConfig(); err == nil {
+		info.KubeContext = kubeConfig.CurrentContext
+		for _, kubeContext := range kubeConfig.Contexts {
+			if kubeContext.Name == kubeConfig.CurrentContext {
+				info.KubeNamespace = kubeContext.Context.Namespace
+				break
+			}
+		}
+	}
+
+	return info
+}
+
+// activeAWSProfile returns the AWS profile the current shell is using
+func activeAWSProfile() string {
+	for _, name := range []string{"SYNACKLAB_PROFILE", "AWS_PROFILE", "AWS_DEFAULT_PROFILE"} {
+		if profile := os.Getenv(name); profile != "" {
+			return profile
+		}
+	}
+
+	configPath, err := getAWSConfigPath()
+	if err != nil {
+		return ""
+	}
+
+	cfg, err := ini.Load(configPath)
+	if err != nil {
+		return ""
+	}
+
+	return matchDefaultProfile(cfg)
+}
+
+// matchDefaultProfile finds the named profile that 'aws-ctx' copied into [default]
+func matchDefaultProfile(cfg *ini.File) string {
+	if !cfg.HasSection("default") {
+		return ""
+	}
+
+	defaultSection := cfg.Section("default")
+	accountID := defaultSection.Key("sso_account_id").String()
+	roleName := defaultSection.Key("sso_role_name").String()
+	roleARN := defaultSection.Key("role_arn").String()
+	sourceProfile := defaultSection.Key("source_profile").String()
+
+	if (accountID == "" || roleName == "") && roleARN == "" {
+		return ""
+	}
+
+	for _, profile := range parseAWSProfiles(cfg) {
+		if profile.accountID == accountID && profile.roleName == roleName &&
+			profile.roleARN == roleARN && profile.sourceProfile == sourceProfile {
+			return profile.name
+		}
+	}
+
+	return "default"
+}
+
+// activeAWSExpiry returns when the active credentials expire. Credentials exported
+// by 'synacklab exec' take precedence over the stored SSO session.
+func activeAWSExpiry() (time.Time, bool) {
+	if value := strings.TrimSpace(os.Getenv("AWS_CREDENTIAL_EXPIRATION")); value != "" {
+		if expiresAt, err := time.Parse(time.RFC3339, value); err == nil {
+			return expiresAt, true
+		}
+	}
+
+	authManager, err := auth.NewManager()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	session, err := authManager.GetStoredCredentials()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return session.ExpiresAt, true
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+func TestPromptCommand(t *testing.T) {
+	if promptCmd.Use != "prompt" {
+		t.Errorf("Expected command use to be 'prompt', got '%s'", promptCmd.Use)
+	}
+
+	formatFlag := promptCmd.Flags().Lookup("format")
+	if formatFlag == nil {
+		t.Fatal("format flag should be defined")
+	}
+	if formatFlag.DefValue != defaultPromptFormat {
+		t.Errorf("Unexpected default format: %s", formatFlag.DefValue)
+	}
+}
+
+func TestDefaultPromptFormat(t *testing.T) {
+	tmpl := template.Must(template.New("prompt").Parse(defaultPromptFormat))
+
+	tests := []struct {
+		name     string
+		info     promptInfo
+		expected string
+	}{
+		{name: "empty", info: promptInfo{}, expected: ""},
+		{
+			name:     "aws only",
+			info:     promptInfo{AWSProfile: "prod-admin", AWSExpiry: "7h12m"},
+			expected: "aws:prod-admin (7h12m)",
+		},
+		{
+			name:     "kube only",
+			info:     promptInfo{KubeContext: "prod-eks", KubeNamespace: "payments"},
+			expected: "k8s:prod-eks/payments",
+		},
+		{
+			name:     "both",
+			info:     promptInfo{AWSProfile: "dev", AWSExpiry: "expired", AWSExpired: true, KubeContext: "dev-eks"},
+			expected: "aws:dev (expired) k8s:dev-eks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, tt.info); err != nil {
+				t.Fatalf("Failed to render prompt: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestMatchDefaultProfile(t *testing.T) {
+	cfg, err := ini.Load([]byte(`
+[default]
+sso_account_id = 222222222222
+sso_role_name = ReadOnly
+region = us-east-1
+
+[profile dev-admin]
+sso_account_id = 111111111111
+sso_role_name = Admin
+
+[profile prod-readonly]
+sso_account_id = 222222222222
+sso_role_name = ReadOnly
+`))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if got := matchDefaultProfile(cfg); got != "prod-readonly" {
+		t.Errorf("Expected prod-readonly, got %q", got)
+	}
+
+	cfg.Section("default").Key("sso_account_id").SetValue("999999999999")
+	if got := matchDefaultProfile(cfg); got != "default" {
+		t.Errorf("Expected default for unmatched profile, got %q", got)
+	}
+
+	cfg.DeleteSection("default")
+	if got := matchDefaultProfile(cfg); got != "" {
+		t.Errorf("Expected no profile without a default section, got %q", got)
+	}
+}
+
+func TestCollectPromptInfo(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SYNACKLAB_PROFILE", "")
+	t.Setenv("AWS_DEFAULT_PROFILE", "")
+	t.Setenv("AWS_PROFILE", "staging")
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	t.Setenv("AWS_CREDENTIAL_EXPIRATION", now.Add(45*time.Minute).Format(time.RFC3339))
+
+	kubeDir := filepath.Join(home, ".kube")
+	if err := os.MkdirAll(kubeDir, 0755); err != nil {
+		t.Fatalf("Failed to create kube dir: %v", err)
+	}
+	kubeConfig := `apiVersion: v1
+kind: Config
+current-context: staging-eks
+contexts:
+- name: staging-eks
+  context:
+    cluster: staging-eks
+    user: staging-eks
+    namespace: web
+`
+	if err := os.WriteFile(filepath.Join(kubeDir, "config"), []byte(kubeConfig), 0600); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+
+	info := collectPromptInfo(now)
+
+	if info.AWSProfile != "staging" {
+		t.Errorf("Expected AWS profile staging, got %q", info.AWSProfile)
+	}
+	if info.AWSExpiry != "45m" || info.AWSExpired {
+		t.Errorf("Expected 45m remaining, got %q (expired=%v)", info.AWSExpiry, info.AWSExpired)
+	}
+	if info.KubeContext != "staging-eks" || info.KubeNamespace != "web" {
+		t.Errorf("Unexpected kube context %q/%q", info.KubeContext, info.KubeNamespace)
+	}
+}
@@ -1,10 +1,19 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"synacklab/pkg/fuzzy"
+)
+
+// Exit codes that let scripts tell why a --query selection failed
+const (
+	exitNoMatch   = 3
+	exitAmbiguous = 4
 )
 
 var rootCmd = &cobra.Command{
@@ -13,18 +22,38 @@ var rootCmd = &cobra.Command{
 	Long: `Synacklab is a command-line tool designed for DevOps engineers to simplify
 AWS SSO authentication and profile management. It helps you authenticate with AWS SSO,
 sync all available profiles, and set default configurations.`,
+	// Errors are reported once by Execute, on stderr
+	SilenceErrors: true,
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode returns the process exit code for an error returned by a command
+func exitCode(err error) int {
+	var ambiguous *fuzzy.AmbiguousError
+	switch {
+	case errors.Is(err, fuzzy.ErrNoMatch):
+		return exitNoMatch
+	case errors.As(err, &ambiguous):
+		return exitAmbiguous
+	default:
+		return 1
 	}
 }
 
 func init() {
 	rootCmd.AddCommand(authCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(eksCmd)
 	rootCmd.AddCommand(eksConfigCmd)
+	rootCmd.AddCommand(eksNsCmd)
+	rootCmd.AddCommand(execCmd)
 	rootCmd.AddCommand(githubCmd)
+	rootCmd.AddCommand(promptCmd)
+	rootCmd.AddCommand(shellInitCmd)
 }
@@ -2,7 +2,11 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"testing"
+
+	"synacklab/pkg/fuzzy"
 )
 
 func TestRootCommand(t *testing.T) {
@@ -15,6 +19,11 @@ func TestRootCommand(t *testing.T) {
 		t.Errorf("Unexpected Short description: %s", rootCmd.Short)
 	}
 
+	// Errors are printed by Execute so that stdout stays clean for --print and --shell
+	if !rootCmd.SilenceErrors {
+		t.Error("Expected root command to silence cobra's error output")
+	}
+
 	// Test that auth command is added
 	authCmdFound := false
 	initCmdFound := false
@@ -66,3 +75,23 @@ func TestExecuteFunction(t *testing.T) {
 	// We can't easily test the actual execution without mocking os.Exit
 	t.Log("Execute function exists and is callable")
 }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "generic error", err: errors.New("boom"), want: 1},
+		{name: "no match", err: fmt.Errorf("select: %w", fuzzy.ErrNoMatch), want: exitNoMatch},
+		{name: "ambiguous", err: fmt.Errorf("select: %w", &fuzzy.AmbiguousError{Query: "prod"}), want: exitAmbiguous},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init <bash|zsh|fish>",
+	Short: "Print shell integration hooks",
+	Long: `Print shell functions that make context switching local to the current shell.
+
+Once installed, 'synacklab auth aws-ctx' sets AWS_PROFILE in the running shell
+instead of rewriting the [default] profile in ~/.aws/config, so each terminal can
+work against a different account. Likewise 'synacklab auth eks-ctx' and
+'synacklab eks-ns' point KUBECONFIG at a per-shell kubeconfig instead of changing
+~/.kube/config. Prefix a command with 'command' to change the shared
+configuration for every shell.
+
+Installation:
+  bash:  echo 'eval "$(synacklab shell-init bash)"' >> ~/.bashrc
+  zsh:   echo 'eval "$(synacklab shell-init zsh)"' >> ~/.zshrc
+  fish:  echo 'synacklab shell-init fish | source' >> ~/.config/fish/config.fish
+
+Combine with 'synacklab prompt' to show the active context in your prompt.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE:      runShellInit,
+}
+
+const posixShellHook = `# synacklab shell integration (%s)
+synacklab() {
+  local __synacklab_env __synacklab_arg
+  # --print writes a name to stdout for the caller instead of switching
+  for __synacklab_arg in "$@"; do
+    case "$__synacklab_arg" in
+      --print|--print=true)
+        command synacklab "$@"
+        return $?
+        ;;
+    esac
+  done
+  case "$1 $2" in
+    "auth aws-ctx")
+      shift 2
+      __synacklab_env="$(command synacklab auth aws-ctx --shell "$@")" || return $?
+      ;;
+    "auth eks-ctx")
+      shift 2
+      __synacklab_env="$(SYNACKLAB_SHELL_PID=$$ command synacklab auth eks-ctx --shell "$@")" || return $?
+      ;;
+    "eks-ns "*)
+      shift
+      __synacklab_env="$(SYNACKLAB_SHELL_PID=$$ command synacklab eks-ns --shell "$@")" || return $?
+      ;;
+    *)
+      command synacklab "$@"
+      return $?
+      ;;
+  esac
+  eval "$__synacklab_env"
+}
+`
+
+const fishShellHook = `# synacklab shell integration (fish)
+function synacklab --wraps synacklab
+    set -l __synacklab_env
+    # --print writes a name to stdout for the caller instead of switching
+    if contains -- --print $argv; or contains -- --print=true $argv
+        command synacklab $argv
+        return $status
+    else if test (count $argv) -ge 2; and test "$argv[1]" = auth; and test "$argv[2]" = aws-ctx
+        set __synacklab_env (command synacklab auth aws-ctx --shell $argv[3..-1]); or return $status
+    else if test (count $argv) -ge 2; and test "$argv[1]" = auth; and test "$argv[2]" = eks-ctx
+        set __synacklab_env (SYNACKLAB_SHELL_PID=$fish_pid command synacklab auth eks-ctx --shell $argv[3..-1]); or return $status
+    else if test (count $argv) -ge 1; and test "$argv[1]" = eks-ns
+        set __synacklab_env (SYNACKLAB_SHELL_PID=$fish_pid command synacklab eks-ns --shell $argv[2..-1]); or return $status
+    else
+        command synacklab $argv
+        return $status
+    end
+    string join \n -- $__synacklab_env | source
+end
+`
+
+func runShellInit(_ *cobra.Command, args []string) error {
+	script, err := shellInitScript(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(script)
+	return nil
+}
+
+// shellInitScript returns the integration hooks for the given shell
+func shellInitScript(shell string) (string, error) {
+	switch shell {
+	case "bash", "zsh":
+		return fmt.Sprintf(posixShellHook, shell), nil
+	case "fish":
+		return fishShellHook, nil
+	default:
+		return "", fmt.Errorf("unsupported shell: %s (supported: bash, zsh, fish)", shell)
+	}
+}
+
+// progressOutput returns the writer for progress messages: stderr when stdout
+// is consumed by the caller, as with --shell and --print, and stdout otherwise
+func progressOutput(stdoutConsumed bool) io.Writer {
+	if stdoutConsumed {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// formatShellExport renders an export statement understood by bash, zsh and fish
+func formatShellExport(name, value string) string {
+	return fmt.Sprintf("export %s=%s", name, shellQuote(value))
+}
+
+// shellQuote wraps a value in single quotes so it is taken literally by the shell
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestShellInitCommand(t *testing.T) {
+	if shellInitCmd.Use != "shell-init <bash|zsh|fish>" {
+		t.Errorf("Unexpected command use: %s", shellInitCmd.Use)
+	}
+
+	found := false
+	for _, cmd := range rootCmd.Commands() {
+		if cmd == shellInitCmd {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("shell-init command not registered on root command")
+	}
+
+	for _, cmd := range []*cobra.Command{awsCtxCmd, eksCtxCmd, eksNsCmd} {
+		if cmd.Flags().Lookup("shell") == nil {
+			t.Errorf("%s should define the shell flag", cmd.Name())
+		}
+	}
+}
+
+func TestShellInitScript(t *testing.T) {
+	tests := []struct {
+		shell    string
+		contains []string
+		wantErr  bool
+	}{
+		{
+			shell:    "bash",
+			contains: []string{"synacklab()", "aws-ctx --shell", "eks-ctx --shell", "eks-ns --shell", "SYNACKLAB_SHELL_PID=$$", "eval", "command synacklab \"$@\""},
+		},
+		{
+			shell:    "zsh",
+			contains: []string{"(zsh)", "synacklab()", "aws-ctx --shell"},
+		},
+		{
+			shell:    "fish",
+			contains: []string{"function synacklab", "aws-ctx --shell", "eks-ctx --shell", "eks-ns --shell", "SYNACKLAB_SHELL_PID=$fish_pid", "| source"},
+		},
+		{
+			shell:   "powershell",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			script, err := shellInitScript(tt.shell)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error for unsupported shell")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(script, want) {
+					t.Errorf("Expected %s script to contain %q", tt.shell, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatShellExport(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{value: "prod-admin", expected: "export AWS_PROFILE='prod-admin'"},
+		{value: "it's", expected: `export AWS_PROFILE='it'\''s'`},
+		{value: "$(rm -rf /)", expected: "export AWS_PROFILE='$(rm -rf /)'"},
+	}
+
+	for _, tt := range tests {
+		if got := formatShellExport("AWS_PROFILE", tt.value); got != tt.expected {
+			t.Errorf("formatShellExport(%q) = %q, want %q", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestProgressOutput(t *testing.T) {
+	if progressOutput(true) != os.Stderr {
+		t.Error("Expected progress on stderr when stdout is consumed")
+	}
+	if progressOutput(false) != os.Stdout {
+		t.Error("Expected progress on stdout")
+	}
+}
+
+func TestShellInitScriptPrint(t *testing.T) {
+	// A stand-in for synacklab that prints its arguments, which the hook
+	// would fail to eval
+	bin := t.TempDir()
+	fake := "#!/bin/sh\necho \"$*\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "synacklab"), []byte(fake), 0755); err != nil {
+		t.Fatalf("Failed to write fake synacklab: %v", err)
+	}
+
+	tests := []struct {
+		shell   string
+		command string
+	}{
+		{"bash", `synacklab auth aws-ctx --query prod --print; echo "captured: $(synacklab auth eks-ctx --print)"`},
+		{"zsh", `synacklab auth aws-ctx --query prod --print; echo "captured: $(synacklab auth eks-ctx --print)"`},
+		{"fish", `synacklab auth aws-ctx --query prod --print; echo "captured: "(synacklab auth eks-ctx --print)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			shellPath, err := exec.LookPath(tt.shell)
+			if err != nil {
+				t.Skipf("%s is not installed", tt.shell)
+			}
+
+			script, err := shellInitScript(tt.shell)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			cmd := exec.Command(shellPath, "-c", script+tt.command)
+			cmd.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("%s hook failed: %v\n%s", tt.shell, err, output)
+			}
+
+			want := "auth aws-ctx --query prod --print\ncaptured: auth eks-ctx --print\n"
+			if string(output) != want {
+				t.Errorf("%s hook printed %q, want %q", tt.shell, output, want)
+			}
+		})
+	}
+}
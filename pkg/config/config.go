@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,7 +17,9 @@ type Config struct {
 
 // AWSConfig represents AWS-specific configuration
 type AWSConfig struct {
-	SSO SSOConfig `yaml:"sso"`
+	SSO             SSOConfig        `yaml:"sso"`
+	ChainedProfiles []ChainedProfile `yaml:"chained_profiles,omitempty"`
+	EKS             EKSConfig        `yaml:"eks,omitempty"`
 }
 
 // SSOConfig represents AWS SSO configuration
@@ -25,6 +28,32 @@ type SSOConfig struct {
 	Region   string `yaml:"region"`
 }
 
+// ChainedProfile represents a profile that assumes a role from another profile
+type ChainedProfile struct {
+	Name            string `yaml:"name"`
+	SourceProfile   string `yaml:"source_profile"`
+	RoleARN         string `yaml:"role_arn"`
+	ExternalID      string `yaml:"external_id,omitempty"`
+	SessionName     string `yaml:"session_name,omitempty"`
+	MFASerial       string `yaml:"mfa_serial,omitempty"`
+	Region          string `yaml:"region,omitempty"`
+	DurationSeconds int    `yaml:"duration_seconds,omitempty"`
+}
+
+// EKSConfig represents how EKS clusters are written to kubeconfig
+type EKSConfig struct {
+	// ContextTemplate names contexts using ${cluster}, ${region}, ${profile} and ${account}
+	ContextTemplate  string `yaml:"context_template,omitempty"`
+	DefaultNamespace string `yaml:"default_namespace,omitempty"`
+	// Aliases maps a cluster ARN or generated context name to a custom context name
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+	// Kubeconfig is the file discovered clusters are written to, e.g. ~/.kube/synacklab.yaml
+	Kubeconfig string `yaml:"kubeconfig,omitempty"`
+}
+
+// EKSContextPlaceholders lists the placeholders supported in context templates
+var EKSContextPlaceholders = []string{"cluster", "region", "profile", "account"}
+
 // GitHubConfig represents GitHub-specific configuration
 type GitHubConfig struct {
 	Token        string `yaml:"token,omitempty"`
@@ -102,7 +131,15 @@ func GetConfigPath() (string, error) {
 
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	return c.ValidateAWS()
+	if err := c.ValidateAWS(); err != nil {
+		return err
+	}
+
+	if err := c.ValidateChainedProfiles(); err != nil {
+		return err
+	}
+
+	return c.ValidateEKS()
 }
 
 // ValidateAWS validates AWS-specific configuration
@@ -118,6 +155,79 @@ func (c *Config) ValidateAWS() error {
 	return nil
 }
 
+// ValidateChainedProfiles validates chained assume-role profiles
+func (c *Config) ValidateChainedProfiles() error {
+	names := make(map[string]bool)
+	for i, profile := range c.AWS.ChainedProfiles {
+		if profile.Name == "" {
+			return fmt.Errorf("chained profile %d: name is required", i+1)
+		}
+		if names[profile.Name] {
+			return fmt.Errorf("chained profile %s: duplicate profile name", profile.Name)
+		}
+		names[profile.Name] = true
+
+		if profile.SourceProfile == "" {
+			return fmt.Errorf("chained profile %s: source_profile is required", profile.Name)
+		}
+		if profile.SourceProfile == profile.Name {
+			return fmt.Errorf("chained profile %s: source_profile cannot reference itself", profile.Name)
+		}
+		if !strings.HasPrefix(profile.RoleARN, "arn:") || !strings.Contains(profile.RoleARN, ":role/") {
+			return fmt.Errorf("chained profile %s: role_arn must be a valid IAM role ARN", profile.Name)
+		}
+		if profile.MFASerial != "" && !strings.HasPrefix(profile.MFASerial, "arn:") {
+			return fmt.Errorf("chained profile %s: mfa_serial must be an MFA device ARN", profile.Name)
+		}
+		if profile.DurationSeconds != 0 && (profile.DurationSeconds < 900 || profile.DurationSeconds > 43200) {
+			return fmt.Errorf("chained profile %s: duration_seconds must be between 900 and 43200", profile.Name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateEKS validates the EKS kubeconfig settings
+func (c *Config) ValidateEKS() error {
+	if template := c.AWS.EKS.ContextTemplate; template != "" {
+		if !strings.Contains(template, "${cluster}") {
+			return fmt.Errorf("eks context_template must contain ${cluster}")
+		}
+
+		remaining := template
+		for _, placeholder := range EKSContextPlaceholders {
+			remaining = strings.ReplaceAll(remaining, "${"+placeholder+"}", "")
+		}
+		if strings.Contains(remaining, "${") {
+			return fmt.Errorf("eks context_template contains an unknown placeholder (supported: ${%s})",
+				strings.Join(EKSContextPlaceholders, "}, ${"))
+		}
+	}
+
+	aliases := make(map[string]string)
+	for key, alias := range c.AWS.EKS.Aliases {
+		if alias == "" {
+			return fmt.Errorf("eks alias for %s cannot be empty", key)
+		}
+		if other, exists := aliases[alias]; exists {
+			return fmt.Errorf("eks alias %s is used for both %s and %s", alias, other, key)
+		}
+		aliases[alias] = key
+	}
+
+	return nil
+}
+
+// GetChainedProfile returns the chained profile with the given name
+func (c *Config) GetChainedProfile(name string) (*ChainedProfile, bool) {
+	for i := range c.AWS.ChainedProfiles {
+		if c.AWS.ChainedProfiles[i].Name == name {
+			return &c.AWS.ChainedProfiles[i], true
+		}
+	}
+	return nil, false
+}
+
 // ValidateGitHub validates GitHub-specific configuration
 func (c *Config) ValidateGitHub() error {
 	if c.GitHub.Token == "" {
@@ -168,6 +168,101 @@ func TestValidateConfig(t *testing.T) {
 	}
 }
 
+func TestValidateChainedProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []ChainedProfile
+		wantErr  bool
+	}{
+		{
+			name: "valid chained profile",
+			profiles: []ChainedProfile{
+				{
+					Name:          "prod-deploy",
+					SourceProfile: "shared-admin",
+					RoleARN:       "arn:aws:iam::123456789012:role/Deploy",
+					MFASerial:     "arn:aws:iam::123456789012:mfa/jane",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "missing source profile",
+			profiles: []ChainedProfile{{Name: "prod-deploy", RoleARN: "arn:aws:iam::123456789012:role/Deploy"}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid role ARN",
+			profiles: []ChainedProfile{{Name: "prod-deploy", SourceProfile: "shared-admin", RoleARN: "Deploy"}},
+			wantErr:  true,
+		},
+		{
+			name:     "self reference",
+			profiles: []ChainedProfile{{Name: "prod-deploy", SourceProfile: "prod-deploy", RoleARN: "arn:aws:iam::123456789012:role/Deploy"}},
+			wantErr:  true,
+		},
+		{
+			name: "duplicate names",
+			profiles: []ChainedProfile{
+				{Name: "prod-deploy", SourceProfile: "a", RoleARN: "arn:aws:iam::123456789012:role/Deploy"},
+				{Name: "prod-deploy", SourceProfile: "b", RoleARN: "arn:aws:iam::123456789012:role/Deploy"},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "duration out of range",
+			profiles: []ChainedProfile{{Name: "prod-deploy", SourceProfile: "a", RoleARN: "arn:aws:iam::123456789012:role/Deploy", DurationSeconds: 60}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{AWS: AWSConfig{ChainedProfiles: tt.profiles}}
+			err := cfg.ValidateChainedProfiles()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateChainedProfiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEKS(t *testing.T) {
+	tests := []struct {
+		name    string
+		eks     EKSConfig
+		wantErr bool
+	}{
+		{name: "empty", eks: EKSConfig{}, wantErr: false},
+		{
+			name: "valid template and aliases",
+			eks: EKSConfig{
+				ContextTemplate: "${account}-${cluster}",
+				Aliases:         map[string]string{"arn:aws:eks:us-east-1:123456789012:cluster/main": "prod"},
+			},
+			wantErr: false,
+		},
+		{name: "template without cluster", eks: EKSConfig{ContextTemplate: "${profile}-${region}"}, wantErr: true},
+		{name: "unknown placeholder", eks: EKSConfig{ContextTemplate: "${cluster}-${env}"}, wantErr: true},
+		{name: "empty alias", eks: EKSConfig{Aliases: map[string]string{"main-us-east-1": ""}}, wantErr: true},
+		{
+			name:    "duplicate alias",
+			eks:     EKSConfig{Aliases: map[string]string{"a-us-east-1": "prod", "b-us-east-1": "prod"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{AWS: AWSConfig{EKS: tt.eks}}
+			err := cfg.ValidateEKS()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEKS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestGetConfigPath(t *testing.T) {
 	path, err := GetConfigPath()
 	if err != nil {
@@ -209,20 +209,107 @@ func (f *Finder) SelectWithFilter() (string, error) {
 	}
 }
 
-// filterOptions filters options based on the input string
-func (f *Finder) filterOptions(filter string) []Option {
-	filter = strings.ToLower(filter)
-	var filtered []Option
+// SelectMany displays options and allows user to select several by number.
+// Numbers are separated by commas or spaces, ranges such as 2-5 are accepted
+// and "all" selects every option.
+func (f *Finder) SelectMany() ([]string, error) {
+	if len(f.options) == 0 {
+		return nil, fmt.Errorf("no options available")
+	}
+
+	// Display prompt
+	fmt.Println(f.prompt)
+	fmt.Println(strings.Repeat("-", len(f.prompt)))
 
+	// Calculate max value length for alignment
+	maxValueLen := 0
 	for _, option := range f.options {
-		// Check if filter matches value or description
-		if strings.Contains(strings.ToLower(option.Value), filter) ||
-			strings.Contains(strings.ToLower(option.Description), filter) {
-			filtered = append(filtered, option)
+		if len(option.Value) > maxValueLen {
+			maxValueLen = len(option.Value)
+		}
+	}
+
+	// Display options with consistent formatting
+	for i, option := range f.options {
+		fmt.Printf("%d. %-*s", i+1, maxValueLen, option.Value)
+		if option.Description != "" {
+			fmt.Printf("  │  %s", option.Description)
 		}
+		fmt.Println()
 	}
 
-	return filtered
+	// Get user selection
+	fmt.Print("\nSelect options (e.g. 1,3,5-7 or all): ")
+
+	reader := bufio.NewReader(os.Stdin)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	indexes, err := parseSelection(input, len(f.options))
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		selected = append(selected, f.options[index].Value)
+	}
+	return selected, nil
+}
+
+// parseSelection parses a list of 1-based option numbers and ranges into
+// sorted, de-duplicated 0-based indexes
+func parseSelection(input string, count int) ([]int, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, fmt.Errorf("no selection made")
+	}
+
+	chosen := make([]bool, count)
+	if strings.EqualFold(input, "all") {
+		for i := range chosen {
+			chosen[i] = true
+		}
+	} else {
+		fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' })
+		for _, field := range fields {
+			first, last, isRange := strings.Cut(field, "-")
+			start, err := strconv.Atoi(first)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selection: %s", field)
+			}
+			end := start
+			if isRange {
+				if end, err = strconv.Atoi(last); err != nil {
+					return nil, fmt.Errorf("invalid selection: %s", field)
+				}
+			}
+			if start < 1 || end > count || start > end {
+				return nil, fmt.Errorf("selection out of range: %s", field)
+			}
+			for i := start; i <= end; i++ {
+				chosen[i-1] = true
+			}
+		}
+	}
+
+	var indexes []int
+	for i, ok := range chosen {
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no selection made")
+	}
+	return indexes, nil
+}
+
+// filterOptions filters and ranks options with the fuzzy matcher
+func (f *Finder) filterOptions(filter string) []Option {
+	return matchedOptions(Filter(f.options, filter))
 }
 
 // GetOptions returns all available options
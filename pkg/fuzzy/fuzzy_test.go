@@ -1,6 +1,7 @@
 package fuzzy
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -175,3 +176,33 @@ func TestSelectWithNoOptions(t *testing.T) {
 		t.Error("SelectWithFilter should return error when no options are available")
 	}
 }
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{name: "single", input: "2", want: []int{1}},
+		{name: "list and range", input: "4, 1 2-3", want: []int{0, 1, 2, 3}},
+		{name: "duplicates", input: "1,1,1-2", want: []int{0, 1}},
+		{name: "all", input: "ALL", want: []int{0, 1, 2, 3, 4}},
+		{name: "empty", input: "  \n", wantErr: true},
+		{name: "out of range", input: "6", wantErr: true},
+		{name: "reversed range", input: "3-1", wantErr: true},
+		{name: "not a number", input: "one", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSelection(tt.input, 5)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSelection(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) && !tt.wantErr {
+				t.Errorf("parseSelection(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package fuzzy
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +10,10 @@ import (
 	fzf "github.com/junegunn/fzf/src"
 )
 
+// errFzfUnavailable is returned by run when fzf cannot be used and the simple
+// finder should be used instead
+var errFzfUnavailable = errors.New("fzf unavailable")
+
 // FzfRunner defines the interface for running fzf
 type FzfRunner interface {
 	Run(opts *fzf.Options) (int, error)
@@ -27,6 +32,7 @@ type FzfFinder struct {
 	options []Option
 	prompt  string
 	runner  FzfRunner
+	preview PreviewFunc
 }
 
 // NewFzf creates a new fzf-style fuzzy finder
@@ -63,16 +69,47 @@ func (f *FzfFinder) SetPrompt(prompt string) {
 	f.prompt = prompt
 }
 
+// SetPreview sets the function rendering the preview pane for the highlighted option
+func (f *FzfFinder) SetPreview(preview PreviewFunc) {
+	f.preview = preview
+}
+
 // Select starts the fuzzy selection process using the fzf library
 func (f *FzfFinder) Select() (string, error) {
+	selected, err := f.run(false)
+	if errors.Is(err, errFzfUnavailable) {
+		// Fallback to simple finder if fzf fails
+		return f.fallbackSelect()
+	}
+	if err != nil {
+		return "", err
+	}
+	return selected[0], nil
+}
+
+// SelectMany lets the user pick several options: Tab toggles the highlighted
+// option, Ctrl+A selects every match and Ctrl+R inverts the selection.
+// Pressing Enter without marking anything selects the highlighted option.
+func (f *FzfFinder) SelectMany() ([]string, error) {
+	selected, err := f.run(true)
+	if errors.Is(err, errFzfUnavailable) {
+		// Fallback to simple finder if fzf fails
+		return f.fallbackSelectMany()
+	}
+	return selected, err
+}
+
+// run executes fzf and returns the selected values, or errFzfUnavailable when
+// fzf could not be run
+func (f *FzfFinder) run(multi bool) ([]string, error) {
 	if len(f.options) == 0 {
-		return "", fmt.Errorf("no options available")
+		return nil, fmt.Errorf("no options available")
 	}
 
 	// Create a temporary file with the options
 	tmpFile, err := os.CreateTemp("", "fzf-options-*.txt")
 	if err != nil {
-		return "", fmt.Errorf("failed to create temporary file: %w", err)
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
 	}
 	defer func() {
 		_ = os.Remove(tmpFile.Name()) // Ignore cleanup errors
@@ -88,39 +125,35 @@ func (f *FzfFinder) Select() (string, error) {
 			displayText = fmt.Sprintf("%s  │  %s", option.Value, option.Description)
 		}
 		if _, err := fmt.Fprintln(tmpFile, displayText); err != nil {
-			return "", fmt.Errorf("failed to write option to file: %w", err)
+			return nil, fmt.Errorf("failed to write option to file: %w", err)
 		}
 	}
 
 	// Close the file so fzf can read it
 	if err := tmpFile.Close(); err != nil {
-		return "", fmt.Errorf("failed to close temporary file: %w", err)
+		return nil, fmt.Errorf("failed to close temporary file: %w", err)
 	}
 
-	// Prepare fzf arguments
-	args := []string{
-		"--prompt=" + f.prompt + " ",
-		"--height=10",
-		"--layout=default",
-		"--no-multi",
-		"--cycle",
-		"--hscroll",
-		"--hscroll-off=10",
-		"--tabstop=8",
-		"--clear",
-		"--extended",
-		"--algo=v2",
-		"--tiebreak=length",
-		"--sort=1000",
-		"--no-mouse",
-		"--no-reverse",
-		"--border=none",
+	// Pre-render previews so fzf only has to read a file per highlighted line
+	previewDir := ""
+	if f.preview != nil {
+		previewDir, err = os.MkdirTemp("", "fzf-preview-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create preview directory: %w", err)
+		}
+		defer func() {
+			_ = os.RemoveAll(previewDir) // Ignore cleanup errors
+		}()
+
+		if err := writePreviewFiles(previewDir, f.options, f.preview); err != nil {
+			return nil, err
+		}
 	}
 
 	// Parse options and run fzf
-	opts, err := fzf.ParseOptions(true, args)
+	opts, err := fzf.ParseOptions(true, f.fzfArgs(previewDir, multi))
 	if err != nil {
-		return "", fmt.Errorf("failed to parse fzf options: %w", err)
+		return nil, fmt.Errorf("failed to parse fzf options: %w", err)
 	}
 
 	// Redirect stdin to read from our temporary file
@@ -129,7 +162,7 @@ func (f *FzfFinder) Select() (string, error) {
 
 	tmpFileForReading, err := os.Open(tmpFile.Name())
 	if err != nil {
-		return "", fmt.Errorf("failed to open temporary file for reading: %w", err)
+		return nil, fmt.Errorf("failed to open temporary file for reading: %w", err)
 	}
 	defer func() {
 		_ = tmpFileForReading.Close() // Ignore close errors
@@ -143,7 +176,7 @@ func (f *FzfFinder) Select() (string, error) {
 
 	r, w, err := os.Pipe()
 	if err != nil {
-		return "", fmt.Errorf("failed to create pipe: %w", err)
+		return nil, fmt.Errorf("failed to create pipe: %w", err)
 	}
 	defer func() {
 		_ = r.Close() // Ignore close errors
@@ -154,54 +187,124 @@ func (f *FzfFinder) Select() (string, error) {
 
 	os.Stdout = w
 
+	// Read the result while fzf runs, as a large selection does not fit in
+	// the pipe buffer and fzf would block writing it
+	type readResult struct {
+		data []byte
+		err  error
+	}
+	output := make(chan readResult, 1)
+	go func() {
+		data, err := io.ReadAll(r)
+		output <- readResult{data, err}
+	}()
+
 	// Run fzf
 	exitCode, err := f.runner.Run(opts)
 
-	// Restore stdout before reading result
+	// Restore stdout and wait for the rest of the result
 	_ = w.Close() // Ignore close errors
 	os.Stdout = originalStdout
+	read := <-output
 
 	if err != nil {
-		// Fallback to simple finder if fzf fails
-		return f.fallbackSelect()
+		return nil, errFzfUnavailable
 	}
 
 	if exitCode != fzf.ExitOk {
-		return "", fmt.Errorf("fzf selection cancelled or failed")
+		return nil, fmt.Errorf("fzf selection cancelled or failed")
 	}
 
-	// Read the result
-	result, err := io.ReadAll(r)
-	if err != nil {
-		return "", fmt.Errorf("failed to read fzf result: %w", err)
+	if read.err != nil {
+		return nil, fmt.Errorf("failed to read fzf result: %w", read.err)
 	}
+	result := read.data
 
-	selectedText := strings.TrimSpace(string(result))
-	if selectedText == "" {
-		return "", fmt.Errorf("no selection made")
+	var selected []string
+	for _, line := range strings.Split(string(result), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		selected = append(selected, f.optionValue(line))
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no selection made")
 	}
 
-	// Extract the original value from the selected text
+	return selected, nil
+}
+
+// optionValue maps a line printed by fzf back to the value of its option
+func (f *FzfFinder) optionValue(line string) string {
 	// The format is "value  │  description" so we need to extract just the value
-	parts := strings.Split(selectedText, "  │  ")
+	parts := strings.Split(strings.TrimSpace(line), "  │  ")
 	selectedValue := strings.TrimSpace(parts[0])
 
 	// Find the matching option to return the original value
 	for _, option := range f.options {
 		if option.Value == selectedValue {
-			return option.Value, nil
+			return option.Value
 		}
 	}
 
 	// Fallback: return the selected text as-is
-	return selectedValue, nil
+	return selectedValue
+}
+
+// fzfArgs returns the fzf command line, with a preview window when previews
+// have been rendered into previewDir
+func (f *FzfFinder) fzfArgs(previewDir string, multi bool) []string {
+	height := "--height=10"
+	if previewDir != "" {
+		height = "--height=20"
+	}
+
+	multiArg := "--no-multi"
+	if multi {
+		multiArg = "--multi"
+	}
+
+	args := []string{
+		"--prompt=" + f.prompt + " ",
+		height,
+		"--layout=default",
+		multiArg,
+		"--cycle",
+		"--hscroll",
+		"--hscroll-off=10",
+		"--tabstop=8",
+		"--clear",
+		"--extended",
+		"--algo=v2",
+		"--tiebreak=length",
+		"--sort=1000",
+		"--no-mouse",
+		"--no-reverse",
+		"--border=none",
+	}
+
+	if multi {
+		args = append(args, "--bind=ctrl-a:select-all,ctrl-r:toggle-all")
+	}
+
+	if previewDir != "" {
+		args = append(args,
+			"--preview="+previewCommand(previewDir),
+			"--preview-window=right:50%:wrap",
+		)
+	}
+
+	return args
 }
 
 // FzfFinderInterface defines the interface for fzf-based fuzzy finding
 type FzfFinderInterface interface {
 	SetOptions(options []Option) error
 	SetPrompt(prompt string)
+	SetPreview(preview PreviewFunc)
 	Select() (string, error)
+	SelectMany() ([]string, error)
 }
 
 // fallbackSelect provides a simple selection for when fzf fails
@@ -214,5 +317,14 @@ func (f *FzfFinder) fallbackSelect() (string, error) {
 	return finder.SelectWithFilter()
 }
 
+// fallbackSelectMany provides a simple multi-selection for when fzf fails
+func (f *FzfFinder) fallbackSelectMany() ([]string, error) {
+	finder := New(f.prompt)
+	for _, option := range f.options {
+		finder.AddOption(option.Value, option.Description)
+	}
+	return finder.SelectMany()
+}
+
 // Ensure FzfFinder implements the interface
 var _ FzfFinderInterface = (*FzfFinder)(nil)
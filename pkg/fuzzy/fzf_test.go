@@ -2,8 +2,10 @@ package fuzzy
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	fzf "github.com/junegunn/fzf/src"
 )
@@ -294,3 +296,84 @@ func TestFzfSelectCancelled(t *testing.T) {
 		t.Errorf("Expected error containing '%s', got '%s'", expectedError, err.Error())
 	}
 }
+
+func TestFzfSelectMany(t *testing.T) {
+	mockRunner := &MockFzfRunner{
+		OutputToWrite: "option1  │  First option\noption3\n",
+	}
+
+	finder := NewFzfWithRunner("Test", mockRunner)
+	options := []Option{
+		{Value: "option1", Description: "First option"},
+		{Value: "option2", Description: "Second option"},
+		{Value: "option3"},
+	}
+	if err := finder.SetOptions(options); err != nil {
+		t.Fatalf("SetOptions failed: %v", err)
+	}
+
+	selected, err := finder.SelectMany()
+	if err != nil {
+		t.Fatalf("SelectMany failed: %v", err)
+	}
+
+	if strings.Join(selected, ",") != "option1,option3" {
+		t.Errorf("Expected [option1 option3], got %v", selected)
+	}
+
+	if mockRunner.LastOpts == nil || mockRunner.LastOpts.Multi == 0 {
+		t.Error("Expected fzf to run in multi-select mode")
+	}
+
+	if _, err := finder.Select(); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if mockRunner.LastOpts.Multi != 0 {
+		t.Error("Expected fzf to run in single-select mode")
+	}
+}
+
+func TestFzfSelectManyLargeSelection(t *testing.T) {
+	// More output than a pipe buffer holds, which fzf must be able to write
+	// before it exits
+	var options []Option
+	var output strings.Builder
+	for i := 0; i < 5000; i++ {
+		option := Option{Value: fmt.Sprintf("option-%04d", i), Description: strings.Repeat("x", 40)}
+		options = append(options, option)
+		output.WriteString(option.Value + "  │  " + option.Description + "\n")
+	}
+	if output.Len() <= 64*1024 {
+		t.Fatalf("Expected more than 64 KiB of output, got %d bytes", output.Len())
+	}
+
+	finder := NewFzfWithRunner("Test", &MockFzfRunner{OutputToWrite: output.String()})
+	if err := finder.SetOptions(options); err != nil {
+		t.Fatalf("SetOptions failed: %v", err)
+	}
+
+	type result struct {
+		selected []string
+		err      error
+	}
+	done := make(chan result, 1)
+	stdout := os.Stdout
+	go func() {
+		selected, err := finder.SelectMany()
+		done <- result{selected, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			t.Fatalf("SelectMany failed: %v", res.err)
+		}
+		if len(res.selected) != len(options) {
+			t.Errorf("Expected %d selected options, got %d", len(options), len(res.selected))
+		}
+	case <-time.After(10 * time.Second):
+		// Stdout is still redirected to the blocked pipe
+		os.Stdout = stdout
+		t.Fatal("SelectMany blocked writing a large selection")
+	}
+}
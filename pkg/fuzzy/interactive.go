@@ -21,8 +21,14 @@ type InteractiveFinder interface {
 	// Select starts the interactive selection process
 	Select() (string, error)
 
+	// SelectMany starts the interactive selection of several options
+	SelectMany() ([]string, error)
+
 	// SetKeyBindings allows customization of key bindings
 	SetKeyBindings(bindings KeyBindings)
+
+	// SetPreview sets the function rendering details of the highlighted option
+	SetPreview(preview PreviewFunc)
 }
 
 // KeyBindings defines keyboard shortcuts for the interactive finder
@@ -31,6 +37,11 @@ type KeyBindings struct {
 	Down   []string // Default: ["↓", "j"]
 	Select []string // Default: ["Enter"]
 	Cancel []string // Default: ["Escape", "Ctrl+C"]
+
+	// Multi-select only
+	Toggle    []string // Default: ["Tab"]
+	SelectAll []string // Default: ["Ctrl+A"]
+	Invert    []string // Default: ["Ctrl+R"]
 }
 
 // DefaultKeyBindings returns the default key bindings
@@ -40,6 +51,10 @@ func DefaultKeyBindings() KeyBindings {
 		Down:   []string{"\x1b[B", "j"},  // Down arrow, vim j
 		Select: []string{"\r", "\n"},     // Enter
 		Cancel: []string{"\x1b", "\x03"}, // Escape, Ctrl+C
+
+		Toggle:    []string{"\t"},   // Tab
+		SelectAll: []string{"\x01"}, // Ctrl+A
+		Invert:    []string{"\x12"}, // Ctrl+R
 	}
 }
 
@@ -47,6 +62,7 @@ func DefaultKeyBindings() KeyBindings {
 type InteractiveFinderImpl struct {
 	options         []Option
 	filteredOptions []Option
+	filteredMatches []Match
 	selectedIndex   int
 	filterText      string
 	displayOffset   int
@@ -55,6 +71,9 @@ type InteractiveFinderImpl struct {
 	maxDisplayRows  int
 	terminalWidth   int
 	terminalHeight  int
+	preview         PreviewFunc
+	multi           bool
+	marked          map[string]bool
 }
 
 // NewInteractive creates a new interactive fuzzy finder
@@ -84,6 +103,10 @@ func NewInteractiveWithConsistentBindings(prompt string) InteractiveFinder {
 		Down:   []string{"\x1b[B", "j"},  // Down arrow, vim j
 		Select: []string{"\r", "\n"},     // Enter
 		Cancel: []string{"\x1b", "\x03"}, // Escape, Ctrl+C
+
+		Toggle:    []string{"\t"},   // Tab
+		SelectAll: []string{"\x01"}, // Ctrl+A
+		Invert:    []string{"\x12"}, // Ctrl+R
 	}
 
 	finder.SetKeyBindings(consistentBindings)
@@ -100,6 +123,7 @@ func (f *InteractiveFinderImpl) SetOptions(options []Option) error {
 	copy(f.options, options)
 	f.filteredOptions = make([]Option, len(options))
 	copy(f.filteredOptions, options)
+	f.filteredMatches = nil
 	f.selectedIndex = 0
 	f.displayOffset = 0
 	f.filterText = ""
@@ -117,21 +141,73 @@ func (f *InteractiveFinderImpl) SetKeyBindings(bindings KeyBindings) {
 	f.keyBindings = bindings
 }
 
+// SetPreview sets the function rendering details of the highlighted option
+func (f *InteractiveFinderImpl) SetPreview(preview PreviewFunc) {
+	f.preview = preview
+}
+
 // Select starts the interactive selection process
 func (f *InteractiveFinderImpl) Select() (string, error) {
 	if len(f.options) == 0 {
 		return "", fmt.Errorf("no options available")
 	}
 
+	f.multi = false
+
 	// Check if terminal supports interactive mode
 	if !f.isTerminalSupported() {
 		return f.fallbackSelect()
 	}
 
+	ok, err := f.interact()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return f.fallbackSelect()
+	}
+
+	return f.filteredOptions[f.selectedIndex].Value, nil
+}
+
+// SelectMany starts the interactive selection of several options. Tab
+// toggles the highlighted option, Ctrl+A marks every match and Ctrl+R inverts
+// the marks of the matches. Pressing Enter without marking anything selects
+// the highlighted option.
+func (f *InteractiveFinderImpl) SelectMany() ([]string, error) {
+	if len(f.options) == 0 {
+		return nil, fmt.Errorf("no options available")
+	}
+
+	f.multi = true
+	f.marked = make(map[string]bool)
+
+	// Check if terminal supports interactive mode
+	if !f.isTerminalSupported() {
+		return f.fallbackSelectMany()
+	}
+
+	ok, err := f.interact()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return f.fallbackSelectMany()
+	}
+
+	if selected := f.markedValues(); len(selected) > 0 {
+		return selected, nil
+	}
+	return []string{f.filteredOptions[f.selectedIndex].Value}, nil
+}
+
+// interact runs the input loop until an option is chosen. It returns false
+// when the terminal cannot be put into raw mode.
+func (f *InteractiveFinderImpl) interact() (bool, error) {
 	// Save terminal state
 	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
 	if err != nil {
-		return f.fallbackSelect()
+		return false, nil
 	}
 	defer func() {
 		if err := term.Restore(int(os.Stdin.Fd()), oldState); err != nil {
@@ -152,7 +228,7 @@ func (f *InteractiveFinderImpl) Select() (string, error) {
 	for {
 		n, err := os.Stdin.Read(buffer)
 		if err != nil {
-			return "", fmt.Errorf("failed to read input: %w", err)
+			return true, fmt.Errorf("failed to read input: %w", err)
 		}
 
 		input := string(buffer[:n])
@@ -161,16 +237,51 @@ func (f *InteractiveFinderImpl) Select() (string, error) {
 		switch action {
 		case "select":
 			if len(f.filteredOptions) > 0 && f.selectedIndex < len(f.filteredOptions) {
-				return f.filteredOptions[f.selectedIndex].Value, nil
+				return true, nil
 			}
 		case "cancel":
-			return "", fmt.Errorf("selection cancelled")
+			return true, fmt.Errorf("selection cancelled")
 		case "update":
 			f.render()
 		}
 	}
 }
 
+// markedValues returns the marked options in their original order
+func (f *InteractiveFinderImpl) markedValues() []string {
+	var values []string
+	for _, option := range f.options {
+		if f.marked[option.Value] {
+			values = append(values, option.Value)
+		}
+	}
+	return values
+}
+
+// toggleMark marks or unmarks the highlighted option and moves to the next one
+func (f *InteractiveFinderImpl) toggleMark() {
+	if f.selectedIndex >= len(f.filteredOptions) {
+		return
+	}
+	value := f.filteredOptions[f.selectedIndex].Value
+	f.marked[value] = !f.marked[value]
+	f.moveDown()
+}
+
+// markAll marks every option matching the current filter
+func (f *InteractiveFinderImpl) markAll() {
+	for _, option := range f.filteredOptions {
+		f.marked[option.Value] = true
+	}
+}
+
+// invertMarks flips the marks of the options matching the current filter
+func (f *InteractiveFinderImpl) invertMarks() {
+	for _, option := range f.filteredOptions {
+		f.marked[option.Value] = !f.marked[option.Value]
+	}
+}
+
 // isTerminalSupported checks if the terminal supports interactive features
 func (f *InteractiveFinderImpl) isTerminalSupported() bool {
 	// Check if stdin is a terminal
@@ -202,6 +313,15 @@ func (f *InteractiveFinderImpl) fallbackSelect() (string, error) {
 	return finder.SelectWithFilter()
 }
 
+// fallbackSelectMany provides a simple multi-selection for unsupported terminals
+func (f *InteractiveFinderImpl) fallbackSelectMany() ([]string, error) {
+	finder := New(f.prompt)
+	for _, option := range f.options {
+		finder.AddOption(option.Value, option.Description)
+	}
+	return finder.SelectMany()
+}
+
 // handleInput processes keyboard input and returns the action to take
 func (f *InteractiveFinderImpl) handleInput(input string) string {
 	// Check for special keys first
@@ -231,6 +351,29 @@ func (f *InteractiveFinderImpl) handleInput(input string) string {
 		}
 	}
 
+	if f.multi {
+		for _, key := range f.keyBindings.Toggle {
+			if input == key {
+				f.toggleMark()
+				return "update"
+			}
+		}
+
+		for _, key := range f.keyBindings.SelectAll {
+			if input == key {
+				f.markAll()
+				return "update"
+			}
+		}
+
+		for _, key := range f.keyBindings.Invert {
+			if input == key {
+				f.invertMarks()
+				return "update"
+			}
+		}
+	}
+
 	// Handle backspace
 	if input == "\x7f" || input == "\b" {
 		if len(f.filterText) > 0 {
@@ -281,8 +424,10 @@ func (f *InteractiveFinderImpl) updateFilter() {
 	if f.filterText == "" {
 		f.filteredOptions = make([]Option, len(f.options))
 		copy(f.filteredOptions, f.options)
+		f.filteredMatches = nil
 	} else {
-		f.filteredOptions = f.filterOptions(f.filterText)
+		f.filteredMatches = Filter(f.options, f.filterText)
+		f.filteredOptions = matchedOptions(f.filteredMatches)
 	}
 
 	// Reset selection to first item
@@ -290,20 +435,9 @@ func (f *InteractiveFinderImpl) updateFilter() {
 	f.displayOffset = 0
 }
 
-// filterOptions filters options based on the filter text
+// filterOptions filters and ranks options with the fuzzy matcher
 func (f *InteractiveFinderImpl) filterOptions(filter string) []Option {
-	filter = strings.ToLower(filter)
-	var filtered []Option
-
-	for _, option := range f.options {
-		// Check if filter matches value or description
-		if strings.Contains(strings.ToLower(option.Value), filter) ||
-			strings.Contains(strings.ToLower(option.Description), filter) {
-			filtered = append(filtered, option)
-		}
-	}
-
-	return filtered
+	return matchedOptions(Filter(f.options, filter))
 }
 
 // render displays the current state of the finder
@@ -365,13 +499,27 @@ func (f *InteractiveFinderImpl) render() {
 		if i == f.selectedIndex {
 			prefix = "> "
 		}
+		if f.multi {
+			if f.marked[option.Value] {
+				prefix += "[x] "
+			} else {
+				prefix += "[ ] "
+			}
+		}
+
+		// Highlight the characters matched by the filter
+		value, description := option.Value, option.Description
+		if i < len(f.filteredMatches) {
+			value = highlight(value, f.filteredMatches[i].ValuePositions)
+			description = highlight(description, f.filteredMatches[i].DescriptionPositions)
+		}
 
 		// Display option value with consistent formatting and alignment
-		fmt.Printf("%s%-*s", prefix, maxValueLen, option.Value)
+		fmt.Printf("%s%s%s", prefix, value, strings.Repeat(" ", maxValueLen-len(option.Value)))
 
 		// Display description if available with proper spacing
 		if option.Description != "" {
-			fmt.Printf("  │  %s", option.Description)
+			fmt.Printf("  │  %s", description)
 		}
 
 		// Display additional metadata if available
@@ -390,10 +538,28 @@ func (f *InteractiveFinderImpl) render() {
 		fmt.Printf("\n[%d/%d] Use ↑↓ or j/k to navigate", f.selectedIndex+1, len(f.filteredOptions))
 	}
 
+	// Display details of the highlighted option
+	if preview := f.previewText(); preview != "" {
+		fmt.Println()
+		fmt.Println(strings.Repeat("-", f.getTerminalWidth()))
+		fmt.Println(preview)
+	}
+
 	// Display consistent help text with keyboard shortcuts
+	if f.multi {
+		fmt.Printf("\n%d selected. Tab to toggle, Ctrl+A to select all, Ctrl+R to invert\n", len(f.markedValues()))
+	}
 	fmt.Println("\nPress Enter to select, Escape to cancel, ↑↓ or j/k to navigate")
 }
 
+// previewText renders the preview of the highlighted option, if a preview is set
+func (f *InteractiveFinderImpl) previewText() string {
+	if f.preview == nil || f.selectedIndex >= len(f.filteredOptions) {
+		return ""
+	}
+	return f.preview(f.filteredOptions[f.selectedIndex])
+}
+
 // updateTerminalSize gets the current terminal dimensions
 func (f *InteractiveFinderImpl) updateTerminalSize() {
 	width, height, err := f.getTerminalSize()
@@ -2,6 +2,7 @@ package fuzzy
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -434,6 +435,58 @@ func TestHandleInput(t *testing.T) {
 	}
 }
 
+func TestHandleInputMultiSelect(t *testing.T) {
+	finder := NewInteractive("Test")
+	impl := finder.(*InteractiveFinderImpl)
+
+	options := []Option{
+		{Value: "alpha"},
+		{Value: "beta"},
+		{Value: "gamma"},
+	}
+	if err := finder.SetOptions(options); err != nil {
+		t.Fatalf("SetOptions failed: %v", err)
+	}
+
+	// Multi-select keys are ignored in single-select mode
+	if action := impl.handleInput("\t"); action != "" {
+		t.Errorf("Expected no action for Tab in single-select mode, got '%s'", action)
+	}
+
+	impl.multi = true
+	impl.marked = make(map[string]bool)
+
+	// Tab marks the highlighted option and moves down
+	if action := impl.handleInput("\t"); action != "update" {
+		t.Errorf("Expected 'update' action for Tab, got '%s'", action)
+	}
+	if impl.selectedIndex != 1 {
+		t.Errorf("Expected Tab to move to index 1, got %d", impl.selectedIndex)
+	}
+	if got := strings.Join(impl.markedValues(), ","); got != "alpha" {
+		t.Errorf("Expected [alpha] marked, got %s", got)
+	}
+
+	// Ctrl+R inverts the marks
+	impl.handleInput("\x12")
+	if got := strings.Join(impl.markedValues(), ","); got != "beta,gamma" {
+		t.Errorf("Expected [beta gamma] marked after invert, got %s", got)
+	}
+
+	// Select all and invert only apply to the current matches
+	impl.filterText = "alp"
+	impl.updateFilter()
+	impl.handleInput("\x01")
+	if got := strings.Join(impl.markedValues(), ","); got != "alpha,beta,gamma" {
+		t.Errorf("Expected every option marked after select all, got %s", got)
+	}
+
+	impl.handleInput("\x12")
+	if got := strings.Join(impl.markedValues(), ","); got != "beta,gamma" {
+		t.Errorf("Expected only the match to be inverted, got %s", got)
+	}
+}
+
 func TestInteractiveSelectWithNoOptions(t *testing.T) {
 	finder := NewInteractive("Test")
 
@@ -500,7 +553,7 @@ func TestRealTimeFilteringUpdates(t *testing.T) {
 	}{
 		{"", 4, "production-web-server", "no filter should show all options"},
 		{"p", 3, "production-web-server", "single char filter should match production items and development-api"},
-		{"pr", 2, "production-web-server", "two char filter should still match production items"},
+		{"pr", 3, "production-web-server", "two char filter should match production items and the API server description"},
 		{"prod", 2, "production-web-server", "word filter should match production items"},
 		{"production", 2, "production-web-server", "full word filter should match production items"},
 		{"production-w", 1, "production-web-server", "specific filter should match one item"},
@@ -0,0 +1,461 @@
+package fuzzy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Scoring constants, modelled on fzf: every matched character scores
+// scoreMatch, gaps between matched characters are penalised and characters at
+// word boundaries, camel case humps and consecutive runs earn bonuses.
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+
+	bonusBoundary            = scoreMatch / 2
+	bonusNonWord             = scoreMatch / 2
+	bonusCamel123            = bonusBoundary + scoreGapExtension
+	bonusConsecutive         = -(scoreGapStart + scoreGapExtension)
+	bonusFirstCharMultiplier = 2
+)
+
+// Match is an option that satisfies a query
+type Match struct {
+	Option Option
+	Score  int
+
+	// ValuePositions and DescriptionPositions hold the indexes of the matched
+	// runes, for highlighting
+	ValuePositions       []int
+	DescriptionPositions []int
+}
+
+// termKind is how a query term is matched
+type termKind int
+
+const (
+	termFuzzy termKind = iota
+	termExact
+	termPrefix
+	termSuffix
+	termEqual
+)
+
+// queryTerm is a single space-separated term of a query
+type queryTerm struct {
+	text    []rune
+	kind    termKind
+	inverse bool
+}
+
+// parseQuery splits a query into terms using fzf's extended search syntax:
+// 'exact, ^prefix, suffix$, ^equal$ and !negation (an inverse exact match)
+func parseQuery(query string) []queryTerm {
+	var terms []queryTerm
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		term := queryTerm{kind: termFuzzy}
+
+		if strings.HasPrefix(field, "!") {
+			term.inverse = true
+			term.kind = termExact
+			field = field[1:]
+		}
+
+		switch {
+		case strings.HasPrefix(field, "'"):
+			term.kind = termExact
+			field = field[1:]
+		case strings.HasPrefix(field, "^") && strings.HasSuffix(field, "$") && len(field) > 1:
+			term.kind = termEqual
+			field = field[1 : len(field)-1]
+		case strings.HasPrefix(field, "^"):
+			term.kind = termPrefix
+			field = field[1:]
+		case strings.HasSuffix(field, "$"):
+			term.kind = termSuffix
+			field = field[:len(field)-1]
+		}
+
+		if field == "" {
+			continue
+		}
+		term.text = []rune(field)
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// Filter returns the options matching query, best matches first. Every term of
+// the query must match the value, the description or a metadata value.
+// An empty query matches every option in its original order.
+func Filter(options []Option, query string) []Match {
+	terms := parseQuery(query)
+
+	var matches []Match
+	for _, option := range options {
+		if match, ok := matchOption(option, terms); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	// Ties keep their original order, so callers can rank favourites first
+	if len(terms) > 0 {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].Score > matches[j].Score
+		})
+	}
+
+	return matches
+}
+
+// ErrNoMatch is returned by Resolve when no option matches the query
+var ErrNoMatch = errors.New("no match")
+
+// AmbiguousError is returned by Resolve when several options match the query
+type AmbiguousError struct {
+	Query      string
+	Candidates []string
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("query %q is ambiguous, %d options match: %s", e.Query, len(e.Candidates), strings.Join(e.Candidates, ", "))
+}
+
+// Resolve picks a single option for query without user interaction. An option
+// whose value equals the query wins; otherwise exactly one option must match,
+// unless first is set, in which case the best match is taken.
+func Resolve(options []Option, query string, first bool) (string, error) {
+	matches := Filter(options, query)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w for query %q", ErrNoMatch, query)
+	}
+
+	for _, match := range matches {
+		if strings.EqualFold(match.Option.Value, strings.TrimSpace(query)) {
+			return match.Option.Value, nil
+		}
+	}
+
+	if len(matches) == 1 || first {
+		return matches[0].Option.Value, nil
+	}
+
+	return "", &AmbiguousError{Query: query, Candidates: matchedValues(matches)}
+}
+
+// matchedValues returns the option values of matches
+func matchedValues(matches []Match) []string {
+	values := make([]string, 0, len(matches))
+	for _, match := range matches {
+		values = append(values, match.Option.Value)
+	}
+	return values
+}
+
+// matchedOptions returns the options of matches
+func matchedOptions(matches []Match) []Option {
+	options := make([]Option, 0, len(matches))
+	for _, match := range matches {
+		options = append(options, match.Option)
+	}
+	return options
+}
+
+// matchOption matches every term against the searchable fields of option
+func matchOption(option Option, terms []queryTerm) (Match, bool) {
+	match := Match{Option: option}
+
+	fields := []string{option.Value, option.Description}
+	keys := make([]string, 0, len(option.Metadata))
+	for key := range option.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, option.Metadata[key])
+	}
+
+	for _, term := range terms {
+		bestScore, bestField := 0, -1
+		var bestPositions []int
+		for i, field := range fields {
+			if field == "" {
+				continue
+			}
+			score, positions, ok := matchTerm([]rune(field), term)
+			if ok && (bestField < 0 || score > bestScore) {
+				bestScore, bestField, bestPositions = score, i, positions
+			}
+		}
+
+		if term.inverse {
+			if bestField >= 0 {
+				return Match{}, false
+			}
+			continue
+		}
+		if bestField < 0 {
+			return Match{}, false
+		}
+
+		match.Score += bestScore
+		switch bestField {
+		case 0:
+			match.ValuePositions = mergePositions(match.ValuePositions, bestPositions)
+		case 1:
+			match.DescriptionPositions = mergePositions(match.DescriptionPositions, bestPositions)
+		}
+	}
+
+	return match, true
+}
+
+// matchTerm matches a single term against text, ignoring case
+func matchTerm(text []rune, term queryTerm) (int, []int, bool) {
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	pattern := term.text
+	switch term.kind {
+	case termFuzzy:
+		start, end, ok := fuzzyWindow(lower, pattern)
+		if !ok {
+			return 0, nil, false
+		}
+		score, positions := calculateScore(text, lower, pattern, start, end)
+		return score, positions, true
+	case termExact:
+		start := runeIndex(lower, pattern)
+		if start < 0 {
+			return 0, nil, false
+		}
+		score, positions := calculateScore(text, lower, pattern, start, start+len(pattern)-1)
+		return score, positions, true
+	case termPrefix:
+		if !hasRunePrefix(lower, pattern) {
+			return 0, nil, false
+		}
+		score, positions := calculateScore(text, lower, pattern, 0, len(pattern)-1)
+		return score, positions, true
+	case termSuffix, termEqual:
+		trimmed := len(lower)
+		for trimmed > 0 && unicode.IsSpace(lower[trimmed-1]) {
+			trimmed--
+		}
+		start := trimmed - len(pattern)
+		if start < 0 || (term.kind == termEqual && start != 0) || !hasRunePrefix(lower[start:trimmed], pattern) {
+			return 0, nil, false
+		}
+		score, positions := calculateScore(text, lower, pattern, start, trimmed-1)
+		return score, positions, true
+	}
+	return 0, nil, false
+}
+
+// fuzzyWindow finds the shortest window of text that ends at the earliest
+// possible position and still contains pattern as a subsequence
+func fuzzyWindow(text, pattern []rune) (int, int, bool) {
+	if len(pattern) == 0 {
+		return 0, 0, false
+	}
+
+	// Forward scan: the earliest position where the whole pattern has matched
+	pidx, end := 0, -1
+	for i, r := range text {
+		if r == pattern[pidx] {
+			pidx++
+			if pidx == len(pattern) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, 0, false
+	}
+
+	// Backward scan: the latest start from which the pattern still matches
+	pidx = len(pattern) - 1
+	start := end
+	for i := end; i >= 0; i-- {
+		if text[i] == pattern[pidx] {
+			pidx--
+			if pidx < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	return start, end, true
+}
+
+// charClass is the kind of a character, used for boundary bonuses
+type charClass int
+
+const (
+	charNonWord charClass = iota
+	charLower
+	charUpper
+	charNumber
+	charLetter
+)
+
+func classOf(r rune) charClass {
+	switch {
+	case unicode.IsLower(r):
+		return charLower
+	case unicode.IsUpper(r):
+		return charUpper
+	case unicode.IsDigit(r):
+		return charNumber
+	case unicode.IsLetter(r):
+		return charLetter
+	default:
+		return charNonWord
+	}
+}
+
+// bonusFor returns the bonus for matching a character of class current that
+// follows a character of class previous
+func bonusFor(previous, current charClass) int {
+	switch {
+	case previous == charNonWord && current != charNonWord:
+		return bonusBoundary
+	case previous == charLower && current == charUpper,
+		previous != charNumber && current == charNumber:
+		return bonusCamel123
+	case current == charNonWord:
+		return bonusNonWord
+	default:
+		return 0
+	}
+}
+
+// calculateScore scores the greedy match of pattern within text[start:end+1]
+// and returns the matched positions
+func calculateScore(text, lower, pattern []rune, start, end int) (int, []int) {
+	score, pidx, consecutive, firstBonus := 0, 0, 0, 0
+	inGap := false
+	positions := make([]int, 0, len(pattern))
+
+	previous := charNonWord
+	if start > 0 {
+		previous = classOf(text[start-1])
+	}
+
+	for i := start; i <= end && pidx < len(pattern); i++ {
+		class := classOf(text[i])
+		if lower[i] == pattern[pidx] {
+			positions = append(positions, i)
+			score += scoreMatch
+
+			bonus := bonusFor(previous, class)
+			if consecutive == 0 {
+				firstBonus = bonus
+			} else {
+				// Break consecutive chunks at boundaries
+				if bonus >= bonusBoundary && bonus > firstBonus {
+					firstBonus = bonus
+				}
+				bonus = max(bonus, firstBonus, bonusConsecutive)
+			}
+
+			if pidx == 0 {
+				score += bonus * bonusFirstCharMultiplier
+			} else {
+				score += bonus
+			}
+
+			inGap = false
+			consecutive++
+			pidx++
+		} else {
+			if inGap {
+				score += scoreGapExtension
+			} else {
+				score += scoreGapStart
+			}
+			inGap = true
+			consecutive = 0
+			firstBonus = 0
+		}
+		previous = class
+	}
+
+	return score, positions
+}
+
+// runeIndex returns the index of the first occurrence of pattern in text, or -1
+func runeIndex(text, pattern []rune) int {
+	for i := 0; i+len(pattern) <= len(text); i++ {
+		if hasRunePrefix(text[i:], pattern) {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasRunePrefix(text, prefix []rune) bool {
+	if len(prefix) > len(text) {
+		return false
+	}
+	for i, r := range prefix {
+		if text[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// mergePositions returns the sorted union of two position lists
+func mergePositions(a, b []int) []int {
+	seen := make(map[int]bool, len(a)+len(b))
+	var merged []int
+	for _, list := range [][]int{a, b} {
+		for _, position := range list {
+			if !seen[position] {
+				seen[position] = true
+				merged = append(merged, position)
+			}
+		}
+	}
+	sort.Ints(merged)
+	return merged
+}
+
+// highlight wraps the runes of text at positions in bold, coloured ANSI codes
+func highlight(text string, positions []int) string {
+	if len(positions) == 0 {
+		return text
+	}
+
+	marked := make(map[int]bool, len(positions))
+	for _, position := range positions {
+		marked[position] = true
+	}
+
+	var b strings.Builder
+	inMatch := false
+	for i, r := range []rune(text) {
+		if marked[i] != inMatch {
+			if marked[i] {
+				b.WriteString("\x1b[1;32m")
+			} else {
+				b.WriteString("\x1b[0m")
+			}
+			inMatch = marked[i]
+		}
+		b.WriteRune(r)
+	}
+	if inMatch {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package fuzzy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	options := []Option{
+		{Value: "prod-admin", Description: "Account: 111111111111 | Role: Admin"},
+		{Value: "prod-readonly", Description: "Account: 111111111111 | Role: ReadOnly"},
+		{Value: "staging-admin", Description: "Account: 222222222222 | Role: Admin"},
+		{Value: "sandbox", Description: "Account: 333333333333 | Role: PowerUser", Metadata: map[string]string{"region": "eu-west-1"}},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "empty query keeps order", query: "", want: []string{"prod-admin", "prod-readonly", "staging-admin", "sandbox"}},
+		{name: "subsequence", query: "prdadm", want: []string{"prod-admin"}},
+		{name: "multiple terms", query: "prod admin", want: []string{"prod-admin"}},
+		{name: "consecutive at word start ranks first", query: "sa", want: []string{"sandbox", "staging-admin"}},
+		{name: "exact", query: "'ready", want: nil},
+		{name: "exact match", query: "'read", want: []string{"prod-readonly"}},
+		{name: "prefix", query: "^s", want: []string{"staging-admin", "sandbox"}},
+		{name: "suffix", query: "admin$", want: []string{"prod-admin", "staging-admin"}},
+		{name: "equal", query: "^sandbox$", want: []string{"sandbox"}},
+		{name: "negation", query: "admin !staging", want: []string{"prod-admin"}},
+		{name: "negated prefix", query: "!^prod", want: []string{"staging-admin", "sandbox"}},
+		{name: "description", query: "poweruser", want: []string{"sandbox"}},
+		{name: "metadata", query: "euwest", want: []string{"sandbox"}},
+		{name: "case insensitive", query: "PRDADM", want: []string{"prod-admin"}},
+		{name: "no match", query: "xyz", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, match := range Filter(options, tt.query) {
+				got = append(got, match.Option.Value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterRanking(t *testing.T) {
+	options := []Option{
+		{Value: "xfoobar"},
+		{Value: "foo-bar"},
+		{Value: "fxoxoxbxaxr"},
+		{Value: "FooBar"},
+	}
+
+	matches := Filter(options, "fb")
+	if len(matches) != 4 {
+		t.Fatalf("Expected 4 matches, got %d", len(matches))
+	}
+
+	// Boundaries and camel case humps beat matches in the middle of a word
+	if matches[0].Option.Value != "foo-bar" || matches[1].Option.Value != "FooBar" {
+		t.Errorf("Unexpected ranking: %v, %v", matches[0].Option.Value, matches[1].Option.Value)
+	}
+
+	// Consecutive characters beat scattered ones
+	consecutive := Filter([]Option{{Value: "zaxxbxxc"}, {Value: "xabcx"}}, "abc")
+	if len(consecutive) != 2 || consecutive[0].Option.Value != "xabcx" {
+		t.Errorf("Expected the consecutive match first, got %v", consecutive)
+	}
+}
+
+func TestFilterPositions(t *testing.T) {
+	matches := Filter([]Option{{Value: "prod-admin", Description: "Role: Admin"}}, "prd role")
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(matches))
+	}
+
+	if !reflect.DeepEqual(matches[0].ValuePositions, []int{0, 1, 3}) {
+		t.Errorf("Unexpected value positions: %v", matches[0].ValuePositions)
+	}
+	if !reflect.DeepEqual(matches[0].DescriptionPositions, []int{0, 1, 2, 3}) {
+		t.Errorf("Unexpected description positions: %v", matches[0].DescriptionPositions)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	got := highlight("prod-admin", []int{0, 1, 3})
+	want := "\x1b[1;32mpr\x1b[0mo\x1b[1;32md\x1b[0m-admin"
+	if got != want {
+		t.Errorf("highlight() = %q, want %q", got, want)
+	}
+
+	if got := highlight("prod", nil); got != "prod" {
+		t.Errorf("Expected unchanged text without positions, got %q", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	options := []Option{
+		{Value: "prod"},
+		{Value: "prod-admin"},
+		{Value: "prod-readonly"},
+		{Value: "staging-admin"},
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		first     bool
+		want      string
+		noMatch   bool
+		ambiguous []string
+	}{
+		{name: "single match", query: "stg adm", want: "staging-admin"},
+		{name: "exact value wins", query: "prod", want: "prod"},
+		{name: "exact value ignores case", query: "PROD-ADMIN", want: "prod-admin"},
+		{name: "ambiguous", query: "admin", ambiguous: []string{"prod-admin", "staging-admin"}},
+		{name: "first takes best match", query: "admin", first: true, want: "prod-admin"},
+		{name: "no match", query: "dev", noMatch: true},
+		{name: "no match with first", query: "dev", first: true, noMatch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(options, tt.query, tt.first)
+
+			var ambiguousErr *AmbiguousError
+			switch {
+			case tt.noMatch:
+				if !errors.Is(err, ErrNoMatch) {
+					t.Errorf("Expected ErrNoMatch, got %v", err)
+				}
+			case tt.ambiguous != nil:
+				if !errors.As(err, &ambiguousErr) {
+					t.Fatalf("Expected AmbiguousError, got %v", err)
+				}
+				if !reflect.DeepEqual(ambiguousErr.Candidates, tt.ambiguous) {
+					t.Errorf("Candidates = %v, want %v", ambiguousErr.Candidates, tt.ambiguous)
+				}
+			case err != nil:
+				t.Errorf("Unexpected error: %v", err)
+			case got != tt.want:
+				t.Errorf("Resolve(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
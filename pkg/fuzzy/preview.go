@@ -0,0 +1,85 @@
+package fuzzy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// PreviewFunc renders the details shown next to the highlighted option
+type PreviewFunc func(option Option) string
+
+// PreviewField is a labelled line in a preview
+type PreviewField struct {
+	Label string
+	Value string
+}
+
+// FormatPreview renders fields as aligned "Label: value" lines, skipping empty values
+func FormatPreview(fields ...PreviewField) string {
+	width := 0
+	for _, field := range fields {
+		if field.Value != "" && len(field.Label) > width {
+			width = len(field.Label)
+		}
+	}
+
+	var b strings.Builder
+	for _, field := range fields {
+		if field.Value == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "%-*s  %s\n", width+1, field.Label+":", field.Value)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// previewFileName returns the file holding the preview of the option at index
+func previewFileName(index int) string {
+	return fmt.Sprintf("%d.txt", index)
+}
+
+// writePreviewFiles renders the preview of every option into dir, one file per
+// option, so that fzf can show them with a plain file read
+func writePreviewFiles(dir string, options []Option, preview PreviewFunc) error {
+	for i, option := range options {
+		path := filepath.Join(dir, previewFileName(i))
+		if err := os.WriteFile(path, []byte(preview(option)+"\n"), 0600); err != nil {
+			return fmt.Errorf("failed to write preview: %w", err)
+		}
+	}
+	return nil
+}
+
+// previewCommand returns the fzf --preview command that shows the pre-rendered
+// preview of the highlighted line ({n} is its zero-based index)
+func previewCommand(dir string) string {
+	return previewCommandFor(runtime.GOOS, os.Getenv("SHELL"), dir)
+}
+
+// previewCommandFor returns the preview command for the shell fzf runs it with:
+// $SHELL, or cmd.exe on Windows when $SHELL is not set
+func previewCommandFor(goos, shell, dir string) string {
+	if goos != "windows" {
+		return "cat " + shellQuote(dir) + "/{n}.txt"
+	}
+
+	// Git Bash and MSYS set $SHELL to a POSIX shell; Windows paths are fine there
+	name := strings.ToLower(shell[strings.LastIndexAny(shell, `/\`)+1:])
+	switch {
+	case name == "" || strings.HasPrefix(name, "cmd"):
+		return `type "` + dir + `\{n}.txt"`
+	case strings.HasPrefix(name, "pwsh") || strings.HasPrefix(name, "powershell"):
+		// PowerShell escapes quotes in single-quoted strings by doubling them
+		return "Get-Content -LiteralPath '" + strings.ReplaceAll(dir, "'", "''") + `\{n}.txt'`
+	default:
+		return "cat " + shellQuote(dir) + "/{n}.txt"
+	}
+}
+
+// shellQuote wraps a value in single quotes so it is taken literally by the shell
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,167 @@
+package fuzzy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	fzf "github.com/junegunn/fzf/src"
+)
+
+func TestFormatPreview(t *testing.T) {
+	got := FormatPreview(
+		PreviewField{Label: "Profile", Value: "dev"},
+		PreviewField{Label: "Role ARN", Value: ""},
+		PreviewField{Label: "Account ID", Value: "123456789012"},
+	)
+
+	want := "Profile:     dev\nAccount ID:  123456789012"
+	if got != want {
+		t.Errorf("FormatPreview() = %q, want %q", got, want)
+	}
+
+	if got := FormatPreview(PreviewField{Label: "Empty"}); got != "" {
+		t.Errorf("Expected empty preview, got %q", got)
+	}
+}
+
+func TestWritePreviewFiles(t *testing.T) {
+	dir := t.TempDir()
+	options := []Option{
+		{Value: "dev", Metadata: map[string]string{"region": "us-east-1"}},
+		{Value: "prod", Metadata: map[string]string{"region": "eu-west-1"}},
+	}
+
+	preview := func(option Option) string {
+		return option.Value + " in " + option.Metadata["region"]
+	}
+	if err := writePreviewFiles(dir, options, preview); err != nil {
+		t.Fatalf("writePreviewFiles failed: %v", err)
+	}
+
+	for i, want := range []string{"dev in us-east-1\n", "prod in eu-west-1\n"} {
+		data, err := os.ReadFile(filepath.Join(dir, previewFileName(i)))
+		if err != nil {
+			t.Fatalf("Failed to read preview %d: %v", i, err)
+		}
+		if string(data) != want {
+			t.Errorf("Preview %d = %q, want %q", i, string(data), want)
+		}
+	}
+}
+
+func TestFzfArgsWithPreview(t *testing.T) {
+	finder := NewFzf("Test")
+
+	for _, arg := range finder.fzfArgs("", false) {
+		if strings.HasPrefix(arg, "--preview") {
+			t.Errorf("Unexpected preview argument without previews: %s", arg)
+		}
+	}
+
+	args := finder.fzfArgs("/tmp/fzf-preview-1", false)
+	if !containsArg(args, "--preview=cat '/tmp/fzf-preview-1'/{n}.txt") {
+		t.Errorf("Expected preview command in %v", args)
+	}
+	if !containsArg(args, "--preview-window=right:50%:wrap") {
+		t.Errorf("Expected preview window in %v", args)
+	}
+
+	if _, err := fzf.ParseOptions(true, args); err != nil {
+		t.Errorf("fzf rejected preview arguments: %v", err)
+	}
+}
+
+func TestFzfSelectWithPreview(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	var previewDir string
+	mockRunner := &MockFzfRunner{
+		OutputToWrite: "prod  │  Production\n",
+		RunFunc: func(_ *fzf.Options) (int, error) {
+			// Previews must exist while fzf is running
+			matches, _ := filepath.Glob(filepath.Join(os.TempDir(), "fzf-preview-*", previewFileName(1)))
+			if len(matches) == 1 {
+				previewDir = filepath.Dir(matches[0])
+			}
+			return fzf.ExitOk, nil
+		},
+	}
+
+	finder := NewFzfWithRunner("Test", mockRunner)
+	finder.SetPreview(func(option Option) string { return "details of " + option.Value })
+	_ = finder.SetOptions([]Option{
+		{Value: "dev", Description: "Development"},
+		{Value: "prod", Description: "Production"},
+	})
+
+	result, err := finder.Select()
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if result != "prod" {
+		t.Errorf("Expected 'prod', got %q", result)
+	}
+
+	if previewDir == "" {
+		t.Fatal("Expected preview files while fzf was running")
+	}
+	if _, err := os.Stat(previewDir); !os.IsNotExist(err) {
+		t.Errorf("Expected preview directory %s to be removed", previewDir)
+	}
+}
+
+func TestInteractivePreviewText(t *testing.T) {
+	finder := NewInteractive("Test").(*InteractiveFinderImpl)
+	_ = finder.SetOptions([]Option{{Value: "dev"}, {Value: "prod"}})
+
+	if got := finder.previewText(); got != "" {
+		t.Errorf("Expected no preview without a preview function, got %q", got)
+	}
+
+	finder.SetPreview(func(option Option) string { return "details of " + option.Value })
+	finder.moveDown()
+	if got := finder.previewText(); got != "details of prod" {
+		t.Errorf("previewText() = %q, want %q", got, "details of prod")
+	}
+
+	finder.filterText = "none"
+	finder.updateFilter()
+	if got := finder.previewText(); got != "" {
+		t.Errorf("Expected no preview without matches, got %q", got)
+	}
+}
+
+func containsArg(args []string, want string) bool {
+	for _, arg := range args {
+		if arg == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPreviewCommandFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		goos     string
+		shell    string
+		dir      string
+		expected string
+	}{
+		{name: "posix", goos: "linux", shell: "/bin/zsh", dir: "/tmp/fzf-preview-1", expected: "cat '/tmp/fzf-preview-1'/{n}.txt"},
+		{name: "windows without shell", goos: "windows", dir: `C:\Temp\fzf-preview-1`, expected: `type "C:\Temp\fzf-preview-1\{n}.txt"`},
+		{name: "windows cmd", goos: "windows", shell: `C:\Windows\System32\cmd.exe`, dir: `C:\Temp\p`, expected: `type "C:\Temp\p\{n}.txt"`},
+		{name: "windows powershell", goos: "windows", shell: "pwsh", dir: `C:\Users\o'brien\p`, expected: `Get-Content -LiteralPath 'C:\Users\o''brien\p\{n}.txt'`},
+		{name: "windows git bash", goos: "windows", shell: "/usr/bin/bash", dir: `C:\Temp\p`, expected: `cat 'C:\Temp\p'/{n}.txt`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := previewCommandFor(tt.goos, tt.shell, tt.dir); got != tt.expected {
+				t.Errorf("previewCommandFor() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
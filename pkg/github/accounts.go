@@ -0,0 +1,508 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// UserAccount is the result of looking up a GitHub user
+type UserAccount struct {
+	Login  string
+	Exists bool
+
+	// Member reports whether the user belongs to the organization the
+	// lookup was made for
+	Member bool
+}
+
+// TeamAccount is the result of looking up a team of an organization
+type TeamAccount struct {
+	Slug   string
+	Exists bool
+}
+
+// OrganizationAccount is an organization owning repositories
+type OrganizationAccount struct {
+	Login string
+
+	// BasePermission is the permission every member has on the repositories
+	// of the organization: none, read, write or admin. It is empty when the
+	// token is not allowed to see it.
+	BasePermission string
+}
+
+// AccountDirectory looks up the users and teams configuration refers to.
+// Clients implementing it let validation check them against GitHub.
+type AccountDirectory interface {
+	// LookupUsers looks up users by login and, when org is not empty, their
+	// membership of org. The result holds an entry for every login.
+	LookupUsers(org string, logins []string) (map[string]*UserAccount, error)
+
+	// LookupTeams looks up teams of org by slug. The result holds an entry
+	// for every slug.
+	LookupTeams(org string, slugs []string) (map[string]*TeamAccount, error)
+
+	// GetOrganization returns the organization named login, or nil when
+	// login is not an organization
+	GetOrganization(login string) (*OrganizationAccount, error)
+}
+
+// basePermissions orders the repository permissions from weakest to strongest
+var basePermissions = []string{"none", "read", "write", "admin"}
+
+// accountCache remembers the lookups of an AccountDirectory so that users
+// and teams shared by repositories are looked up once. It is safe for
+// concurrent use.
+type accountCache struct {
+	directory AccountDirectory
+
+	mu            sync.Mutex
+	users         map[string]*UserAccount         // by organization and login
+	teams         map[string]*TeamAccount         // by organization and slug
+	organizations map[string]*OrganizationAccount // nil for users
+}
+
+// newAccountCache creates an empty cache of the lookups of directory
+func newAccountCache(directory AccountDirectory) *accountCache {
+	return &accountCache{
+		directory:     directory,
+		users:         make(map[string]*UserAccount),
+		teams:         make(map[string]*TeamAccount),
+		organizations: make(map[string]*OrganizationAccount),
+	}
+}
+
+// accountKey returns the cache key of an account name, which GitHub
+// compares regardless of case, within an organization
+func accountKey(org, name string) string {
+	return strings.ToLower(org) + "/" + strings.ToLower(name)
+}
+
+// organization returns the organization named login, or nil when it is a user
+func (c *accountCache) organization(login string) (*OrganizationAccount, error) {
+	if login == "" {
+		return nil, nil
+	}
+
+	key := strings.ToLower(login)
+	c.mu.Lock()
+	org, ok := c.organizations[key]
+	c.mu.Unlock()
+	if ok {
+		return org, nil
+	}
+
+	org, err := c.directory.GetOrganization(login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up owner '%s': %w", login, err)
+	}
+
+	c.mu.Lock()
+	c.organizations[key] = org
+	c.mu.Unlock()
+	return org, nil
+}
+
+// loadUsers looks up the logins not cached yet in a single batch
+func (c *accountCache) loadUsers(org string, logins []string) error {
+	missing := missingAccounts(c, c.users, org, logins)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	accounts, err := c.directory.LookupUsers(org, missing)
+	if err != nil {
+		return fmt.Errorf("failed to look up users: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, login := range missing {
+		account := accounts[login]
+		if account == nil {
+			account = &UserAccount{Login: login}
+		}
+		c.users[accountKey(org, login)] = account
+	}
+	return nil
+}
+
+// loadTeams looks up the slugs not cached yet in a single batch
+func (c *accountCache) loadTeams(org string, slugs []string) error {
+	missing := missingAccounts(c, c.teams, org, slugs)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	accounts, err := c.directory.LookupTeams(org, missing)
+	if err != nil {
+		return fmt.Errorf("failed to look up teams of organization '%s': %w", org, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, slug := range missing {
+		account := accounts[slug]
+		if account == nil {
+			account = &TeamAccount{Slug: slug}
+		}
+		c.teams[accountKey(org, slug)] = account
+	}
+	return nil
+}
+
+// missingAccounts returns the names, without duplicates, not yet cached in accounts
+func missingAccounts[T any](c *accountCache, accounts map[string]T, org string, names []string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		key := accountKey(org, name)
+		if _, ok := accounts[key]; ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+// user returns the cached lookup of login
+func (c *accountCache) user(org, login string) *UserAccount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.users[accountKey(org, login)]
+}
+
+// team returns the cached lookup of slug
+func (c *accountCache) team(org, slug string) *TeamAccount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.teams[accountKey(org, slug)]
+}
+
+// prefetch looks up the users and teams of all configurations in one batch
+// each, so that checking them afterwards hits the cache
+func (c *accountCache) prefetch(configs []*RepositoryConfig, owner string) error {
+	org, err := c.organization(owner)
+	if err != nil {
+		return err
+	}
+
+	var logins, slugs []string
+	for _, config := range configs {
+		logins = append(logins, collaboratorLogins(config)...)
+		slugs = append(slugs, teamAccessSlugs(config)...)
+	}
+
+	if err := c.loadUsers(org.login(), logins); err != nil {
+		return err
+	}
+	if org != nil {
+		return c.loadTeams(org.Login, slugs)
+	}
+	return nil
+}
+
+// check checks the collaborators and teams of config against GitHub. Users
+// and teams that do not exist are errors; collaborators outside the
+// organization and team permissions its base permission already grants are
+// warnings.
+func (c *accountCache) check(config *RepositoryConfig, owner string) (ValidationErrors, []ValidationWarning, error) {
+	org, err := c.organization(owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.loadUsers(org.login(), collaboratorLogins(config)); err != nil {
+		return nil, nil, err
+	}
+	if org != nil {
+		if err := c.loadTeams(org.Login, teamAccessSlugs(config)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var validationErrors ValidationErrors
+	var warnings []ValidationWarning
+
+	for i, collab := range config.Collaborators {
+		if !checkableAccount(collab.Remove, collab.Username) {
+			continue
+		}
+		field := fmt.Sprintf("collaborators[%d].username", i)
+		account := c.user(org.login(), collab.Username)
+		switch {
+		case !account.Exists:
+			validationErrors.Add(field, collab.Username, fmt.Sprintf("user '%s' does not exist on GitHub", collab.Username))
+		case org != nil && !account.Member:
+			warnings = append(warnings, ValidationWarning{
+				Field:   field,
+				Value:   collab.Username,
+				Message: fmt.Sprintf("user '%s' is not a member of organization '%s' and will be added as an outside collaborator", collab.Username, org.Login),
+				Code:    "outside_collaborator",
+			})
+		}
+	}
+
+	for i, team := range config.Teams {
+		if !checkableAccount(team.Remove, team.TeamSlug) {
+			continue
+		}
+		if org == nil {
+			validationErrors.Add(fmt.Sprintf("teams[%d].team", i), team.TeamSlug,
+				fmt.Sprintf("team '%s' cannot be granted access: '%s' is not an organization", team.TeamSlug, owner))
+			continue
+		}
+		if !c.team(org.Login, team.TeamSlug).Exists {
+			validationErrors.Add(fmt.Sprintf("teams[%d].team", i), team.TeamSlug,
+				fmt.Sprintf("team '%s' does not exist in organization '%s'", team.TeamSlug, org.Login))
+			continue
+		}
+		if org.grants(team.Permission) {
+			warnings = append(warnings, ValidationWarning{
+				Field:   fmt.Sprintf("teams[%d].permission", i),
+				Value:   team.Permission,
+				Message: fmt.Sprintf("team '%s' is granted %s, which every member of organization '%s' already has through its base permission %s", team.TeamSlug, team.Permission, org.Login, org.BasePermission),
+				Code:    "permission_within_base",
+			})
+		}
+	}
+
+	return validationErrors, warnings, nil
+}
+
+// login returns the login of the organization, or "" for a user
+func (o *OrganizationAccount) login() string {
+	if o == nil {
+		return ""
+	}
+	return o.Login
+}
+
+// grants reports whether the base permission of the organization is at
+// least permission, making a grant of it to members redundant
+func (o *OrganizationAccount) grants(permission string) bool {
+	base := slices.Index(basePermissions, o.BasePermission)
+	requested := slices.Index(basePermissions, permission)
+	return base >= 0 && requested >= 0 && requested <= base
+}
+
+// checkableAccount reports whether an entry names an account to look up:
+// removals and names that are still templated are not
+func checkableAccount(remove bool, name string) bool {
+	return !remove && name != "" && !hasVariables(name)
+}
+
+// collaboratorLogins returns the logins of the collaborators of config to look up
+func collaboratorLogins(config *RepositoryConfig) []string {
+	var logins []string
+	for _, collab := range config.Collaborators {
+		if checkableAccount(collab.Remove, collab.Username) {
+			logins = append(logins, collab.Username)
+		}
+	}
+	return logins
+}
+
+// teamAccessSlugs returns the slugs of the teams of config to look up
+func teamAccessSlugs(config *RepositoryConfig) []string {
+	var slugs []string
+	for _, team := range config.Teams {
+		if checkableAccount(team.Remove, team.TeamSlug) {
+			slugs = append(slugs, team.TeamSlug)
+		}
+	}
+	return slugs
+}
+
+// graphqlBatchSize is the number of users or teams looked up per GraphQL query
+const graphqlBatchSize = 50
+
+// graphqlRequest is the body of a GraphQL query
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// graphqlResponse is the body of a GraphQL response
+type graphqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphql runs a GraphQL query and returns its data. Values that were not
+// found are null in the data rather than errors.
+func (c *Client) graphql(query string, variables map[string]any) (map[string]json.RawMessage, error) {
+	// The GraphQL endpoint is a sibling of the REST API root, /graphql on
+	// github.com and /api/graphql on GitHub Enterprise Server
+	endpoint := c.client.BaseURL.ResolveReference(&url.URL{Path: "../graphql"})
+
+	var response graphqlResponse
+	err := WithRetry(func() error {
+		req, err := c.client.NewRequest(http.MethodPost, endpoint.String(), &graphqlRequest{Query: query, Variables: variables})
+		if err != nil {
+			return err
+		}
+		response = graphqlResponse{}
+		if _, err := c.client.Do(c.ctx, req, &response); err != nil {
+			return WrapGitHubError(err, "graphql")
+		}
+		return nil
+	}, DefaultRetryConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, graphqlErr := range response.Errors {
+		if graphqlErr.Type != "NOT_FOUND" {
+			return nil, fmt.Errorf("GraphQL query failed: %s", graphqlErr.Message)
+		}
+	}
+	return response.Data, nil
+}
+
+// batchSelection returns one field per name, aliased with prefix and the
+// index of the name, and adds the names to variables under the same aliases
+func batchSelection(prefix string, names []string, variables map[string]any, field func(alias string) string) string {
+	var selection strings.Builder
+	for i, name := range names {
+		alias := fmt.Sprintf("%s%d", prefix, i)
+		variables[alias] = name
+		selection.WriteString(field(alias))
+		selection.WriteString("\n")
+	}
+	return selection.String()
+}
+
+// graphqlQuery returns a query of selection declaring the string variables
+func graphqlQuery(variables map[string]any, selection string) string {
+	params := make([]string, 0, len(variables))
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		params = append(params, fmt.Sprintf("$%s: String!", name))
+	}
+	return fmt.Sprintf("query(%s) {\n%s}", strings.Join(params, ", "), selection)
+}
+
+// LookupUsers looks up users by login with batched GraphQL queries. Private
+// organization memberships are only visible to tokens of members.
+func (c *Client) LookupUsers(org string, logins []string) (map[string]*UserAccount, error) {
+	accounts := make(map[string]*UserAccount, len(logins))
+
+	for batch := range slices.Chunk(logins, graphqlBatchSize) {
+		variables := make(map[string]any)
+		selection := "login"
+		if org != "" {
+			variables["org"] = org
+			selection = "login organization(login: $org) { login }"
+		}
+		query := graphqlQuery(variables, batchSelection("u", batch, variables, func(alias string) string {
+			return fmt.Sprintf("%s: user(login: $%s) { %s }", alias, alias, selection)
+		}))
+
+		data, err := c.graphql(query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, login := range batch {
+			var user *struct {
+				Login        string `json:"login"`
+				Organization *struct {
+					Login string `json:"login"`
+				} `json:"organization"`
+			}
+			if raw, ok := data[fmt.Sprintf("u%d", i)]; ok {
+				if err := json.Unmarshal(raw, &user); err != nil {
+					return nil, fmt.Errorf("failed to decode user '%s': %w", login, err)
+				}
+			}
+
+			account := &UserAccount{Login: login}
+			if user != nil {
+				account.Login = user.Login
+				account.Exists = true
+				account.Member = user.Organization != nil
+			}
+			accounts[login] = account
+		}
+	}
+
+	return accounts, nil
+}
+
+// LookupTeams looks up teams of an organization by slug with batched GraphQL queries
+func (c *Client) LookupTeams(org string, slugs []string) (map[string]*TeamAccount, error) {
+	accounts := make(map[string]*TeamAccount, len(slugs))
+
+	for batch := range slices.Chunk(slugs, graphqlBatchSize) {
+		variables := map[string]any{"org": org}
+		teamSelection := batchSelection("t", batch, variables, func(alias string) string {
+			return fmt.Sprintf("%s: team(slug: $%s) { slug }", alias, alias)
+		})
+		query := graphqlQuery(variables, "organization(login: $org) {\n"+teamSelection+"}\n")
+
+		data, err := c.graphql(query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		var teams map[string]*struct {
+			Slug string `json:"slug"`
+		}
+		if raw, ok := data["organization"]; ok {
+			if err := json.Unmarshal(raw, &teams); err != nil {
+				return nil, fmt.Errorf("failed to decode teams of organization '%s': %w", org, err)
+			}
+		}
+
+		for i, slug := range batch {
+			account := &TeamAccount{Slug: slug}
+			if team := teams[fmt.Sprintf("t%d", i)]; team != nil {
+				account.Slug = team.Slug
+				account.Exists = true
+			}
+			accounts[slug] = account
+		}
+	}
+
+	return accounts, nil
+}
+
+// GetOrganization returns the organization named login, or nil when login
+// is a user
+func (c *Client) GetOrganization(login string) (*OrganizationAccount, error) {
+	var org *OrganizationAccount
+
+	err := WithRetry(func() error {
+		result, _, err := c.client.Organizations.Get(c.ctx, login)
+		if err != nil {
+			return WrapGitHubError(err, fmt.Sprintf("organization %s", login))
+		}
+		org = &OrganizationAccount{
+			Login:          result.GetLogin(),
+			BasePermission: result.GetDefaultRepoPermission(),
+		}
+		return nil
+	}, DefaultRetryConfig())
+
+	var ghErr *Error
+	if errors.As(err, &ghErr) && ghErr.Type == ErrorTypeNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return org, nil
+}
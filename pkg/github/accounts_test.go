@@ -0,0 +1,330 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// fakeDirectory is an AccountDirectory over fixed accounts that records its lookups
+type fakeDirectory struct {
+	mu            sync.Mutex
+	members       map[string]bool // users by login, and whether they are members
+	teams         []string
+	organizations map[string]*OrganizationAccount
+
+	userLookups [][]string
+	teamLookups [][]string
+	orgLookups  []string
+}
+
+func newFakeDirectory() *fakeDirectory {
+	return &fakeDirectory{
+		members: map[string]bool{"alice": true, "bob": true, "carol": false},
+		teams:   []string{"platform", "security"},
+		organizations: map[string]*OrganizationAccount{
+			"acme": {Login: "acme", BasePermission: "read"},
+		},
+	}
+}
+
+func (d *fakeDirectory) LookupUsers(org string, logins []string) (map[string]*UserAccount, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.userLookups = append(d.userLookups, logins)
+
+	accounts := make(map[string]*UserAccount)
+	for _, login := range logins {
+		member, exists := d.members[strings.ToLower(login)]
+		accounts[login] = &UserAccount{Login: login, Exists: exists, Member: exists && member && org != ""}
+	}
+	return accounts, nil
+}
+
+func (d *fakeDirectory) LookupTeams(org string, slugs []string) (map[string]*TeamAccount, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.teamLookups = append(d.teamLookups, slugs)
+
+	accounts := make(map[string]*TeamAccount)
+	for _, slug := range slugs {
+		accounts[slug] = &TeamAccount{Slug: slug, Exists: slices.Contains(d.teams, slug)}
+	}
+	return accounts, nil
+}
+
+func (d *fakeDirectory) GetOrganization(login string) (*OrganizationAccount, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.orgLookups = append(d.orgLookups, login)
+	return d.organizations[login], nil
+}
+
+// directoryAPIClient is a mock API client able to look up accounts
+type directoryAPIClient struct {
+	*mockAPIClient
+	*fakeDirectory
+}
+
+func TestAccountCache_Check(t *testing.T) {
+	config := &RepositoryConfig{
+		Name: "api",
+		Collaborators: []Collaborator{
+			{Username: "alice", Permission: "write"},
+			{Username: "carol", Permission: "read"},
+			{Username: "ghost", Permission: "read"},
+			{Username: "${vars.maintainer}", Permission: "admin"},
+			{Username: "nobody", Remove: true},
+		},
+		Teams: []TeamAccess{
+			{TeamSlug: "platform", Permission: "write"},
+			{TeamSlug: "security", Permission: "read"},
+			{TeamSlug: "missing", Permission: "admin"},
+		},
+	}
+
+	directory := newFakeDirectory()
+	validationErrors, warnings, err := newAccountCache(directory).check(config, "acme")
+	if err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+
+	wantErrors := map[string]string{
+		"collaborators[2].username": "user 'ghost' does not exist on GitHub",
+		"teams[2].team":             "team 'missing' does not exist in organization 'acme'",
+	}
+	if len(validationErrors) != len(wantErrors) {
+		t.Errorf("check() errors = %v, want %d", validationErrors, len(wantErrors))
+	}
+	for field, message := range wantErrors {
+		if got := findValidationError(t, validationErrors, field).Message; got != message {
+			t.Errorf("%s message = %q, want %q", field, got, message)
+		}
+	}
+
+	wantWarnings := map[string]string{
+		"collaborators[1].username": "outside_collaborator",
+		"teams[1].permission":       "permission_within_base",
+	}
+	if len(warnings) != len(wantWarnings) {
+		t.Errorf("check() warnings = %v, want %d", warnings, len(wantWarnings))
+	}
+	for _, warning := range warnings {
+		if wantWarnings[warning.Field] != warning.Code {
+			t.Errorf("unexpected warning %s (%s): %s", warning.Field, warning.Code, warning.Message)
+		}
+	}
+
+	if want := [][]string{{"alice", "carol", "ghost"}}; !slices.EqualFunc(directory.userLookups, want, slices.Equal) {
+		t.Errorf("user lookups = %v, want %v", directory.userLookups, want)
+	}
+}
+
+func TestAccountCache_UserOwner(t *testing.T) {
+	config := &RepositoryConfig{
+		Name:          "dotfiles",
+		Collaborators: []Collaborator{{Username: "carol", Permission: "read"}},
+		Teams:         []TeamAccess{{TeamSlug: "platform", Permission: "read"}},
+	}
+
+	directory := newFakeDirectory()
+	validationErrors, warnings, err := newAccountCache(directory).check(config, "someone")
+	if err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+
+	// Collaborators of personal repositories are not expected to be members
+	if len(warnings) != 0 {
+		t.Errorf("check() warnings = %v, want none", warnings)
+	}
+	validationErr := findValidationError(t, validationErrors, "teams[0].team")
+	if !strings.Contains(validationErr.Message, "'someone' is not an organization") {
+		t.Errorf("message = %q", validationErr.Message)
+	}
+	if len(directory.teamLookups) != 0 {
+		t.Errorf("team lookups = %v, want none for a user", directory.teamLookups)
+	}
+}
+
+func TestAccountCache_Concurrent(t *testing.T) {
+	directory := newFakeDirectory()
+	cache := newAccountCache(directory)
+	config := &RepositoryConfig{
+		Name:          "api",
+		Collaborators: []Collaborator{{Username: "alice", Permission: "write"}, {Username: "Bob", Permission: "read"}},
+		Teams:         []TeamAccess{{TeamSlug: "platform", Permission: "write"}},
+	}
+	if err := cache.prefetch([]*RepositoryConfig{config}, "acme"); err != nil {
+		t.Fatalf("prefetch() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			validationErrors, _, err := cache.check(config, "ACME")
+			if err != nil || validationErrors.HasErrors() {
+				t.Errorf("check() = %v, %v", validationErrors, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(directory.userLookups) != 1 || len(directory.teamLookups) != 1 || len(directory.orgLookups) != 1 {
+		t.Errorf("lookups = %d users, %d teams, %d organizations, want one each",
+			len(directory.userLookups), len(directory.teamLookups), len(directory.orgLookups))
+	}
+}
+
+func TestMultiReconciler_ValidateAllAccounts(t *testing.T) {
+	config, err := LoadMultiRepositoryConfig([]byte(`defaults:
+  collaborators:
+    - username: alice
+      permission: write
+repositories:
+  - name: api
+    teams:
+      - team: platform
+        permission: write
+  - name: web
+    collaborators:
+      - username: ghost
+        permission: read
+  - name: worker
+    collaborators:
+      - username: carol
+        permission: read
+`))
+	if err != nil {
+		t.Fatalf("LoadMultiRepositoryConfig() error = %v", err)
+	}
+
+	directory := newFakeDirectory()
+	client := &directoryAPIClient{mockAPIClient: newMockAPIClient(), fakeDirectory: directory}
+	result, err := NewMultiReconciler(client, "acme").ValidateAll(config, nil)
+	if err != nil {
+		t.Fatalf("ValidateAll() error = %v", err)
+	}
+
+	if !slices.Equal(result.Valid, []string{"api", "worker"}) {
+		t.Errorf("Valid = %v, want [api worker]", result.Valid)
+	}
+	if _, ok := result.Invalid["web"]; !ok {
+		t.Fatalf("Invalid = %v, want web", result.Invalid)
+	}
+	validationErr := findValidationError(t, result.Details["web"].Errors, "collaborators[0].username")
+	if validationErr.Position == nil || validationErr.Position.String() != "12:19" {
+		t.Errorf("ghost position = %v, want 12:19", validationErr.Position)
+	}
+
+	hasOutsideWarning := false
+	for _, warning := range result.Details["worker"].Warnings {
+		hasOutsideWarning = hasOutsideWarning || warning.Code == "outside_collaborator"
+	}
+	if !hasOutsideWarning {
+		t.Errorf("worker warnings = %v, want an outside collaborator", result.Details["worker"].Warnings)
+	}
+
+	// Users shared by repositories are looked up once, in a single batch
+	if len(directory.userLookups) != 1 || len(directory.userLookups[0]) != 3 {
+		t.Errorf("user lookups = %v, want one batch of alice, ghost and carol", directory.userLookups)
+	}
+}
+
+func TestClient_AccountLookups(t *testing.T) {
+	var queries int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/graphql":
+			queries++
+			var request graphqlRequest
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("decoding query: %v", err)
+			}
+			data := map[string]any{}
+			if strings.Contains(request.Query, "team(") {
+				data["organization"] = map[string]any{"t0": map[string]any{"slug": "platform"}, "t1": nil}
+			} else {
+				data["u0"] = map[string]any{"login": "Alice", "organization": map[string]any{"login": "acme"}}
+				data["u1"] = map[string]any{"login": "carol", "organization": nil}
+				data["u2"] = nil
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data":   data,
+				"errors": []map[string]any{{"type": "NOT_FOUND", "message": "Could not resolve to a User"}},
+			})
+		case r.URL.Path == "/orgs/acme":
+			_, _ = w.Write([]byte(`{"login": "acme", "default_repository_permission": "write"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	ghClient := github.NewClient(nil)
+	ghClient.BaseURL = baseURL
+	client := &Client{client: ghClient, ctx: context.Background()}
+
+	users, err := client.LookupUsers("acme", []string{"alice", "carol", "ghost"})
+	if err != nil {
+		t.Fatalf("LookupUsers() error = %v", err)
+	}
+	for login, want := range map[string]UserAccount{
+		"alice": {Login: "Alice", Exists: true, Member: true},
+		"carol": {Login: "carol", Exists: true},
+		"ghost": {Login: "ghost"},
+	} {
+		if got := users[login]; got == nil || *got != want {
+			t.Errorf("LookupUsers()[%s] = %+v, want %+v", login, got, want)
+		}
+	}
+	if queries != 1 {
+		t.Errorf("LookupUsers() made %d queries, want 1", queries)
+	}
+
+	teams, err := client.LookupTeams("acme", []string{"platform", "missing"})
+	if err != nil {
+		t.Fatalf("LookupTeams() error = %v", err)
+	}
+	if !teams["platform"].Exists || teams["missing"].Exists {
+		t.Errorf("LookupTeams() = platform %+v, missing %+v", teams["platform"], teams["missing"])
+	}
+
+	org, err := client.GetOrganization("acme")
+	if err != nil || org == nil || org.BasePermission != "write" {
+		t.Errorf("GetOrganization(acme) = %+v, %v", org, err)
+	}
+	org, err = client.GetOrganization("someone")
+	if err != nil || org != nil {
+		t.Errorf("GetOrganization(someone) = %+v, %v, want nil for a user", org, err)
+	}
+}
+
+func TestGraphqlQuery(t *testing.T) {
+	variables := map[string]any{"org": "acme"}
+	selection := batchSelection("u", []string{"alice", "bob"}, variables, func(alias string) string {
+		return alias + ": user(login: $" + alias + ") { login }"
+	})
+
+	want := "query($org: String!, $u0: String!, $u1: String!) {\n" +
+		"u0: user(login: $u0) { login }\n" +
+		"u1: user(login: $u1) { login }\n" +
+		"}"
+	if got := graphqlQuery(variables, selection); got != want {
+		t.Errorf("graphqlQuery() = %q, want %q", got, want)
+	}
+	if variables["u1"] != "bob" {
+		t.Errorf("variables = %v, want u1 bound to bob", variables)
+	}
+}
@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
@@ -63,6 +64,16 @@ func (am *Manager) ValidateToken(ctx context.Context) (*TokenInfo, error) {
 		return nil, fmt.Errorf("not authenticated: call Authenticate() first")
 	}
 
+	// Installation tokens act as an app rather than a user, so the
+	// repositories they can access validate them instead
+	if tokenType(am.token, nil) == TokenTypeInstallation {
+		repositories, err := am.installationRepositories(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate GitHub token: %w", err)
+		}
+		return &TokenInfo{Type: TokenTypeInstallation, Repositories: repositories}, nil
+	}
+
 	// Get the authenticated user to validate the token
 	user, _, err := am.client.Users.Get(ctx, "")
 	if err != nil {
@@ -84,9 +95,14 @@ func (am *Manager) ValidateToken(ctx context.Context) (*TokenInfo, error) {
 	tokenInfo := &TokenInfo{
 		User:   user.GetLogin(),
 		Scopes: scopes,
+		Type:   tokenType(am.token, resp.Header),
 	}
 
-	// Validate required permissions
+	// Validate required permissions. Only classic tokens have scopes; the
+	// permissions of other tokens are checked by the apply preflight.
+	if tokenInfo.Type != TokenTypeClassic {
+		return tokenInfo, nil
+	}
 	if err := am.validatePermissions(tokenInfo.Scopes); err != nil {
 		return tokenInfo, err
 	}
@@ -94,6 +110,26 @@ func (am *Manager) ValidateToken(ctx context.Context) (*TokenInfo, error) {
 	return tokenInfo, nil
 }
 
+// installationRepositories returns the repositories the installation of the
+// token can access, by name, with the role GitHub reports for it on each
+func (am *Manager) installationRepositories(ctx context.Context) (map[string]string, error) {
+	repositories := make(map[string]string)
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := am.client.Apps.ListRepos(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range page.Repositories {
+			repositories[repo.GetName()] = viewerPermission(repo.GetPermissions())
+		}
+		if resp.NextPage == 0 {
+			return repositories, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
 // validatePermissions checks if the token has required permissions
 func (am *Manager) validatePermissions(scopes []string) error {
 	requiredScopes := []string{"repo"}
@@ -125,8 +161,51 @@ func (am *Manager) GetClient() *github.Client {
 
 // TokenInfo contains information about the authenticated token
 type TokenInfo struct {
-	User   string   `json:"user"`
-	Scopes []string `json:"scopes"`
+	User   string    `json:"user"`
+	Scopes []string  `json:"scopes"`
+	Type   TokenType `json:"type,omitempty"`
+
+	// Permissions are the fine-grained permissions of the token, such as
+	// administration: write. GitHub does not list them, so they are only
+	// known when given by the user.
+	Permissions map[string]string `json:"permissions,omitempty"`
+
+	// MissingPermissions are the fine-grained permissions probe requests
+	// showed the token does not have at all
+	MissingPermissions []string `json:"missing_permissions,omitempty"`
+
+	// Repositories are the repositories an installation token can access,
+	// by name, with the role GitHub reports for the installation on each
+	Repositories map[string]string `json:"repositories,omitempty"`
+}
+
+// TokenType is the kind of GitHub token, which determines how its access is granted
+type TokenType string
+
+const (
+	// TokenTypeClassic tokens, personal access tokens (classic) and OAuth
+	// app tokens, are granted OAuth scopes
+	TokenTypeClassic TokenType = "classic"
+	// TokenTypeFineGrained personal access tokens are granted permissions
+	TokenTypeFineGrained TokenType = "fine-grained"
+	// TokenTypeInstallation tokens of GitHub App installations, including
+	// the GITHUB_TOKEN of Actions workflows, are granted permissions
+	TokenTypeInstallation TokenType = "installation"
+)
+
+// tokenType returns the type of token from the headers of a response to a
+// request made with it. GitHub only reports scopes for classic tokens.
+func tokenType(token string, header http.Header) TokenType {
+	switch {
+	case len(header.Values("X-OAuth-Scopes")) > 0:
+		return TokenTypeClassic
+	case strings.HasPrefix(token, "ghs_"):
+		return TokenTypeInstallation
+	case strings.HasPrefix(token, "github_pat_"):
+		return TokenTypeFineGrained
+	}
+	// Tokens of unknown formats are treated as classic, as before
+	return TokenTypeClassic
 }
 
 // AuthenticateFromConfig is a convenience method that handles the full authentication flow
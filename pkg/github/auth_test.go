@@ -214,6 +214,33 @@ func TestManager_ValidateToken(t *testing.T) {
 	}
 }
 
+func TestManager_ValidateToken_Installation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/installation/repositories" && r.URL.Query().Get("page") == "2":
+			_, _ = w.Write([]byte(`{"total_count": 2, "repositories": [{"name": "web", "permissions": {"pull": true, "push": true}}]}`))
+		case r.URL.Path == "/installation/repositories":
+			w.Header().Set("Link", `<`+"http://"+r.Host+`/installation/repositories?page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`{"total_count": 2, "repositories": [{"name": "api", "permissions": {"admin": true, "push": true}}]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+		}
+	}))
+	defer server.Close()
+
+	am := NewManager()
+	require.NoError(t, am.Authenticate("ghs_installation_token"))
+	baseURL, _ := url.Parse(server.URL + "/")
+	am.client.BaseURL = baseURL
+
+	tokenInfo, err := am.ValidateToken(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, TokenTypeInstallation, tokenInfo.Type)
+	assert.Equal(t, map[string]string{"api": "admin", "web": "push"}, tokenInfo.Repositories)
+}
+
 func TestManager_ValidateToken_NotAuthenticated(t *testing.T) {
 	am := NewManager()
 	ctx := context.Background()
@@ -369,3 +396,26 @@ func TestTokenInfo(t *testing.T) {
 	assert.Equal(t, "testuser", tokenInfo.User)
 	assert.Equal(t, []string{"repo", "user"}, tokenInfo.Scopes)
 }
+
+func TestTokenType(t *testing.T) {
+	scopes := http.Header{}
+	scopes.Set("X-OAuth-Scopes", "")
+
+	tests := []struct {
+		name   string
+		token  string
+		header http.Header
+		want   TokenType
+	}{
+		{"classic token with scopes header", "ghp_abc", scopes, TokenTypeClassic},
+		{"fine-grained token", "github_pat_abc", http.Header{}, TokenTypeFineGrained},
+		{"installation token", "ghs_abc", http.Header{}, TokenTypeInstallation},
+		{"unknown token format", "abc", http.Header{}, TokenTypeClassic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tokenType(tt.token, tt.header))
+		})
+	}
+}
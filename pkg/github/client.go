@@ -49,6 +49,40 @@ func (c *Client) GetRepository(owner, name string) (*Repository, error) {
 	return c.convertGitHubRepository(repo), nil
 }
 
+// ListOrganizationRepositories lists all repositories of an organization
+func (c *Client) ListOrganizationRepositories(org string) ([]*Repository, error) {
+	opts := &github.RepositoryListByOrgOptions{
+		Type:        "all",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var allRepos []*Repository
+
+	err := WithRetry(func() error {
+		allRepos = nil // Reset on retry
+		opts.Page = 0  // Reset pagination on retry
+
+		for {
+			repos, resp, err := c.client.Repositories.ListByOrg(c.ctx, org, opts)
+			if err != nil {
+				return WrapGitHubError(err, fmt.Sprintf("repositories of organization %s", org))
+			}
+
+			for _, repo := range repos {
+				allRepos = append(allRepos, c.convertGitHubRepository(repo))
+			}
+
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+		return nil
+	}, DefaultRetryConfig())
+
+	return allRepos, err
+}
+
 // CreateRepository creates a new repository with the given configuration
 func (c *Client) CreateRepository(config RepositoryConfig) (*Repository, error) {
 	repo := &github.Repository{
@@ -447,9 +481,23 @@ func (c *Client) convertGitHubRepository(repo *github.Repository) *Repository {
 			Projects:    repo.GetHasProjects(),
 			Discussions: repo.GetHasDiscussions(),
 		},
-		CreatedAt: repo.GetCreatedAt().Time,
-		UpdatedAt: repo.GetUpdatedAt().Time,
+		Archived:         repo.GetArchived(),
+		Language:         repo.GetLanguage(),
+		CreatedAt:        repo.GetCreatedAt().Time,
+		UpdatedAt:        repo.GetUpdatedAt().Time,
+		ViewerPermission: viewerPermission(repo.GetPermissions()),
+	}
+}
+
+// viewerPermission returns the strongest role in the permissions GitHub
+// reports for the authenticated user on a repository
+func viewerPermission(permissions map[string]bool) string {
+	for _, role := range []string{"admin", "maintain", "push", "triage", "pull"} {
+		if permissions[role] {
+			return role
+		}
 	}
+	return ""
 }
 
 // convertGitHubBranchProtection converts GitHub API branch protection to our internal type
@@ -0,0 +1,392 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includeKey is the repository field listing the fragments merged into it
+const includeKey = "include"
+
+// LoadConfigFromPath loads configuration from a file, a directory or a glob
+// pattern. Every YAML file found in a directory (recursively) or matched by a
+// pattern is merged into a single multi-repository configuration; files and
+// directories whose names start with "_" or "." are skipped so that include
+// fragments can live alongside the configuration.
+func LoadConfigFromPath(path string) (any, ConfigFormat, error) {
+	return LoadConfigFromPathWithOptions(path, LoadOptions{})
+}
+
+// LoadConfigFromPathWithOptions loads configuration like LoadConfigFromPath,
+// decoding every file according to options
+func LoadConfigFromPathWithOptions(path string, options LoadOptions) (any, ConfigFormat, error) {
+	files, err := configFiles(path)
+	if err != nil {
+		return nil, FormatMultiRepository, err
+	}
+	if files == nil {
+		return loadConfigFromFile(path, options)
+	}
+
+	config, err := loadConfigFiles(files, options)
+	if err != nil {
+		return nil, FormatMultiRepository, err
+	}
+	return config, FormatMultiRepository, nil
+}
+
+// configFiles returns the configuration files a directory or glob pattern
+// refers to, or nil when path names a single file
+func configFiles(path string) ([]string, error) {
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config pattern %q: %w", path, err)
+		}
+
+		var files []string
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && !info.IsDir() {
+				files = append(files, match)
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no configuration files match %q", path)
+		}
+		sort.Strings(files)
+		return files, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		// Let the single file loader report missing files
+		return nil, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != path && isSkippedConfigName(d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && isYAMLFile(d.Name()) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no configuration files found in %s", path)
+	}
+	return files, nil
+}
+
+// isSkippedConfigName reports whether a directory entry is left out of
+// directory loading, which is how fragments are kept apart from configuration
+func isSkippedConfigName(name string) bool {
+	return strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".")
+}
+
+func isYAMLFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// loadConfigFiles merges several files into one multi-repository configuration.
+// Repositories and repository sets are concatenated, profiles, merge strategies and variables are
+// combined and at most one file may define the global defaults.
+func loadConfigFiles(files []string, options LoadOptions) (*MultiRepositoryConfig, error) {
+	merged := &MultiRepositoryConfig{}
+	defaultsFile := ""
+	profileFiles := make(map[string]string)
+	strategyFiles := make(map[string]string)
+	varFiles := make(map[string]string)
+
+	for _, file := range files {
+		config, err := decodeConfigFile(file, options)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		if config.Version != "" {
+			if merged.Version != "" && merged.Version != config.Version {
+				return nil, fmt.Errorf("%s: version %q conflicts with version %q", file, config.Version, merged.Version)
+			}
+			merged.Version = config.Version
+		}
+
+		if config.Defaults != nil {
+			if defaultsFile != "" {
+				return nil, fmt.Errorf("%s: defaults are already defined in %s", file, defaultsFile)
+			}
+			merged.Defaults, defaultsFile = config.Defaults, file
+		}
+
+		for name, profile := range config.Profiles {
+			if other, exists := profileFiles[name]; exists {
+				return nil, fmt.Errorf("%s: profile %q is already defined in %s", file, name, other)
+			}
+			if merged.Profiles == nil {
+				merged.Profiles = make(map[string]*RepositoryDefaults)
+			}
+			merged.Profiles[name] = profile
+			profileFiles[name] = file
+		}
+
+		for field, strategy := range config.Merge {
+			if other, exists := strategyFiles[field]; exists {
+				return nil, fmt.Errorf("%s: merge strategy for %q is already defined in %s", file, field, other)
+			}
+			if merged.Merge == nil {
+				merged.Merge = make(MergeStrategies)
+			}
+			merged.Merge[field] = strategy
+			strategyFiles[field] = file
+		}
+
+		for name, value := range config.Vars {
+			if other, exists := varFiles[name]; exists {
+				return nil, fmt.Errorf("%s: variable %q is already defined in %s", file, name, other)
+			}
+			if merged.Vars == nil {
+				merged.Vars = make(map[string]string)
+			}
+			merged.Vars[name] = value
+			varFiles[name] = file
+		}
+
+		positions := config.positions.shifted("repositories", len(merged.Repositories)).shifted("repository_sets", len(merged.RepositorySets))
+		merged.positions = merged.positions.merge(positions)
+
+		merged.Repositories = append(merged.Repositories, config.Repositories...)
+		merged.RepositorySets = append(merged.RepositorySets, config.RepositorySets...)
+	}
+
+	if err := merged.Validate(); err != nil {
+		return nil, fmt.Errorf("multi-repository configuration validation failed: %w", err)
+	}
+
+	return merged, nil
+}
+
+// decodeConfigFile reads one file of a composed configuration. It is not
+// validated on its own, since its repositories may use profiles or defaults
+// defined in another file.
+func decodeConfigFile(filename string, options LoadOptions) (*MultiRepositoryConfig, error) {
+	source, err := readConfigFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(source.data)) == 0 {
+		return &MultiRepositoryConfig{}, nil
+	}
+
+	format, err := source.detectFormat(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == FormatSingleRepository {
+		var repo RepositoryConfig
+		if err := yaml.Unmarshal(source.data, &repo); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		config := &MultiRepositoryConfig{Repositories: []RepositoryConfig{repo}}
+		config.setPositions(source.positions.prefixed("repositories[0]"))
+		return config, nil
+	}
+
+	var config MultiRepositoryConfig
+	if err := yaml.Unmarshal(source.data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse multi-repository YAML: %w", err)
+	}
+	config.setPositions(source.positions)
+	return &config, nil
+}
+
+// configSource is a configuration document ready to be decoded
+type configSource struct {
+	data      []byte          // the document, re-encoded when includes were merged into it
+	root      *yaml.Node      // the document's content, nil when it is empty
+	positions sourcePositions // where each value is defined
+}
+
+// parseConfigSource prepares configuration data that was not read from a
+// file. Data that does not parse is left for decoding to report.
+func parseConfigSource(data []byte) *configSource {
+	source := &configSource{data: data}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err == nil && len(doc.Content) > 0 {
+		source.root = doc.Content[0]
+		source.positions = indexPositions(source.root, "", nil)
+	}
+	return source
+}
+
+// readConfigFile reads a configuration file and resolves the includes of its
+// repositories relative to the file's directory. The positions of the source
+// are in the file or in the fragment a value was included from.
+func readConfigFile(filename string) (*configSource, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return expandIncludes(data, filename)
+}
+
+// expandIncludes merges the fragments listed in the include: field of each
+// repository into that repository, resolving them relative to the directory
+// of filename. Data without includes is kept unchanged.
+func expandIncludes(data []byte, filename string) (*configSource, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return &configSource{data: data}, nil
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return &configSource{data: data, root: doc.Content[0], positions: indexPositions(doc.Content[0], filename, nil)}, nil
+	}
+
+	// The repositories of a multi-repository file, or the file itself
+	root := doc.Content[0]
+	repositories := []*yaml.Node{root}
+	if list := mappingValue(root, "repositories"); list != nil && list.Kind == yaml.SequenceNode {
+		repositories = list.Content
+	}
+
+	expanded := false
+	origins := make(map[*yaml.Node]string)
+	for _, repo := range repositories {
+		included, err := includeFragments(repo, filepath.Dir(filename), nil, origins)
+		if err != nil {
+			return nil, err
+		}
+		expanded = expanded || included
+	}
+
+	source := &configSource{data: data, root: root, positions: indexPositions(root, filename, origins)}
+	if expanded {
+		expandedData, err := yaml.Marshal(&doc)
+		if err != nil {
+			return nil, err
+		}
+		source.data = expandedData
+	}
+	return source, nil
+}
+
+// includeFragments replaces the include: field of a mapping with the fields of
+// the fragments it lists. Fields of the mapping itself take precedence, and
+// later fragments take precedence over earlier ones. stack holds the fragments
+// being expanded, to detect include cycles, and origins records the fragment
+// each included value comes from.
+func includeFragments(node *yaml.Node, dir string, stack []string, origins map[*yaml.Node]string) (bool, error) {
+	if node.Kind != yaml.MappingNode {
+		return false, nil
+	}
+	index := mappingIndex(node, includeKey)
+	if index < 0 {
+		return false, nil
+	}
+
+	value := node.Content[index+1]
+	var files []string
+	switch value.Kind {
+	case yaml.ScalarNode:
+		files = []string{value.Value}
+	default:
+		if err := value.Decode(&files); err != nil {
+			return false, fmt.Errorf("line %d: include must be a file or a list of files", value.Line)
+		}
+	}
+	node.Content = slices.Delete(node.Content, index, index+2)
+
+	for i := len(files) - 1; i >= 0; i-- {
+		path, fragment, err := loadFragment(files[i], dir, stack, origins)
+		if err != nil {
+			return false, err
+		}
+		for j := 0; j+1 < len(fragment.Content); j += 2 {
+			if mappingIndex(node, fragment.Content[j].Value) < 0 {
+				value := fragment.Content[j+1]
+				node.Content = append(node.Content, fragment.Content[j], value)
+				// Values the fragment includes itself keep their own origin
+				if _, ok := origins[value]; !ok {
+					origins[value] = path
+				}
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// loadFragment reads an include fragment, resolving its own includes relative
+// to the fragment's directory, and returns its path and content
+func loadFragment(file, dir string, stack []string, origins map[*yaml.Node]string) (string, *yaml.Node, error) {
+	path := file
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if slices.Contains(stack, path) {
+		return "", nil, fmt.Errorf("include cycle: %s", strings.Join(append(stack, path), " -> "))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read include: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", nil, fmt.Errorf("failed to parse include %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return path, &yaml.Node{Kind: yaml.MappingNode}, nil
+	}
+
+	fragment := doc.Content[0]
+	if fragment.Kind != yaml.MappingNode {
+		return "", nil, fmt.Errorf("include %s must contain a mapping of repository settings", path)
+	}
+
+	if _, err := includeFragments(fragment, filepath.Dir(path), append(stack, path), origins); err != nil {
+		return "", nil, err
+	}
+	return path, fragment, nil
+}
+
+// mappingIndex returns the index of key within the content of a mapping node, or -1
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if index := mappingIndex(node, key); index >= 0 {
+		return node.Content[index+1]
+	}
+	return nil
+}
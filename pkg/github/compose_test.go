@@ -0,0 +1,378 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfigFiles writes files, keyed by path relative to dir
+func writeConfigFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestLoadConfigFromPath_Directory(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"defaults.yaml": `
+defaults:
+  private: true
+  topics: [managed]
+profiles:
+  service:
+    topics: [service]
+    teams:
+      - team: backend
+        permission: write
+  public:
+    private: false
+`,
+		"services/api.yaml": `
+name: api
+profiles: [service, public]
+`,
+		"services/workers.yaml": `
+repositories:
+  - name: worker
+    profiles: [service]
+    description: Background jobs
+  - name: scheduler
+`,
+		"_fragments/ignored.yaml": `
+topics: [not-a-repository]
+`,
+		"README.md": "not configuration",
+	})
+
+	configData, format, err := LoadConfigFromPath(dir)
+	if err != nil {
+		t.Fatalf("LoadConfigFromPath() error = %v", err)
+	}
+	if format != FormatMultiRepository {
+		t.Errorf("LoadConfigFromPath() format = %v, want %v", format, FormatMultiRepository)
+	}
+
+	config := configData.(*MultiRepositoryConfig)
+	var names []string
+	for _, repo := range config.Repositories {
+		names = append(names, repo.Name)
+	}
+	if got := strings.Join(names, ","); got != "api,worker,scheduler" {
+		t.Errorf("repositories = %s, want api,worker,scheduler", got)
+	}
+	if config.Defaults == nil || len(config.Profiles) != 2 {
+		t.Fatalf("defaults and profiles were not merged: %+v", config)
+	}
+
+	layers, err := config.layersFor(&config.Repositories[0])
+	if err != nil {
+		t.Fatalf("layersFor() error = %v", err)
+	}
+	merged, err := NewConfigMerger().MergeLayers(layers, &config.Repositories[0])
+	if err != nil {
+		t.Fatalf("MergeLayers() error = %v", err)
+	}
+	if merged.Private {
+		t.Errorf("api should be public, the public profile overrides the defaults")
+	}
+	if got := strings.Join(merged.Topics, ","); got != "service" {
+		t.Errorf("api topics = %s, want service", got)
+	}
+	if len(merged.Teams) != 1 || merged.Teams[0].TeamSlug != "backend" {
+		t.Errorf("api teams = %+v, want backend from the service profile", merged.Teams)
+	}
+}
+
+func TestLoadConfigFromPath_Merge(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"defaults.yaml": `
+merge:
+  topics: append
+defaults:
+  topics: [managed]
+`,
+		"repos.yaml": `
+repositories:
+  - name: api
+    topics: [api]
+`,
+	})
+
+	configData, _, err := LoadConfigFromPath(dir)
+	if err != nil {
+		t.Fatalf("LoadConfigFromPath() error = %v", err)
+	}
+
+	config := configData.(*MultiRepositoryConfig)
+	if config.Merge["topics"] != MergeStrategyAppend {
+		t.Errorf("merge = %v, want the topics strategy from defaults.yaml", config.Merge)
+	}
+}
+
+func TestLoadConfigFromPath_Vars(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"defaults.yaml": `
+vars:
+  team: platform
+defaults:
+  topics: [managed]
+`,
+		"repos.yaml": `
+vars:
+  ci: https://ci.example.com
+repositories:
+  - name: api
+    description: ${team} service
+`,
+	})
+
+	configData, _, err := LoadConfigFromPath(dir)
+	if err != nil {
+		t.Fatalf("LoadConfigFromPath() error = %v", err)
+	}
+
+	config := configData.(*MultiRepositoryConfig)
+	if config.Vars["team"] != "platform" || config.Vars["ci"] != "https://ci.example.com" {
+		t.Errorf("vars = %v, want the variables of both files", config.Vars)
+	}
+}
+
+func TestLoadConfigFromPath_Glob(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"b.yaml":   "name: bravo\n",
+		"a.yaml":   "name: alpha\n",
+		"c.yml":    "name: charlie\n",
+		"notes.md": "name: ignored\n",
+	})
+
+	configData, _, err := LoadConfigFromPath(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfigFromPath() error = %v", err)
+	}
+
+	config := configData.(*MultiRepositoryConfig)
+	if len(config.Repositories) != 2 || config.Repositories[0].Name != "alpha" || config.Repositories[1].Name != "bravo" {
+		t.Errorf("repositories = %+v, want alpha and bravo", config.Repositories)
+	}
+
+	if _, _, err := LoadConfigFromPath(filepath.Join(dir, "*.json")); err == nil {
+		t.Errorf("LoadConfigFromPath() expected an error for a pattern without matches")
+	}
+}
+
+func TestLoadConfigFromPath_File(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{"repo.yaml": "name: single\n"})
+
+	configData, format, err := LoadConfigFromPath(filepath.Join(dir, "repo.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfigFromPath() error = %v", err)
+	}
+	if format != FormatSingleRepository {
+		t.Errorf("LoadConfigFromPath() format = %v, want %v", format, FormatSingleRepository)
+	}
+	if configData.(*RepositoryConfig).Name != "single" {
+		t.Errorf("LoadConfigFromPath() name = %s, want single", configData.(*RepositoryConfig).Name)
+	}
+}
+
+func TestLoadConfigFromPath_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "defaults defined twice",
+			files: map[string]string{
+				"a.yaml": "defaults:\n  private: true\nrepositories:\n  - name: a\n",
+				"b.yaml": "defaults:\n  private: false\nrepositories:\n  - name: b\n",
+			},
+			wantErr: "defaults are already defined",
+		},
+		{
+			name: "profile defined twice",
+			files: map[string]string{
+				"a.yaml": "profiles:\n  service:\n    private: true\n",
+				"b.yaml": "profiles:\n  service:\n    private: false\nrepositories:\n  - name: b\n",
+			},
+			wantErr: `profile "service" is already defined`,
+		},
+		{
+			name: "merge strategy defined twice",
+			files: map[string]string{
+				"a.yaml": "merge:\n  topics: append\nrepositories:\n  - name: a\n",
+				"b.yaml": "merge:\n  topics: override\nrepositories:\n  - name: b\n",
+			},
+			wantErr: `merge strategy for "topics" is already defined`,
+		},
+		{
+			name: "variable defined twice",
+			files: map[string]string{
+				"a.yaml": "vars:\n  team: backend\nrepositories:\n  - name: a\n",
+				"b.yaml": "vars:\n  team: frontend\nrepositories:\n  - name: b\n",
+			},
+			wantErr: `variable "team" is already defined`,
+		},
+		{
+			name: "unknown profile",
+			files: map[string]string{
+				"a.yaml": "name: a\nprofiles: [missing]\n",
+			},
+			wantErr: "unknown profile",
+		},
+		{
+			name: "duplicate repository across files",
+			files: map[string]string{
+				"a.yaml": "name: same\n",
+				"b.yaml": "name: same\n",
+			},
+			wantErr: "duplicate repository name",
+		},
+		{
+			name: "conflicting versions",
+			files: map[string]string{
+				"a.yaml": "version: \"1.0\"\nrepositories:\n  - name: a\n",
+				"b.yaml": "version: \"2.0\"\nrepositories:\n  - name: b\n",
+			},
+			wantErr: "conflicts with version",
+		},
+		{
+			name:    "empty directory",
+			files:   map[string]string{"_fragments/team.yaml": "topics: [x]\n"},
+			wantErr: "no configuration files found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFiles(t, dir, tt.files)
+
+			_, _, err := LoadConfigFromPath(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfigFromPath() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFromFile_Includes(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"repos.yaml": `
+repositories:
+  - name: api
+    include: [_fragments/base.yaml, _fragments/backend.yaml]
+    description: API service
+  - name: docs
+    include: _fragments/base.yaml
+`,
+		"_fragments/base.yaml": `
+description: Shared description
+private: true
+topics: [base]
+`,
+		"_fragments/backend.yaml": `
+include: teams.yaml
+topics: [backend]
+`,
+		"_fragments/teams.yaml": `
+teams:
+  - team: backend
+    permission: write
+`,
+	})
+
+	configData, _, err := LoadConfigFromFile(filepath.Join(dir, "repos.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile() error = %v", err)
+	}
+
+	config := configData.(*MultiRepositoryConfig)
+	api, docs := config.Repositories[0], config.Repositories[1]
+
+	if api.Description != "API service" {
+		t.Errorf("api description = %q, the repository's own field should win", api.Description)
+	}
+	if strings.Join(api.Topics, ",") != "backend" {
+		t.Errorf("api topics = %v, the later fragment should win", api.Topics)
+	}
+	if !api.Private {
+		t.Errorf("api should be private from the base fragment")
+	}
+	if len(api.Teams) != 1 || api.Teams[0].TeamSlug != "backend" {
+		t.Errorf("api teams = %+v, want the nested include", api.Teams)
+	}
+	if len(api.Include) != 0 {
+		t.Errorf("api include = %v, want it resolved", api.Include)
+	}
+	if docs.Description != "Shared description" {
+		t.Errorf("docs description = %q, want it from the fragment", docs.Description)
+	}
+}
+
+func TestLoadConfigFromFile_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "missing fragment",
+			files: map[string]string{
+				"repo.yaml": "name: a\ninclude: [missing.yaml]\n",
+			},
+			wantErr: "failed to read include",
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"repo.yaml": "name: a\ninclude: [one.yaml]\n",
+				"one.yaml":  "include: [two.yaml]\n",
+				"two.yaml":  "include: [one.yaml]\n",
+			},
+			wantErr: "include cycle",
+		},
+		{
+			name: "fragment is not a mapping",
+			files: map[string]string{
+				"repo.yaml": "name: a\ninclude: [list.yaml]\n",
+				"list.yaml": "- a\n- b\n",
+			},
+			wantErr: "must contain a mapping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFiles(t, dir, tt.files)
+
+			_, _, err := LoadConfigFromFile(filepath.Join(dir, "repo.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfigFromFile() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadRepositoryConfig_UnresolvedInclude(t *testing.T) {
+	_, err := LoadRepositoryConfig([]byte("name: a\ninclude: [base.yaml]\n"))
+	if err == nil || !strings.Contains(err.Error(), "includes are only resolved") {
+		t.Errorf("LoadRepositoryConfig() error = %v, want unresolved include error", err)
+	}
+}
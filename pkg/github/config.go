@@ -3,8 +3,8 @@ package github
 import (
 	"fmt"
 	"net/url"
-	"os"
 	"regexp"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -21,6 +21,32 @@ type RepositoryConfig struct {
 	Collaborators []Collaborator         `yaml:"collaborators,omitempty" validate:"dive"`
 	Teams         []TeamAccess           `yaml:"teams,omitempty" validate:"dive"`
 	Webhooks      []Webhook              `yaml:"webhooks,omitempty" validate:"dive"`
+
+	// Labels is free-form metadata used to select repositories, as in tier=critical;
+	// it is not applied to GitHub
+	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// Merge overrides, for this repository, the merge strategies of the configuration
+	Merge MergeStrategies `yaml:"merge,omitempty"`
+
+	// Vars defines variables for ${name} references, overriding those of the configuration
+	Vars map[string]string `yaml:"vars,omitempty"`
+
+	// Profiles names the profiles of a multi-repository configuration layered,
+	// in order, between the global defaults and the settings above
+	Profiles []string `yaml:"profiles,omitempty"`
+
+	// Include lists YAML fragments merged into this repository when the
+	// configuration is loaded from a file; the repository's own fields win
+	Include []string `yaml:"include,omitempty"`
+
+	// discovered is the live repository when the repository was matched by a
+	// repository set rather than listed, and setLayers the settings of the sets
+	discovered *Repository
+	setLayers  []*RepositoryDefaults
+
+	// positions records where the fields were defined, when parsed from YAML
+	positions sourcePositions
 }
 
 // BranchProtectionRule defines branch protection settings in configuration
@@ -32,6 +58,7 @@ type BranchProtectionRule struct {
 	DismissStaleReviews    bool     `yaml:"dismiss_stale_reviews"`
 	RequireCodeOwnerReview bool     `yaml:"require_code_owner_review"`
 	RestrictPushes         []string `yaml:"restrict_pushes,omitempty"`
+	Remove                 bool     `yaml:"-"` // tagged !remove in configuration
 }
 
 // Validate validates the repository configuration
@@ -39,11 +66,7 @@ func (r *RepositoryConfig) Validate() error {
 	var validationErrors ValidationErrors
 
 	if err := r.validateName(); err != nil {
-		if valErr, ok := err.(*ValidationError); ok {
-			validationErrors = append(validationErrors, *valErr)
-		} else {
-			validationErrors.Add("name", r.Name, err.Error())
-		}
+		validationErrors.addError("name", r.Name, err)
 	}
 
 	if err := r.validateDescription(); err != nil {
@@ -51,26 +74,35 @@ func (r *RepositoryConfig) Validate() error {
 	}
 
 	if err := r.validateTopics(); err != nil {
-		validationErrors.Add("topics", fmt.Sprintf("%v", r.Topics), err.Error())
+		validationErrors.addError("topics", fmt.Sprintf("%v", r.Topics), err)
 	}
 
 	if err := r.validateBranchRules(); err != nil {
-		validationErrors.Add("branch_protection", "", err.Error())
+		validationErrors.addError("branch_protection", "", err)
 	}
 
 	if err := r.validateCollaborators(); err != nil {
-		validationErrors.Add("collaborators", "", err.Error())
+		validationErrors.addError("collaborators", "", err)
 	}
 
 	if err := r.validateTeams(); err != nil {
-		validationErrors.Add("teams", "", err.Error())
+		validationErrors.addError("teams", "", err)
 	}
 
 	if err := r.validateWebhooks(); err != nil {
-		validationErrors.Add("webhooks", "", err.Error())
+		validationErrors.addError("webhooks", "", err)
+	}
+
+	if err := r.Merge.validate(); err != nil {
+		validationErrors.Add("merge", "", err.Error())
+	}
+
+	if len(r.Include) > 0 {
+		validationErrors.Add("include", strings.Join(r.Include, ", "), "includes are only resolved when the configuration is loaded from a file")
 	}
 
 	if validationErrors.HasErrors() {
+		validationErrors.locate(r.positions)
 		return &Error{
 			Type:      ErrorTypeValidation,
 			Message:   validationErrors.Error(),
@@ -82,6 +114,16 @@ func (r *RepositoryConfig) Validate() error {
 	return nil
 }
 
+// fieldError returns a validation error for the value at path, such as
+// collaborators[2].permission
+func fieldError(path, value, format string, args ...any) *ValidationError {
+	return &ValidationError{
+		Field:   path,
+		Value:   value,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
 // validateName validates repository name according to GitHub rules
 func (r *RepositoryConfig) validateName() error {
 	if r.Name == "" {
@@ -137,16 +179,20 @@ func (r *RepositoryConfig) validateTopics() error {
 	}
 
 	for i, topic := range r.Topics {
+		field := fmt.Sprintf("topics[%d]", i)
 		if len(topic) == 0 {
-			return fmt.Errorf("topic %d cannot be empty", i+1)
+			return fieldError(field, topic, "topic %d cannot be empty", i+1)
+		}
+		if hasVariables(topic) {
+			continue
 		}
 		if len(topic) > 50 {
-			return fmt.Errorf("topic %d must be 50 characters or less", i+1)
+			return fieldError(field, topic, "topic %d must be 50 characters or less", i+1)
 		}
 		// GitHub topic validation
 		validTopic := regexp.MustCompile(`^[a-z0-9-]+$`)
 		if !validTopic.MatchString(topic) {
-			return fmt.Errorf("topic %d can only contain lowercase letters, numbers, and hyphens", i+1)
+			return fieldError(field, topic, "topic %d can only contain lowercase letters, numbers, and hyphens", i+1)
 		}
 	}
 
@@ -156,11 +202,15 @@ func (r *RepositoryConfig) validateTopics() error {
 // validateBranchRules validates branch protection rules
 func (r *RepositoryConfig) validateBranchRules() error {
 	for i, rule := range r.BranchRules {
+		field := fmt.Sprintf("branch_protection[%d]", i)
 		if rule.Pattern == "" {
-			return fmt.Errorf("branch protection rule %d: pattern is required", i+1)
+			return fieldError(field+".pattern", "", "branch protection rule %d: pattern is required", i+1)
+		}
+		if rule.Remove {
+			continue
 		}
 		if rule.RequiredReviews < 0 || rule.RequiredReviews > 6 {
-			return fmt.Errorf("branch protection rule %d: required reviews must be between 0 and 6", i+1)
+			return fieldError(field+".required_reviews", fmt.Sprintf("%d", rule.RequiredReviews), "branch protection rule %d: required reviews must be between 0 and 6", i+1)
 		}
 	}
 	return nil
@@ -169,14 +219,18 @@ func (r *RepositoryConfig) validateBranchRules() error {
 // validateCollaborators validates collaborator configurations
 func (r *RepositoryConfig) validateCollaborators() error {
 	for i, collab := range r.Collaborators {
+		field := fmt.Sprintf("collaborators[%d]", i)
 		if collab.Username == "" {
-			return fmt.Errorf("collaborator %d: username is required", i+1)
+			return fieldError(field+".username", "", "collaborator %d: username is required", i+1)
 		}
-		if err := validateGitHubUsername(collab.Username); err != nil {
-			return fmt.Errorf("collaborator %d: %w", i+1, err)
+		if err := validateGitHubUsername(collab.Username); err != nil && !hasVariables(collab.Username) {
+			return fieldError(field+".username", collab.Username, "collaborator %d: %v", i+1, err)
+		}
+		if collab.Remove {
+			continue
 		}
 		if !isValidPermission(collab.Permission) {
-			return fmt.Errorf("collaborator %d: permission must be one of: read, write, admin", i+1)
+			return fieldError(field+".permission", collab.Permission, "collaborator %d: permission must be one of: read, write, admin", i+1)
 		}
 	}
 	return nil
@@ -185,14 +239,18 @@ func (r *RepositoryConfig) validateCollaborators() error {
 // validateTeams validates team access configurations
 func (r *RepositoryConfig) validateTeams() error {
 	for i, team := range r.Teams {
+		field := fmt.Sprintf("teams[%d]", i)
 		if team.TeamSlug == "" {
-			return fmt.Errorf("team %d: team slug is required", i+1)
+			return fieldError(field+".team", "", "team %d: team slug is required", i+1)
+		}
+		if err := validateGitHubTeamSlug(team.TeamSlug); err != nil && !hasVariables(team.TeamSlug) {
+			return fieldError(field+".team", team.TeamSlug, "team %d: %v", i+1, err)
 		}
-		if err := validateGitHubTeamSlug(team.TeamSlug); err != nil {
-			return fmt.Errorf("team %d: %w", i+1, err)
+		if team.Remove {
+			continue
 		}
 		if !isValidPermission(team.Permission) {
-			return fmt.Errorf("team %d: permission must be one of: read, write, admin", i+1)
+			return fieldError(field+".permission", team.Permission, "team %d: permission must be one of: read, write, admin", i+1)
 		}
 	}
 	return nil
@@ -201,75 +259,81 @@ func (r *RepositoryConfig) validateTeams() error {
 // validateWebhooks validates webhook configurations
 func (r *RepositoryConfig) validateWebhooks() error {
 	for i, webhook := range r.Webhooks {
+		field := fmt.Sprintf("webhooks[%d]", i)
 		if webhook.URL == "" {
-			return fmt.Errorf("webhook %d: URL is required", i+1)
+			return fieldError(field+".url", "", "webhook %d: URL is required", i+1)
 		}
-		parsedURL, err := url.Parse(webhook.URL)
-		if err != nil {
-			return fmt.Errorf("webhook %d: invalid URL format: %w", i+1, err)
+		if webhook.Remove {
+			continue
 		}
-		// Require HTTP or HTTPS scheme for webhooks
-		if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-			return fmt.Errorf("webhook %d: URL must use http or https scheme", i+1)
-		}
-		if parsedURL.Host == "" {
-			return fmt.Errorf("webhook %d: URL must have a valid host", i+1)
+		if !hasVariables(webhook.URL) {
+			parsedURL, err := url.Parse(webhook.URL)
+			if err != nil {
+				return fieldError(field+".url", webhook.URL, "webhook %d: invalid URL format: %v", i+1, err)
+			}
+			// Require HTTP or HTTPS scheme for webhooks
+			if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+				return fieldError(field+".url", webhook.URL, "webhook %d: URL must use http or https scheme", i+1)
+			}
+			if parsedURL.Host == "" {
+				return fieldError(field+".url", webhook.URL, "webhook %d: URL must have a valid host", i+1)
+			}
 		}
 		if len(webhook.Events) == 0 {
-			return fmt.Errorf("webhook %d: at least one event is required", i+1)
+			return fieldError(field+".events", "", "webhook %d: at least one event is required", i+1)
 		}
 		for j, event := range webhook.Events {
-			if !isValidWebhookEvent(event) {
-				return fmt.Errorf("webhook %d, event %d: invalid event type '%s'", i+1, j+1, event)
+			if !isValidWebhookEvent(event) && !hasVariables(event) {
+				return fieldError(fmt.Sprintf("%s.events[%d]", field, j), event, "webhook %d, event %d: invalid event type '%s'", i+1, j+1, event)
 			}
 		}
 	}
 	return nil
 }
 
+// validPermissions are the permission levels accepted for collaborators and teams
+var validPermissions = []string{"read", "write", "admin"}
+
+// validWebhookEvents are the webhook events accepted in configuration
+var validWebhookEvents = []string{
+	"push",
+	"pull_request",
+	"issues",
+	"issue_comment",
+	"pull_request_review",
+	"pull_request_review_comment",
+	"commit_comment",
+	"create",
+	"delete",
+	"deployment",
+	"deployment_status",
+	"fork",
+	"gollum",
+	"member",
+	"membership",
+	"milestone",
+	"organization",
+	"page_build",
+	"project",
+	"project_card",
+	"project_column",
+	"public",
+	"release",
+	"repository",
+	"status",
+	"team",
+	"team_add",
+	"watch",
+}
+
 // isValidPermission checks if the permission level is valid
 func isValidPermission(permission string) bool {
-	validPermissions := map[string]bool{
-		"read":  true,
-		"write": true,
-		"admin": true,
-	}
-	return validPermissions[permission]
+	return slices.Contains(validPermissions, permission)
 }
 
 // isValidWebhookEvent checks if the webhook event is valid
 func isValidWebhookEvent(event string) bool {
-	validEvents := map[string]bool{
-		"push":                        true,
-		"pull_request":                true,
-		"issues":                      true,
-		"issue_comment":               true,
-		"pull_request_review":         true,
-		"pull_request_review_comment": true,
-		"commit_comment":              true,
-		"create":                      true,
-		"delete":                      true,
-		"deployment":                  true,
-		"deployment_status":           true,
-		"fork":                        true,
-		"gollum":                      true,
-		"member":                      true,
-		"membership":                  true,
-		"milestone":                   true,
-		"organization":                true,
-		"page_build":                  true,
-		"project":                     true,
-		"project_card":                true,
-		"project_column":              true,
-		"public":                      true,
-		"release":                     true,
-		"repository":                  true,
-		"status":                      true,
-		"team":                        true,
-		"team_add":                    true,
-		"watch":                       true,
-	}
-	return validEvents[event]
+	return slices.Contains(validWebhookEvents, event)
 }
 
 // validateGitHubUsername validates a GitHub username according to GitHub's rules
@@ -322,10 +386,26 @@ func validateGitHubTeamSlug(teamSlug string) error {
 
 // LoadRepositoryConfig loads repository configuration from YAML file
 func LoadRepositoryConfig(data []byte) (*RepositoryConfig, error) {
+	return loadRepositoryConfig(parseConfigSource(data))
+}
+
+// LoadRepositoryConfigStrict loads repository configuration from YAML data,
+// rejecting unknown and duplicate keys
+func LoadRepositoryConfigStrict(data []byte) (*RepositoryConfig, error) {
+	source := parseConfigSource(data)
+	if err := source.checkStrict(FormatSingleRepository); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	return loadRepositoryConfig(source)
+}
+
+// loadRepositoryConfig decodes and validates repository configuration
+func loadRepositoryConfig(source *configSource) (*RepositoryConfig, error) {
 	var config RepositoryConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	if err := yaml.Unmarshal(source.data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
+	config.positions = source.positions
 
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -338,34 +418,37 @@ func LoadRepositoryConfig(data []byte) (*RepositoryConfig, error) {
 // This function now detects the format and handles both single and multi-repository configurations
 // For backward compatibility, it returns a single RepositoryConfig even for multi-repo files
 func LoadRepositoryConfigFromFile(filename string) (*RepositoryConfig, error) {
-	data, err := os.ReadFile(filename)
+	source, err := readConfigFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
 	// Detect configuration format
-	detector := NewConfigDetector()
-	format, err := detector.DetectFormat(data)
+	format, err := source.detectFormat(LoadOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to detect config format: %w", err)
+		return nil, err
 	}
 
 	switch format {
 	case FormatSingleRepository:
-		return LoadRepositoryConfig(data)
+		return loadRepositoryConfig(source)
 	case FormatMultiRepository:
 		// For backward compatibility, if this is a multi-repo config but only has one repository,
 		// return that single repository. Otherwise, return an error indicating multi-repo format.
-		multiConfig, err := detector.LoadMultiRepo(data)
+		multiConfig, err := loadMultiRepositoryConfig(source)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load multi-repository config: %w", err)
 		}
 
 		if len(multiConfig.Repositories) == 1 {
-			// Apply defaults to the single repository if present
-			if multiConfig.Defaults != nil {
-				merger := NewConfigMerger()
-				merged, err := merger.MergeDefaults(multiConfig.Defaults, &multiConfig.Repositories[0])
+			// Apply defaults and profiles to the single repository if present
+			layers, err := multiConfig.layersFor(&multiConfig.Repositories[0])
+			if err != nil {
+				return nil, fmt.Errorf("failed to merge defaults: %w", err)
+			}
+			if multiConfig.Defaults != nil || len(layers) > 1 || multiConfig.Repositories[0].hasRemovals() {
+				merger := multiConfig.mergerFor(NewConfigMerger(), &multiConfig.Repositories[0])
+				merged, err := merger.MergeLayers(layers, &multiConfig.Repositories[0])
 				if err != nil {
 					return nil, fmt.Errorf("failed to merge defaults: %w", err)
 				}
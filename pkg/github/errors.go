@@ -1,6 +1,7 @@
 package github
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -339,14 +340,21 @@ type ValidationError struct {
 	Value   string `json:"value,omitempty"`
 	Message string `json:"message"`
 	Code    string `json:"code,omitempty"`
+
+	// Position is where the field is defined, when the configuration was parsed from YAML
+	Position *SourcePosition `json:"position,omitempty"`
 }
 
 // Error implements the error interface
 func (e *ValidationError) Error() string {
+	location := ""
+	if e.Position != nil {
+		location = e.Position.String() + ": "
+	}
 	if e.Value != "" {
-		return fmt.Sprintf("validation error for field '%s' (value: %s): %s", e.Field, e.Value, e.Message)
+		return fmt.Sprintf("%svalidation error for field '%s' (value: %s): %s", location, e.Field, e.Value, e.Message)
 	}
-	return fmt.Sprintf("validation error for field '%s': %s", e.Field, e.Message)
+	return fmt.Sprintf("%svalidation error for field '%s': %s", location, e.Field, e.Message)
 }
 
 // ValidationErrors represents multiple validation errors
@@ -378,6 +386,32 @@ func (e *ValidationErrors) Add(field, value, message string) {
 	})
 }
 
+// addError adds err, keeping the field of a ValidationError and reporting
+// other errors against field
+func (e *ValidationErrors) addError(field, value string, err error) {
+	var valErr *ValidationError
+	if errors.As(err, &valErr) {
+		*e = append(*e, *valErr)
+		return
+	}
+	e.Add(field, value, err.Error())
+}
+
+// addNested adds the errors of a nested value at path, such as a repository
+// of a multi-repository configuration, with their fields made relative to the
+// enclosing value. Errors without field details are reported against path.
+func (e *ValidationErrors) addNested(path, value string, err error) {
+	var nested ValidationErrors
+	if !errors.As(err, &nested) {
+		e.Add(path, value, err.Error())
+		return
+	}
+	for _, valErr := range nested {
+		valErr.Field = joinFieldPath(path, valErr.Field)
+		*e = append(*e, valErr)
+	}
+}
+
 // HasErrors returns true if there are validation errors
 func (e ValidationErrors) HasErrors() bool {
 	return len(e) > 0
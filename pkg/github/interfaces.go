@@ -6,6 +6,7 @@ type APIClient interface {
 	GetRepository(owner, name string) (*Repository, error)
 	CreateRepository(config RepositoryConfig) (*Repository, error)
 	UpdateRepository(owner, name string, config RepositoryConfig) error
+	ListOrganizationRepositories(org string) ([]*Repository, error)
 
 	// Branch protection operations
 	GetBranchProtection(owner, name, branch string) (*BranchProtection, error)
@@ -54,6 +55,9 @@ type ReconciliationPlan struct {
 	Collaborators []CollaboratorChange `json:"collaborators,omitempty"`
 	Teams         []TeamChange         `json:"teams,omitempty"`
 	Webhooks      []WebhookChange      `json:"webhooks,omitempty"`
+
+	// current is the repository as planned against, nil when it is created
+	current *Repository
 }
 
 // RepositoryChange represents a change to repository settings
@@ -0,0 +1,149 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// variablePattern matches ${name} references, and $${ which escapes a literal ${
+var variablePattern = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// hasVariables reports whether value references variables, in which case its
+// format can only be validated once the variables are resolved
+func hasVariables(value string) bool {
+	return strings.Contains(value, "${")
+}
+
+// InterpolateRepository returns a copy of repo with the ${...} references in
+// its string settings resolved. The references available are:
+//
+//	${repo.name}   the repository name
+//	${repo.owner}  the repository owner, left as is when owner is empty
+//	${env.NAME}    the environment variable NAME, which must be set
+//	${name}        the variable name from vars, which may reference others
+//
+// Write $${ for a literal ${.
+func InterpolateRepository(repo *RepositoryConfig, owner string, vars map[string]string) (*RepositoryConfig, error) {
+	expanded, err := (&DefaultConfigMerger{}).deepCopyRepositoryConfig(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := &variableResolver{
+		repo:      repo.Name,
+		owner:     owner,
+		vars:      vars,
+		resolving: make(map[string]bool),
+	}
+
+	var errs []string
+	expand := func(field string, value *string) {
+		resolved, err := resolver.expand(*value)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", field, err))
+			return
+		}
+		*value = resolved
+	}
+
+	expand("description", &expanded.Description)
+	for i := range expanded.Topics {
+		expand(fmt.Sprintf("topics[%d]", i), &expanded.Topics[i])
+	}
+	for i := range expanded.BranchRules {
+		rule := &expanded.BranchRules[i]
+		expand(fmt.Sprintf("branch_protection[%d].pattern", i), &rule.Pattern)
+		for j := range rule.RequiredStatusChecks {
+			expand(fmt.Sprintf("branch_protection[%d].required_status_checks[%d]", i, j), &rule.RequiredStatusChecks[j])
+		}
+		for j := range rule.RestrictPushes {
+			expand(fmt.Sprintf("branch_protection[%d].restrict_pushes[%d]", i, j), &rule.RestrictPushes[j])
+		}
+	}
+	for i := range expanded.Collaborators {
+		expand(fmt.Sprintf("collaborators[%d].username", i), &expanded.Collaborators[i].Username)
+	}
+	for i := range expanded.Teams {
+		expand(fmt.Sprintf("teams[%d].team", i), &expanded.Teams[i].TeamSlug)
+	}
+	for i := range expanded.Webhooks {
+		webhook := &expanded.Webhooks[i]
+		expand(fmt.Sprintf("webhooks[%d].url", i), &webhook.URL)
+		expand(fmt.Sprintf("webhooks[%d].secret", i), &webhook.Secret)
+		for j := range webhook.Events {
+			expand(fmt.Sprintf("webhooks[%d].events[%d]", i, j), &webhook.Events[j])
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to resolve variables: %s", strings.Join(errs, "; "))
+	}
+	return expanded, nil
+}
+
+// variableResolver resolves the ${...} references of a single repository
+type variableResolver struct {
+	repo  string
+	owner string
+	vars  map[string]string
+
+	// resolving holds the variables being expanded, to detect cycles
+	resolving map[string]bool
+}
+
+// expand replaces every reference in value, reporting the first that fails
+func (r *variableResolver) expand(value string) (string, error) {
+	if !hasVariables(value) {
+		return value, nil
+	}
+
+	var firstErr error
+	expanded := variablePattern.ReplaceAllStringFunc(value, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+		resolved, err := r.lookup(strings.TrimSpace(match[2 : len(match)-1]))
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return match
+		}
+		return resolved
+	})
+	return expanded, firstErr
+}
+
+// lookup returns the value of a single reference
+func (r *variableResolver) lookup(name string) (string, error) {
+	switch {
+	case name == "repo.name":
+		return r.repo, nil
+	case name == "repo.owner":
+		if r.owner == "" {
+			return "${repo.owner}", nil
+		}
+		return r.owner, nil
+	case strings.HasPrefix(name, "env."):
+		key := strings.TrimPrefix(name, "env.")
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", key)
+		}
+		return value, nil
+	}
+
+	value, ok := r.vars[name]
+	if !ok {
+		return "", fmt.Errorf("undefined variable ${%s}", name)
+	}
+	if r.resolving[name] {
+		return "", fmt.Errorf("variable ${%s} refers to itself", name)
+	}
+
+	r.resolving[name] = true
+	defer delete(r.resolving, name)
+	return r.expand(value)
+}
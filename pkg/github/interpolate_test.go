@@ -0,0 +1,183 @@
+package github
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterpolateRepository(t *testing.T) {
+	t.Setenv("SYNACKLAB_TEST_SECRET", "s3cret")
+
+	vars := map[string]string{
+		"ci":      "https://ci.example.com",
+		"hooks":   "${ci}/hooks",
+		"team":    "backend",
+		"loop":    "${loop}",
+		"summary": "${repo.name} owned by ${team}",
+	}
+
+	tests := []struct {
+		name    string
+		owner   string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "repository name", value: "${repo.name}", want: "api"},
+		{name: "repository owner", owner: "acme", value: "${repo.owner}/${repo.name}", want: "acme/api"},
+		{name: "unknown owner left as is", value: "${repo.owner}", want: "${repo.owner}"},
+		{name: "environment", value: "${env.SYNACKLAB_TEST_SECRET}", want: "s3cret"},
+		{name: "custom variable", value: "${team}-reviewers", want: "backend-reviewers"},
+		{name: "nested variables", value: "${hooks}/${repo.name}", want: "https://ci.example.com/hooks/api"},
+		{name: "variable using builtins", value: "${summary}", want: "api owned by backend"},
+		{name: "spaces inside braces", value: "${ repo.name }", want: "api"},
+		{name: "escaped", value: "$${repo.name}", want: "${repo.name}"},
+		{name: "plain text", value: "no variables here", want: "no variables here"},
+		{name: "undefined variable", value: "${missing}", wantErr: "undefined variable ${missing}"},
+		{name: "unset environment variable", value: "${env.SYNACKLAB_TEST_UNSET}", wantErr: "environment variable SYNACKLAB_TEST_UNSET is not set"},
+		{name: "cycle", value: "${loop}", wantErr: "refers to itself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &RepositoryConfig{Name: "api", Description: tt.value}
+
+			got, err := InterpolateRepository(repo, tt.owner, vars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("InterpolateRepository() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InterpolateRepository() error = %v", err)
+			}
+			if got.Description != tt.want {
+				t.Errorf("InterpolateRepository() description = %q, want %q", got.Description, tt.want)
+			}
+			if repo.Description != tt.value {
+				t.Errorf("InterpolateRepository() modified the original repository")
+			}
+		})
+	}
+}
+
+func TestInterpolateRepository_Fields(t *testing.T) {
+	t.Setenv("SYNACKLAB_TEST_SECRET", "s3cret")
+
+	repo := &RepositoryConfig{
+		Name:   "api",
+		Topics: []string{"${team}"},
+		BranchRules: []BranchProtectionRule{{
+			Pattern:              "main",
+			RequiredStatusChecks: []string{"ci/${repo.name}"},
+			RestrictPushes:       []string{"${team}"},
+		}},
+		Collaborators: []Collaborator{{Username: "${lead}", Permission: "admin"}},
+		Teams:         []TeamAccess{{TeamSlug: "${team}", Permission: "write"}},
+		Webhooks: []Webhook{{
+			URL:    "https://ci.example.com/hooks/${repo.name}",
+			Secret: "${env.SYNACKLAB_TEST_SECRET}",
+			Events: []string{"push"},
+		}},
+	}
+
+	got, err := InterpolateRepository(repo, "acme", map[string]string{"team": "backend", "lead": "octocat"})
+	if err != nil {
+		t.Fatalf("InterpolateRepository() error = %v", err)
+	}
+
+	if got.Topics[0] != "backend" {
+		t.Errorf("topic = %q, want backend", got.Topics[0])
+	}
+	if got.BranchRules[0].RequiredStatusChecks[0] != "ci/api" || got.BranchRules[0].RestrictPushes[0] != "backend" {
+		t.Errorf("branch rule = %+v, want resolved status checks and push restrictions", got.BranchRules[0])
+	}
+	if got.Collaborators[0].Username != "octocat" || got.Teams[0].TeamSlug != "backend" {
+		t.Errorf("access = %+v %+v, want octocat and backend", got.Collaborators, got.Teams)
+	}
+	if got.Webhooks[0].URL != "https://ci.example.com/hooks/api" || got.Webhooks[0].Secret != "s3cret" {
+		t.Errorf("webhook = %+v, want resolved URL and secret", got.Webhooks[0])
+	}
+}
+
+func TestMultiRepositoryConfig_Variables(t *testing.T) {
+	t.Setenv("SYNACKLAB_TEST_SECRET", "s3cret")
+
+	data := `
+vars:
+  ci: https://ci.example.com
+  team: platform
+defaults:
+  description: Managed by ${team}
+  webhooks:
+    - url: ${ci}/hooks/${repo.name}
+      secret: ${env.SYNACKLAB_TEST_SECRET}
+      events: [push]
+      active: true
+repositories:
+  - name: api
+  - name: web
+    vars:
+      team: frontend
+`
+	config, err := LoadMultiRepositoryConfig([]byte(data))
+	if err != nil {
+		t.Fatalf("LoadMultiRepositoryConfig() error = %v", err)
+	}
+
+	reconciler := &multiReconciler{owner: "acme", merger: NewConfigMerger()}
+
+	api, err := reconciler.mergeRepository(config, &config.Repositories[0])
+	if err != nil {
+		t.Fatalf("mergeRepository() error = %v", err)
+	}
+	if api.Description != "Managed by platform" {
+		t.Errorf("api description = %q, want Managed by platform", api.Description)
+	}
+	if api.Webhooks[0].URL != "https://ci.example.com/hooks/api" || api.Webhooks[0].Secret != "s3cret" {
+		t.Errorf("api webhook = %+v, want the URL and secret resolved", api.Webhooks[0])
+	}
+
+	web, err := reconciler.mergeRepository(config, &config.Repositories[1])
+	if err != nil {
+		t.Fatalf("mergeRepository() error = %v", err)
+	}
+	if web.Description != "Managed by frontend" {
+		t.Errorf("web description = %q, the repository variable should win", web.Description)
+	}
+	if web.Webhooks[0].URL != "https://ci.example.com/hooks/web" {
+		t.Errorf("web webhook URL = %q, want it resolved for web", web.Webhooks[0].URL)
+	}
+	if config.Defaults.Webhooks[0].URL != "${ci}/hooks/${repo.name}" {
+		t.Errorf("merging modified the defaults: %q", config.Defaults.Webhooks[0].URL)
+	}
+}
+
+func TestMultiRepositoryConfig_VariableErrorsAtLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "undefined variable in defaults",
+			data:    "defaults:\n  description: ${missing}\nrepositories:\n  - name: a\n",
+			wantErr: "undefined variable ${missing}",
+		},
+		{
+			name:    "unset secret",
+			data:    "repositories:\n  - name: a\n    webhooks:\n      - url: https://ci.example.com\n        secret: ${env.SYNACKLAB_TEST_UNSET}\n        events: [push]\n",
+			wantErr: "environment variable SYNACKLAB_TEST_UNSET is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMultiRepositoryConfig([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadMultiRepositoryConfig() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package github
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// removeTag marks a list item that removes an inherited item instead of adding one
+const removeTag = "!remove"
+
+// mergeStrategyFields maps the fields accepted in a merge: section to the
+// field names used by DefaultConfigMerger
+var mergeStrategyFields = map[string]string{
+	"topics":            "topics",
+	"collaborators":     "collaborators",
+	"teams":             "teams",
+	"webhooks":          "webhooks",
+	"branch_protection": "branch_rules",
+}
+
+// mergeStrategyNames are the names of the merge strategies in YAML
+var mergeStrategyNames = map[MergeStrategy]string{
+	MergeStrategyOverride:  "override",
+	MergeStrategyAppend:    "append",
+	MergeStrategyDeepMerge: "deep-merge",
+}
+
+// String returns the YAML name of the merge strategy
+func (s MergeStrategy) String() string {
+	if name, ok := mergeStrategyNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseMergeStrategy parses override, append or deep-merge
+func ParseMergeStrategy(name string) (MergeStrategy, error) {
+	for strategy, strategyName := range mergeStrategyNames {
+		if strings.EqualFold(name, strategyName) {
+			return strategy, nil
+		}
+	}
+	return MergeStrategyOverride, fmt.Errorf("unknown merge strategy %q: must be one of override, append, deep-merge", name)
+}
+
+// UnmarshalYAML decodes a merge strategy from its name
+func (s *MergeStrategy) UnmarshalYAML(value *yaml.Node) error {
+	strategy, err := ParseMergeStrategy(value.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	*s = strategy
+	return nil
+}
+
+// MarshalYAML encodes a merge strategy as its name
+func (s MergeStrategy) MarshalYAML() (any, error) {
+	return s.String(), nil
+}
+
+// MergeStrategies selects, per field, how repository settings are combined
+// with the defaults and profiles beneath them. Keys are topics, collaborators,
+// teams, webhooks and branch_protection.
+type MergeStrategies map[string]MergeStrategy
+
+// validate reports fields that cannot be given a merge strategy
+func (s MergeStrategies) validate() error {
+	var unknown []string
+	for field := range s {
+		if _, ok := mergeStrategyFields[field]; !ok {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown merge fields %s: must be topics, collaborators, teams, webhooks or branch_protection", strings.Join(unknown, ", "))
+}
+
+// WithStrategies returns a copy of the merger with each set of strategies
+// applied in turn on top of its own
+func (m *DefaultConfigMerger) WithStrategies(sets ...MergeStrategies) *DefaultConfigMerger {
+	merger := &DefaultConfigMerger{strategies: make(map[string]MergeStrategy, len(m.strategies))}
+	maps.Copy(merger.strategies, m.strategies)
+	for _, set := range sets {
+		for field, strategy := range set {
+			if name, ok := mergeStrategyFields[field]; ok {
+				merger.strategies[name] = strategy
+			}
+		}
+	}
+	return merger
+}
+
+// UnmarshalYAML decodes a collaborator. Tagged !remove, either as a mapping or
+// as a bare username, it removes an inherited collaborator.
+func (c *Collaborator) UnmarshalYAML(value *yaml.Node) error {
+	if value.Tag == removeTag && value.Kind == yaml.ScalarNode {
+		*c = Collaborator{Username: value.Value, Remove: true}
+		return nil
+	}
+
+	type plain Collaborator
+	var decoded plain
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	*c = Collaborator(decoded)
+	c.Remove = value.Tag == removeTag
+	return nil
+}
+
+// UnmarshalYAML decodes team access. Tagged !remove, either as a mapping or as
+// a bare team slug, it removes inherited team access.
+func (t *TeamAccess) UnmarshalYAML(value *yaml.Node) error {
+	if value.Tag == removeTag && value.Kind == yaml.ScalarNode {
+		*t = TeamAccess{TeamSlug: value.Value, Remove: true}
+		return nil
+	}
+
+	type plain TeamAccess
+	var decoded plain
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	*t = TeamAccess(decoded)
+	t.Remove = value.Tag == removeTag
+	return nil
+}
+
+// UnmarshalYAML decodes a webhook. Tagged !remove, either as a mapping or as a
+// bare URL, it removes an inherited webhook.
+func (w *Webhook) UnmarshalYAML(value *yaml.Node) error {
+	if value.Tag == removeTag && value.Kind == yaml.ScalarNode {
+		*w = Webhook{URL: value.Value, Remove: true}
+		return nil
+	}
+
+	type plain Webhook
+	var decoded plain
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	*w = Webhook(decoded)
+	w.Remove = value.Tag == removeTag
+	return nil
+}
+
+// UnmarshalYAML decodes a branch protection rule. Tagged !remove, either as a
+// mapping or as a bare pattern, it removes an inherited rule.
+func (b *BranchProtectionRule) UnmarshalYAML(value *yaml.Node) error {
+	if value.Tag == removeTag && value.Kind == yaml.ScalarNode {
+		*b = BranchProtectionRule{Pattern: value.Value, Remove: true}
+		return nil
+	}
+
+	type plain BranchProtectionRule
+	var decoded plain
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	*b = BranchProtectionRule(decoded)
+	b.Remove = value.Tag == removeTag
+	return nil
+}
+
+// removable is a list item that can be tagged !remove; mergeKey identifies the
+// inherited item it replaces or removes
+type removable interface {
+	mergeKey() string
+	removes() bool
+}
+
+func (c Collaborator) mergeKey() string         { return c.Username }
+func (c Collaborator) removes() bool            { return c.Remove }
+func (t TeamAccess) mergeKey() string           { return t.TeamSlug }
+func (t TeamAccess) removes() bool              { return t.Remove }
+func (w Webhook) mergeKey() string              { return w.URL }
+func (w Webhook) removes() bool                 { return w.Remove }
+func (b BranchProtectionRule) mergeKey() string { return b.Pattern }
+func (b BranchProtectionRule) removes() bool    { return b.Remove }
+
+// splitRemovals separates the items tagged !remove from items and returns the
+// remaining items along with the keys of the removed ones
+func splitRemovals[T removable](items []T) ([]T, map[string]bool) {
+	var kept []T
+	var removals map[string]bool
+	for _, item := range items {
+		if item.removes() {
+			if removals == nil {
+				removals = make(map[string]bool)
+			}
+			removals[item.mergeKey()] = true
+			continue
+		}
+		kept = append(kept, item)
+	}
+	if removals == nil {
+		return items, nil
+	}
+	return kept, removals
+}
+
+// dropRemoved returns items without those whose key was removed, and without
+// removal markers left over from the layers beneath
+func dropRemoved[T removable](items []T, removals map[string]bool) []T {
+	if len(removals) == 0 && !anyRemoves(items) {
+		return items
+	}
+	var kept []T
+	for _, item := range items {
+		if !item.removes() && !removals[item.mergeKey()] {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// hasRemovals reports whether the repository removes any inherited items
+func (r *RepositoryConfig) hasRemovals() bool {
+	return anyRemoves(r.Collaborators) || anyRemoves(r.Teams) || anyRemoves(r.Webhooks) || anyRemoves(r.BranchRules)
+}
+
+func anyRemoves[T removable](items []T) bool {
+	for _, item := range items {
+		if item.removes() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package github
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseMergeStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    MergeStrategy
+		wantErr bool
+	}{
+		{name: "override", want: MergeStrategyOverride},
+		{name: "append", want: MergeStrategyAppend},
+		{name: "deep-merge", want: MergeStrategyDeepMerge},
+		{name: "Append", want: MergeStrategyAppend},
+		{name: "replace", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMergeStrategy(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMergeStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseMergeStrategy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveTagDecoding(t *testing.T) {
+	data := `
+collaborators:
+  - username: alice
+    permission: write
+  - !remove bob
+  - !remove {username: carol}
+teams:
+  - !remove legacy
+webhooks:
+  - !remove https://old.example.com/hook
+branch_protection:
+  - !remove release/*
+`
+	var repo RepositoryConfig
+	if err := yaml.Unmarshal([]byte(data), &repo); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+
+	wantCollaborators := []Collaborator{
+		{Username: "alice", Permission: "write"},
+		{Username: "bob", Remove: true},
+		{Username: "carol", Remove: true},
+	}
+	if len(repo.Collaborators) != len(wantCollaborators) {
+		t.Fatalf("collaborators = %+v, want %+v", repo.Collaborators, wantCollaborators)
+	}
+	for i, want := range wantCollaborators {
+		if repo.Collaborators[i] != want {
+			t.Errorf("collaborator %d = %+v, want %+v", i, repo.Collaborators[i], want)
+		}
+	}
+	if repo.Teams[0] != (TeamAccess{TeamSlug: "legacy", Remove: true}) {
+		t.Errorf("team = %+v, want legacy removed", repo.Teams[0])
+	}
+	if !repo.Webhooks[0].Remove || repo.Webhooks[0].URL != "https://old.example.com/hook" {
+		t.Errorf("webhook = %+v, want removal of the old hook", repo.Webhooks[0])
+	}
+	if !repo.BranchRules[0].Remove || repo.BranchRules[0].Pattern != "release/*" {
+		t.Errorf("branch rule = %+v, want removal of release/*", repo.BranchRules[0])
+	}
+	if !repo.hasRemovals() {
+		t.Errorf("hasRemovals() = false, want true")
+	}
+}
+
+func TestMergeStrategiesFromYAML(t *testing.T) {
+	data := `
+merge:
+  teams: append
+  topics: append
+defaults:
+  topics: [managed]
+  teams:
+    - team: platform
+      permission: read
+    - team: security
+      permission: read
+  collaborators:
+    - username: bot
+      permission: write
+    - username: contractor
+      permission: read
+repositories:
+  - name: api
+    topics: [api]
+    teams:
+      - team: backend
+        permission: write
+      - !remove security
+    collaborators:
+      - !remove contractor
+  - name: web
+    merge:
+      topics: override
+    topics: [web]
+    teams:
+      - team: frontend
+        permission: write
+`
+	config, err := LoadMultiRepositoryConfig([]byte(data))
+	if err != nil {
+		t.Fatalf("LoadMultiRepositoryConfig() error = %v", err)
+	}
+
+	merge := func(repo *RepositoryConfig) *RepositoryConfig {
+		t.Helper()
+		layers, err := config.layersFor(repo)
+		if err != nil {
+			t.Fatalf("layersFor() error = %v", err)
+		}
+		merged, err := config.mergerFor(NewConfigMerger(), repo).MergeLayers(layers, repo)
+		if err != nil {
+			t.Fatalf("MergeLayers() error = %v", err)
+		}
+		return merged
+	}
+
+	api := merge(&config.Repositories[0])
+	if got := teamSlugs(api.Teams); got != "backend,platform" {
+		t.Errorf("api teams = %s, want backend,platform", got)
+	}
+	if got := strings.Join(api.Topics, ","); got != "api,managed" {
+		t.Errorf("api topics = %s, want api,managed", got)
+	}
+	// Collaborators use the default override strategy, so the removal leaves the
+	// remaining defaults in place
+	if len(api.Collaborators) != 1 || api.Collaborators[0].Username != "bot" {
+		t.Errorf("api collaborators = %+v, want only bot", api.Collaborators)
+	}
+
+	web := merge(&config.Repositories[1])
+	if got := strings.Join(web.Topics, ","); got != "web" {
+		t.Errorf("web topics = %s, the repository override should replace the defaults", got)
+	}
+	if got := teamSlugs(web.Teams); got != "frontend,platform,security" {
+		t.Errorf("web teams = %s, want frontend,platform,security", got)
+	}
+}
+
+func TestMergeStrategiesValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "unknown strategy",
+			data:    "merge:\n  teams: replace\nrepositories:\n  - name: a\n",
+			wantErr: "unknown merge strategy",
+		},
+		{
+			name:    "unknown field",
+			data:    "merge:\n  labels: append\nrepositories:\n  - name: a\n",
+			wantErr: "unknown merge fields labels",
+		},
+		{
+			name:    "unknown repository field",
+			data:    "repositories:\n  - name: a\n    merge:\n      features: append\n",
+			wantErr: "unknown merge fields features",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMultiRepositoryConfig([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadMultiRepositoryConfig() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultConfigMerger_RemovalsWithoutDefaults(t *testing.T) {
+	repo := &RepositoryConfig{
+		Name: "repo",
+		Teams: []TeamAccess{
+			{TeamSlug: "backend", Permission: "write"},
+			{TeamSlug: "legacy", Remove: true},
+		},
+	}
+
+	merged, err := NewConfigMerger().MergeDefaults(nil, repo)
+	if err != nil {
+		t.Fatalf("MergeDefaults() error = %v", err)
+	}
+	if got := teamSlugs(merged.Teams); got != "backend" {
+		t.Errorf("MergeDefaults() teams = %s, want the removal marker dropped", got)
+	}
+}
+
+func TestDefaultConfigMerger_DeepMergeTopics(t *testing.T) {
+	merger := NewConfigMerger()
+	merger.SetMergeStrategy("topics", MergeStrategyDeepMerge)
+
+	merged, err := merger.MergeDefaults(&RepositoryDefaults{Topics: []string{"a", "b"}}, &RepositoryConfig{Name: "repo", Topics: []string{"b", "c"}})
+	if err != nil {
+		t.Fatalf("MergeDefaults() error = %v", err)
+	}
+	if got := strings.Join(merged.Topics, ","); got != "b,c,a" {
+		t.Errorf("MergeDefaults() topics = %s, want b,c,a", got)
+	}
+}
+
+func teamSlugs(teams []TeamAccess) string {
+	slugs := make([]string, 0, len(teams))
+	for _, team := range teams {
+		slugs = append(slugs, team.TeamSlug)
+	}
+	return strings.Join(slugs, ",")
+}
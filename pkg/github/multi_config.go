@@ -2,9 +2,11 @@ package github
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"reflect"
 	"runtime"
+	"sort"
 
 	"gopkg.in/yaml.v3"
 )
@@ -37,8 +39,23 @@ type MultiRepositoryConfig struct {
 	// Global defaults applied to all repositories
 	Defaults *RepositoryDefaults `yaml:"defaults,omitempty"`
 
+	// Named sets of settings that repositories opt into with profiles:
+	Profiles map[string]*RepositoryDefaults `yaml:"profiles,omitempty"`
+
+	// Per-field merge strategies, which repositories may override
+	Merge MergeStrategies `yaml:"merge,omitempty"`
+
+	// Variables available to ${name} references, which repositories may override
+	Vars map[string]string `yaml:"vars,omitempty"`
+
 	// List of repositories to manage
-	Repositories []RepositoryConfig `yaml:"repositories" validate:"required,min=1,dive"`
+	Repositories []RepositoryConfig `yaml:"repositories" validate:"dive"`
+
+	// Settings applied to every live repository matching a query
+	RepositorySets []RepositorySet `yaml:"repository_sets,omitempty"`
+
+	// positions records where the values were defined, when parsed from YAML
+	positions sourcePositions
 }
 
 // RepositoryDefaults defines default settings for all repositories
@@ -51,6 +68,10 @@ type RepositoryDefaults struct {
 	Collaborators []Collaborator         `yaml:"collaborators,omitempty" validate:"dive"`
 	Teams         []TeamAccess           `yaml:"teams,omitempty" validate:"dive"`
 	Webhooks      []Webhook              `yaml:"webhooks,omitempty" validate:"dive"`
+	Labels        map[string]string      `yaml:"labels,omitempty"`
+
+	// positions records where the values were defined, when parsed from YAML
+	positions sourcePositions
 }
 
 // ConfigDetector detects and loads appropriate configuration format
@@ -92,6 +113,15 @@ func (d *DefaultConfigDetector) DetectFormat(data []byte) (ConfigFormat, error)
 		return FormatMultiRepository, nil
 	}
 
+	if _, hasSets := raw["repository_sets"]; hasSets {
+		return FormatMultiRepository, nil
+	}
+
+	// Repositories list their profiles, only multi-repository files define them
+	if _, definesProfiles := raw["profiles"].(map[string]any); definesProfiles {
+		return FormatMultiRepository, nil
+	}
+
 	// Default to single repository format for backward compatibility
 	return FormatSingleRepository, nil
 }
@@ -103,10 +133,16 @@ func (d *DefaultConfigDetector) LoadSingleRepo(data []byte) (*RepositoryConfig,
 
 // LoadMultiRepo loads a multi-repository configuration
 func (d *DefaultConfigDetector) LoadMultiRepo(data []byte) (*MultiRepositoryConfig, error) {
+	return loadMultiRepositoryConfig(parseConfigSource(data))
+}
+
+// loadMultiRepositoryConfig decodes and validates a multi-repository configuration
+func loadMultiRepositoryConfig(source *configSource) (*MultiRepositoryConfig, error) {
 	var config MultiRepositoryConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	if err := yaml.Unmarshal(source.data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse multi-repository YAML: %w", err)
 	}
+	config.setPositions(source.positions)
 
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("multi-repository configuration validation failed: %w", err)
@@ -120,10 +156,14 @@ func (m *MultiRepositoryConfig) Validate() error {
 	var validationErrors ValidationErrors
 
 	// Validate that we have at least one repository
-	if len(m.Repositories) == 0 {
+	if len(m.Repositories) == 0 && len(m.RepositorySets) == 0 {
 		validationErrors.Add("repositories", "", "at least one repository must be defined")
 	}
 
+	if err := checkConfigVersion(m.Version); err != nil {
+		validationErrors.Add("version", m.Version, err.Error())
+	}
+
 	// Validate defaults if present
 	if m.Defaults != nil {
 		if err := m.validateDefaults(); err != nil {
@@ -131,6 +171,22 @@ func (m *MultiRepositoryConfig) Validate() error {
 		}
 	}
 
+	if err := m.Merge.validate(); err != nil {
+		validationErrors.Add("merge", "", err.Error())
+	}
+
+	// Validate profiles
+	profileNames := make([]string, 0, len(m.Profiles))
+	for name := range m.Profiles {
+		profileNames = append(profileNames, name)
+	}
+	sort.Strings(profileNames)
+	for _, name := range profileNames {
+		if err := validateRepositoryDefaults(m.Profiles[name]); err != nil {
+			validationErrors.Add("profiles."+name, "", err.Error())
+		}
+	}
+
 	// Check for duplicate repository names
 	repoNames := make(map[string]bool)
 	for i, repo := range m.Repositories {
@@ -144,13 +200,52 @@ func (m *MultiRepositoryConfig) Validate() error {
 		}
 		repoNames[repo.Name] = true
 
+		for _, profile := range repo.Profiles {
+			if _, ok := m.Profiles[profile]; !ok {
+				validationErrors.Add(fmt.Sprintf("repositories[%d].profiles", i), profile, "unknown profile")
+			}
+		}
+
 		// Validate each repository configuration
 		if err := repo.Validate(); err != nil {
+			validationErrors.addNested(fmt.Sprintf("repositories[%d]", i), repo.Name, err)
+		}
+
+		if err := m.checkVariables(&repo); err != nil {
 			validationErrors.Add(fmt.Sprintf("repositories[%d]", i), repo.Name, err.Error())
 		}
 	}
 
+	for i := range m.RepositorySets {
+		set := &m.RepositorySets[i]
+		field := fmt.Sprintf("repository_sets[%d]", i)
+
+		if err := set.Query.validate(); err != nil {
+			validationErrors.Add(field+".query", "", err.Error())
+		}
+
+		unknownProfile := false
+		for _, profile := range set.Profiles {
+			if _, ok := m.Profiles[profile]; !ok {
+				validationErrors.Add(field+".profiles", profile, "unknown profile")
+				unknownProfile = true
+			}
+		}
+
+		if err := validateRepositoryDefaults(set.Config); err != nil {
+			validationErrors.Add(field+".config", "", err.Error())
+		}
+
+		if !unknownProfile {
+			member := &RepositoryConfig{Name: set.label(i), Profiles: set.Profiles, setLayers: []*RepositoryDefaults{set.Config}}
+			if err := m.checkVariables(member); err != nil {
+				validationErrors.Add(field, set.Name, err.Error())
+			}
+		}
+	}
+
 	if validationErrors.HasErrors() {
+		validationErrors.locate(m.positions)
 		return &Error{
 			Type:      ErrorTypeValidation,
 			Message:   validationErrors.Error(),
@@ -164,7 +259,73 @@ func (m *MultiRepositoryConfig) Validate() error {
 
 // validateDefaults validates the defaults configuration
 func (m *MultiRepositoryConfig) validateDefaults() error {
-	defaults := m.Defaults
+	return validateRepositoryDefaults(m.Defaults)
+}
+
+// layersFor returns the defaults and the profiles referenced by repo, in the
+// order they are layered beneath the repository's own settings. Repositories
+// from repository sets start from their current settings and end with the
+// settings of their sets.
+func (m *MultiRepositoryConfig) layersFor(repo *RepositoryConfig) ([]*RepositoryDefaults, error) {
+	var layers []*RepositoryDefaults
+	if repo.discovered != nil {
+		// Repositories from repository sets keep their current settings unless configured
+		layers = append(layers, observedLayer(repo.discovered))
+	}
+	layers = append(layers, m.Defaults)
+	for _, name := range repo.Profiles {
+		profile, ok := m.Profiles[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q", name)
+		}
+		layers = append(layers, profile)
+	}
+	return append(layers, repo.setLayers...), nil
+}
+
+// mergerFor returns merger configured with the merge strategies of the
+// configuration, overridden by those of repo
+func (m *MultiRepositoryConfig) mergerFor(merger ConfigMerger, repo *RepositoryConfig) ConfigMerger {
+	defaultMerger, ok := merger.(*DefaultConfigMerger)
+	if !ok || (len(m.Merge) == 0 && len(repo.Merge) == 0) {
+		return merger
+	}
+	return defaultMerger.WithStrategies(m.Merge, repo.Merge)
+}
+
+// varsFor returns the variables of the configuration overridden by those of repo
+func (m *MultiRepositoryConfig) varsFor(repo *RepositoryConfig) map[string]string {
+	if len(repo.Vars) == 0 {
+		return m.Vars
+	}
+	vars := make(map[string]string, len(m.Vars)+len(repo.Vars))
+	maps.Copy(vars, m.Vars)
+	maps.Copy(vars, repo.Vars)
+	return vars
+}
+
+// checkVariables resolves the variables of repo once merged with its defaults
+// and profiles, so that undefined variables and unset environment variables
+// are reported when the configuration is loaded rather than when it is applied
+func (m *MultiRepositoryConfig) checkVariables(repo *RepositoryConfig) error {
+	layers, err := m.layersFor(repo)
+	if err != nil {
+		// Unknown profiles are reported separately
+		return nil
+	}
+	merged, err := m.mergerFor(NewConfigMerger(), repo).MergeLayers(layers, repo)
+	if err != nil {
+		return err
+	}
+	_, err = InterpolateRepository(merged, "", m.varsFor(repo))
+	return err
+}
+
+// validateRepositoryDefaults validates a set of defaults or a profile
+func validateRepositoryDefaults(defaults *RepositoryDefaults) error {
+	if defaults == nil {
+		return nil
+	}
 
 	// Validate description length
 	if len(defaults.Description) > 350 {
@@ -189,6 +350,9 @@ func (m *MultiRepositoryConfig) validateDefaults() error {
 		if rule.Pattern == "" {
 			return fmt.Errorf("default branch protection rule %d: pattern is required", i+1)
 		}
+		if rule.Remove {
+			continue
+		}
 		if rule.RequiredReviews < 0 || rule.RequiredReviews > 6 {
 			return fmt.Errorf("default branch protection rule %d: required reviews must be between 0 and 6", i+1)
 		}
@@ -199,9 +363,12 @@ func (m *MultiRepositoryConfig) validateDefaults() error {
 		if collab.Username == "" {
 			return fmt.Errorf("default collaborator %d: username is required", i+1)
 		}
-		if err := validateGitHubUsername(collab.Username); err != nil {
+		if err := validateGitHubUsername(collab.Username); err != nil && !hasVariables(collab.Username) {
 			return fmt.Errorf("default collaborator %d: %w", i+1, err)
 		}
+		if collab.Remove {
+			continue
+		}
 		if !isValidPermission(collab.Permission) {
 			return fmt.Errorf("default collaborator %d: permission must be one of: read, write, admin", i+1)
 		}
@@ -212,9 +379,12 @@ func (m *MultiRepositoryConfig) validateDefaults() error {
 		if team.TeamSlug == "" {
 			return fmt.Errorf("default team %d: team slug is required", i+1)
 		}
-		if err := validateGitHubTeamSlug(team.TeamSlug); err != nil {
+		if err := validateGitHubTeamSlug(team.TeamSlug); err != nil && !hasVariables(team.TeamSlug) {
 			return fmt.Errorf("default team %d: %w", i+1, err)
 		}
+		if team.Remove {
+			continue
+		}
 		if !isValidPermission(team.Permission) {
 			return fmt.Errorf("default team %d: permission must be one of: read, write, admin", i+1)
 		}
@@ -225,11 +395,14 @@ func (m *MultiRepositoryConfig) validateDefaults() error {
 		if webhook.URL == "" {
 			return fmt.Errorf("default webhook %d: URL is required", i+1)
 		}
+		if webhook.Remove {
+			continue
+		}
 		if len(webhook.Events) == 0 {
 			return fmt.Errorf("default webhook %d: at least one event is required", i+1)
 		}
 		for j, event := range webhook.Events {
-			if !isValidWebhookEvent(event) {
+			if !isValidWebhookEvent(event) && !hasVariables(event) {
 				return fmt.Errorf("default webhook %d, event %d: invalid event type '%s'", i+1, j+1, event)
 			}
 		}
@@ -244,6 +417,16 @@ func LoadMultiRepositoryConfig(data []byte) (*MultiRepositoryConfig, error) {
 	return detector.LoadMultiRepo(data)
 }
 
+// LoadMultiRepositoryConfigStrict loads multi-repository configuration from
+// YAML data, rejecting unknown and duplicate keys
+func LoadMultiRepositoryConfigStrict(data []byte) (*MultiRepositoryConfig, error) {
+	source := parseConfigSource(data)
+	if err := source.checkStrict(FormatMultiRepository); err != nil {
+		return nil, fmt.Errorf("multi-repository configuration validation failed: %w", err)
+	}
+	return loadMultiRepositoryConfig(source)
+}
+
 // LoadMultiRepositoryConfigFromFile loads multi-repository configuration from a file with memory optimization
 func LoadMultiRepositoryConfigFromFile(filename string) (*MultiRepositoryConfig, error) {
 	// Check file size to determine loading strategy
@@ -258,31 +441,32 @@ func LoadMultiRepositoryConfigFromFile(filename string) (*MultiRepositoryConfig,
 	}
 
 	// For smaller files, use the standard approach
-	data, err := os.ReadFile(filename)
+	source, err := readConfigFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
 	// Detect configuration format
-	detector := NewConfigDetector()
-	format, err := detector.DetectFormat(data)
+	format, err := source.detectFormat(LoadOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to detect config format: %w", err)
+		return nil, err
 	}
 
 	switch format {
 	case FormatMultiRepository:
-		return detector.LoadMultiRepo(data)
+		return loadMultiRepositoryConfig(source)
 	case FormatSingleRepository:
 		// Convert single repository config to multi-repository format
-		singleConfig, err := detector.LoadSingleRepo(data)
+		singleConfig, err := loadRepositoryConfig(source)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load single repository config: %w", err)
 		}
 
-		return &MultiRepositoryConfig{
+		multiConfig := &MultiRepositoryConfig{
 			Repositories: []RepositoryConfig{*singleConfig},
-		}, nil
+		}
+		multiConfig.setPositions(source.positions.prefixed("repositories[0]"))
+		return multiConfig, nil
 	default:
 		return nil, fmt.Errorf("unsupported config format: %s", format)
 	}
@@ -364,23 +548,36 @@ func (scp *StreamingConfigProcessor) ProcessConfig(config *MultiRepositoryConfig
 
 // LoadConfigFromFile loads either single or multi-repository configuration from a file
 func LoadConfigFromFile(filename string) (any, ConfigFormat, error) {
-	data, err := os.ReadFile(filename)
+	return loadConfigFromFile(filename, LoadOptions{})
+}
+
+// loadConfigFromFile loads either configuration format from a file according to options
+func loadConfigFromFile(filename string, options LoadOptions) (any, ConfigFormat, error) {
+	source, err := readConfigFile(filename)
 	if err != nil {
-		return nil, FormatSingleRepository, fmt.Errorf("failed to read config file: %w", err)
+		return nil, FormatSingleRepository, err
 	}
 
-	detector := NewConfigDetector()
-	format, err := detector.DetectFormat(data)
+	format, err := source.detectFormat(options)
 	if err != nil {
-		return nil, FormatSingleRepository, fmt.Errorf("failed to detect config format: %w", err)
+		return nil, format, err
 	}
 
 	switch format {
 	case FormatSingleRepository:
-		config, err := detector.LoadSingleRepo(data)
-		return config, format, err
+		config, err := loadRepositoryConfig(source)
+		if err != nil {
+			return nil, format, err
+		}
+		if len(config.Profiles) > 0 || config.hasRemovals() {
+			return nil, format, fmt.Errorf("repository %s uses profiles or !remove, which only apply to multi-repository configurations", config.Name)
+		}
+		if _, err := InterpolateRepository(config, "", config.Vars); err != nil {
+			return nil, format, fmt.Errorf("configuration validation failed: %w", err)
+		}
+		return config, format, nil
 	case FormatMultiRepository:
-		config, err := detector.LoadMultiRepo(data)
+		config, err := loadMultiRepositoryConfig(source)
 		return config, format, err
 	default:
 		return nil, format, fmt.Errorf("unsupported config format: %s", format)
@@ -399,6 +596,7 @@ const (
 // ConfigMerger merges global defaults with repository-specific settings
 type ConfigMerger interface {
 	MergeDefaults(defaults *RepositoryDefaults, repo *RepositoryConfig) (*RepositoryConfig, error)
+	MergeLayers(layers []*RepositoryDefaults, repo *RepositoryConfig) (*RepositoryConfig, error)
 	ValidateMergedConfig(merged *RepositoryConfig) error
 	SetMergeStrategy(field string, strategy MergeStrategy)
 }
@@ -431,12 +629,29 @@ func (m *DefaultConfigMerger) SetMergeStrategy(field string, strategy MergeStrat
 
 // MergeDefaults merges global defaults with repository-specific settings
 func (m *DefaultConfigMerger) MergeDefaults(defaults *RepositoryDefaults, repo *RepositoryConfig) (*RepositoryConfig, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("repository config cannot be nil")
+	}
+
+	// Items tagged !remove take no part in merging, they remove inherited items afterwards
+	trimmed := *repo
+	var removedRules, removedCollaborators, removedTeams, removedWebhooks map[string]bool
+	trimmed.BranchRules, removedRules = splitRemovals(repo.BranchRules)
+	trimmed.Collaborators, removedCollaborators = splitRemovals(repo.Collaborators)
+	trimmed.Teams, removedTeams = splitRemovals(repo.Teams)
+	trimmed.Webhooks, removedWebhooks = splitRemovals(repo.Webhooks)
+
 	if defaults == nil {
-		return m.deepCopyRepositoryConfig(repo)
+		merged, err := m.deepCopyRepositoryConfig(&trimmed)
+		if err != nil {
+			return nil, err
+		}
+		merged.positions = mergedPositions(nil, repo, merged)
+		return merged, nil
 	}
 
 	// Create a deep copy of the repository config to avoid modifying the original
-	merged, err := m.deepCopyRepositoryConfig(repo)
+	merged, err := m.deepCopyRepositoryConfig(&trimmed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to copy repository config: %w", err)
 	}
@@ -482,9 +697,90 @@ func (m *DefaultConfigMerger) MergeDefaults(defaults *RepositoryDefaults, repo *
 		return nil, fmt.Errorf("failed to merge webhooks: %w", err)
 	}
 
+	// Labels merge key by key, the repository's values winning
+	for key, value := range defaults.Labels {
+		if _, ok := merged.Labels[key]; !ok {
+			if merged.Labels == nil {
+				merged.Labels = make(map[string]string, len(defaults.Labels))
+			}
+			merged.Labels[key] = value
+		}
+	}
+
+	merged.BranchRules = dropRemoved(merged.BranchRules, removedRules)
+	merged.Collaborators = dropRemoved(merged.Collaborators, removedCollaborators)
+	merged.Teams = dropRemoved(merged.Teams, removedTeams)
+	merged.Webhooks = dropRemoved(merged.Webhooks, removedWebhooks)
+	merged.positions = mergedPositions(defaults, repo, merged)
+
 	return merged, nil
 }
 
+// MergeLayers merges several layers of defaults, such as the global defaults
+// followed by a repository's profiles, with repository-specific settings.
+// Later layers take precedence over earlier ones and the repository over all.
+func (m *DefaultConfigMerger) MergeLayers(layers []*RepositoryDefaults, repo *RepositoryConfig) (*RepositoryConfig, error) {
+	var combined *RepositoryDefaults
+	for _, layer := range layers {
+		var err error
+		combined, err = m.overlayDefaults(combined, layer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m.MergeDefaults(combined, repo)
+}
+
+// overlayDefaults layers overlay on top of base using the configured strategies
+func (m *DefaultConfigMerger) overlayDefaults(base, overlay *RepositoryDefaults) (*RepositoryDefaults, error) {
+	if overlay == nil {
+		return base, nil
+	}
+	if base == nil {
+		return overlay, nil
+	}
+
+	// Treat the overlay as a repository so the usual merge rules apply
+	layer := &RepositoryConfig{
+		Description:   overlay.Description,
+		Topics:        overlay.Topics,
+		BranchRules:   overlay.BranchRules,
+		Collaborators: overlay.Collaborators,
+		Teams:         overlay.Teams,
+		Webhooks:      overlay.Webhooks,
+		Labels:        overlay.Labels,
+		positions:     overlay.positions,
+	}
+	if overlay.Features != nil {
+		layer.Features = *overlay.Features
+	}
+
+	merged, err := m.MergeDefaults(base, layer)
+	if err != nil {
+		return nil, err
+	}
+
+	combined := &RepositoryDefaults{
+		Description:   merged.Description,
+		Private:       base.Private,
+		Topics:        merged.Topics,
+		BranchRules:   merged.BranchRules,
+		Collaborators: merged.Collaborators,
+		Teams:         merged.Teams,
+		Webhooks:      merged.Webhooks,
+		Labels:        merged.Labels,
+		positions:     merged.positions,
+	}
+	if overlay.Private != nil {
+		combined.Private = overlay.Private
+	}
+	if base.Features != nil || overlay.Features != nil {
+		combined.Features = &merged.Features
+	}
+
+	return combined, nil
+}
+
 // deepCopyRepositoryConfig creates a deep copy of a RepositoryConfig
 func (m *DefaultConfigMerger) deepCopyRepositoryConfig(repo *RepositoryConfig) (*RepositoryConfig, error) {
 	if repo == nil {
@@ -496,6 +792,7 @@ func (m *DefaultConfigMerger) deepCopyRepositoryConfig(repo *RepositoryConfig) (
 		Description: repo.Description,
 		Private:     repo.Private,
 		Features:    repo.Features,
+		Labels:      maps.Clone(repo.Labels),
 	}
 
 	// Deep copy slices to avoid shared references
@@ -513,6 +810,7 @@ func (m *DefaultConfigMerger) deepCopyRepositoryConfig(repo *RepositoryConfig) (
 				RequiredReviews:        rule.RequiredReviews,
 				DismissStaleReviews:    rule.DismissStaleReviews,
 				RequireCodeOwnerReview: rule.RequireCodeOwnerReview,
+				Remove:                 rule.Remove,
 			}
 			if rule.RequiredStatusChecks != nil {
 				merged.BranchRules[i].RequiredStatusChecks = make([]string, len(rule.RequiredStatusChecks))
@@ -543,6 +841,7 @@ func (m *DefaultConfigMerger) deepCopyRepositoryConfig(repo *RepositoryConfig) (
 				URL:    webhook.URL,
 				Secret: webhook.Secret,
 				Active: webhook.Active,
+				Remove: webhook.Remove,
 			}
 			if webhook.Events != nil {
 				merged.Webhooks[i].Events = make([]string, len(webhook.Events))
@@ -568,8 +867,9 @@ func (m *DefaultConfigMerger) mergeTopics(defaultTopics []string, repoTopics *[]
 			*repoTopics = make([]string, len(defaultTopics))
 			copy(*repoTopics, defaultTopics)
 		}
-	case MergeStrategyAppend:
-		// Append defaults to repository topics, avoiding duplicates
+	case MergeStrategyAppend, MergeStrategyDeepMerge:
+		// Append defaults to repository topics, avoiding duplicates; for topics,
+		// deep merge is the same as append
 		topicSet := make(map[string]bool)
 		for _, topic := range *repoTopics {
 			topicSet[topic] = true
@@ -580,9 +880,6 @@ func (m *DefaultConfigMerger) mergeTopics(defaultTopics []string, repoTopics *[]
 				topicSet[topic] = true
 			}
 		}
-	case MergeStrategyDeepMerge:
-		// For topics, deep merge is the same as append
-		return m.mergeTopics(defaultTopics, repoTopics)
 	}
 
 	return nil
@@ -673,6 +970,7 @@ func (m *DefaultConfigMerger) copyBranchRule(rule BranchProtectionRule) BranchPr
 		RequiredReviews:        rule.RequiredReviews,
 		DismissStaleReviews:    rule.DismissStaleReviews,
 		RequireCodeOwnerReview: rule.RequireCodeOwnerReview,
+		Remove:                 rule.Remove,
 	}
 	if rule.RequiredStatusChecks != nil {
 		copied.RequiredStatusChecks = make([]string, len(rule.RequiredStatusChecks))
@@ -881,6 +1179,7 @@ func (m *DefaultConfigMerger) copyWebhook(webhook Webhook) Webhook {
 		URL:    webhook.URL,
 		Secret: webhook.Secret,
 		Active: webhook.Active,
+		Remove: webhook.Remove,
 	}
 	if webhook.Events != nil {
 		copied.Events = make([]string, len(webhook.Events))
@@ -1213,3 +1213,106 @@ func TestMergeStrategy_EdgeCases(t *testing.T) {
 		t.Errorf("Webhooks length = %v, want 1", len(result.Webhooks))
 	}
 }
+
+func TestDefaultConfigMerger_MergeLayers(t *testing.T) {
+	defaults := &RepositoryDefaults{
+		Description: "Default description",
+		Private:     boolPtr(true),
+		Topics:      []string{"managed"},
+		Features:    &RepositoryFeatures{Issues: true},
+		Teams:       []TeamAccess{{TeamSlug: "platform", Permission: "read"}},
+	}
+	service := &RepositoryDefaults{
+		Description: "Service",
+		Topics:      []string{"service"},
+		Features:    &RepositoryFeatures{Wiki: true},
+	}
+	public := &RepositoryDefaults{
+		Private: boolPtr(false),
+		Teams:   []TeamAccess{{TeamSlug: "community", Permission: "write"}},
+	}
+
+	tests := []struct {
+		name            string
+		layers          []*RepositoryDefaults
+		repo            *RepositoryConfig
+		wantDescription string
+		wantPrivate     bool
+		wantTopics      string
+		wantTeams       string
+		wantFeatures    RepositoryFeatures
+	}{
+		{
+			name:            "defaults only",
+			layers:          []*RepositoryDefaults{defaults},
+			repo:            &RepositoryConfig{Name: "repo"},
+			wantDescription: "Default description",
+			wantPrivate:     true,
+			wantTopics:      "managed",
+			wantTeams:       "platform",
+			wantFeatures:    RepositoryFeatures{Issues: true},
+		},
+		{
+			name:            "profiles layered in order",
+			layers:          []*RepositoryDefaults{defaults, service, public},
+			repo:            &RepositoryConfig{Name: "repo"},
+			wantDescription: "Service",
+			wantPrivate:     false,
+			wantTopics:      "service",
+			wantTeams:       "community",
+			wantFeatures:    RepositoryFeatures{Issues: true, Wiki: true},
+		},
+		{
+			name:            "repository wins over profiles",
+			layers:          []*RepositoryDefaults{defaults, service},
+			repo:            &RepositoryConfig{Name: "repo", Description: "Own", Topics: []string{"own"}},
+			wantDescription: "Own",
+			wantPrivate:     true,
+			wantTopics:      "own",
+			wantTeams:       "platform",
+			wantFeatures:    RepositoryFeatures{Issues: true, Wiki: true},
+		},
+		{
+			name:            "nil layers",
+			layers:          []*RepositoryDefaults{nil, service},
+			repo:            &RepositoryConfig{Name: "repo"},
+			wantDescription: "Service",
+			wantTopics:      "service",
+			wantFeatures:    RepositoryFeatures{Wiki: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := NewConfigMerger().MergeLayers(tt.layers, tt.repo)
+			if err != nil {
+				t.Fatalf("MergeLayers() error = %v", err)
+			}
+
+			if merged.Description != tt.wantDescription {
+				t.Errorf("MergeLayers() description = %q, want %q", merged.Description, tt.wantDescription)
+			}
+			if merged.Private != tt.wantPrivate {
+				t.Errorf("MergeLayers() private = %v, want %v", merged.Private, tt.wantPrivate)
+			}
+			if got := strings.Join(merged.Topics, ","); got != tt.wantTopics {
+				t.Errorf("MergeLayers() topics = %s, want %s", got, tt.wantTopics)
+			}
+			var teams []string
+			for _, team := range merged.Teams {
+				teams = append(teams, team.TeamSlug)
+			}
+			if got := strings.Join(teams, ","); got != tt.wantTeams {
+				t.Errorf("MergeLayers() teams = %s, want %s", got, tt.wantTeams)
+			}
+			if merged.Features != tt.wantFeatures {
+				t.Errorf("MergeLayers() features = %+v, want %+v", merged.Features, tt.wantFeatures)
+			}
+		})
+	}
+
+	// Layering must not modify the layers themselves
+	if len(defaults.Topics) != 1 || defaults.Topics[0] != "managed" {
+		t.Errorf("MergeLayers() modified the defaults: %v", defaults.Topics)
+	}
+}
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -25,6 +26,10 @@ type MultiRepoValidationResult struct {
 	Invalid map[string]error                        `json:"invalid"`
 	Details map[string]*RepositoryValidationDetails `json:"details"`
 	Summary ValidationSummary                       `json:"summary"`
+
+	// Errors holds the errors of the configuration as a whole, such as
+	// duplicate repository names, which prevent validating repositories
+	Errors []ValidationError `json:"errors,omitempty"`
 }
 
 // ValidationSummary provides aggregate validation statistics
@@ -41,6 +46,21 @@ type RepositoryValidationDetails struct {
 	Errors         []ValidationError   `json:"errors"`
 	Warnings       []ValidationWarning `json:"warnings"`
 	ValidatedAt    string              `json:"validated_at"`
+
+	// Position is where the repository is defined, when parsed from YAML
+	Position *SourcePosition `json:"position,omitempty"`
+}
+
+// locate sets the position of the repository and of its errors and warnings
+// from the positions of its fields
+func (d *RepositoryValidationDetails) locate(positions sourcePositions) {
+	d.Position = positions.lookup("")
+	ValidationErrors(d.Errors).locate(positions)
+	for i := range d.Warnings {
+		if d.Warnings[i].Position == nil {
+			d.Warnings[i].Position = positions.lookup(d.Warnings[i].Field)
+		}
+	}
 }
 
 // ValidationWarning represents a non-critical validation issue
@@ -49,6 +69,9 @@ type ValidationWarning struct {
 	Value   string `json:"value,omitempty"`
 	Message string `json:"message"`
 	Code    string `json:"code,omitempty"`
+
+	// Position is where the field is defined, when the configuration was parsed from YAML
+	Position *SourcePosition `json:"position,omitempty"`
 }
 
 // Error implements the error interface for ValidationWarning
@@ -98,6 +121,12 @@ func (mr *multiReconciler) PlanAll(config *MultiRepositoryConfig, repoFilter []s
 		return nil, NewMultiRepoAuthError(fmt.Sprintf("Authentication failed before planning: %v", err))
 	}
 
+	// Add the repositories matched by repository sets
+	config, err := mr.expandRepositorySets(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand repository sets: %w", err)
+	}
+
 	// Validate repository filter
 	if err := mr.validateRepositoryFilter(config, repoFilter); err != nil {
 		return nil, NewMultiRepoValidationError(fmt.Sprintf("invalid repository filter: %v", err), nil)
@@ -107,11 +136,11 @@ func (mr *multiReconciler) PlanAll(config *MultiRepositoryConfig, repoFilter []s
 	var planErrors []string
 
 	// Get repositories to process based on filter
-	repositoriesToProcess := mr.getRepositoriesToProcess(config.Repositories, repoFilter)
+	repositoriesToProcess := mr.getRepositoriesToProcess(config, repoFilter)
 
 	for _, repoConfig := range repositoriesToProcess {
 		// Merge defaults with repository-specific configuration
-		mergedConfig, err := mr.merger.MergeDefaults(config.Defaults, &repoConfig)
+		mergedConfig, err := mr.mergeRepository(config, &repoConfig)
 		if err != nil {
 			planErrors = append(planErrors, fmt.Sprintf("repository %s: failed to merge defaults: %v", repoConfig.Name, err))
 			continue
@@ -185,7 +214,17 @@ func (mr *multiReconciler) ValidateAll(config *MultiRepositoryConfig, repoFilter
 	// First validate the multi-repository configuration structure itself
 	// We'll handle individual repository validation errors gracefully
 	if err := mr.validateMultiRepoStructure(config); err != nil {
-		return nil, NewMultiRepoValidationError(fmt.Sprintf("multi-repository configuration validation failed: %v", err), nil)
+		var validationErrors ValidationErrors
+		if errors.As(err, &validationErrors) {
+			result.Errors = validationErrors
+		}
+		return result, NewMultiRepoValidationError(fmt.Sprintf("multi-repository configuration validation failed: %v", err), nil)
+	}
+
+	// Add the repositories matched by repository sets
+	config, err := mr.expandRepositorySets(config)
+	if err != nil {
+		return result, fmt.Errorf("failed to expand repository sets: %w", err)
 	}
 
 	// Validate repository filter
@@ -194,10 +233,11 @@ func (mr *multiReconciler) ValidateAll(config *MultiRepositoryConfig, repoFilter
 	}
 
 	// Get repositories to process based on filter
-	repositoriesToProcess := mr.getRepositoriesToProcess(config.Repositories, repoFilter)
+	repositoriesToProcess := mr.getRepositoriesToProcess(config, repoFilter)
 	result.Summary.TotalRepositories = len(repositoriesToProcess)
 
 	// Validate each repository configuration with detailed error reporting
+	var merged []*RepositoryConfig
 	for _, repoConfig := range repositoriesToProcess {
 		validationDetails := &RepositoryValidationDetails{
 			RepositoryName: repoConfig.Name,
@@ -208,21 +248,24 @@ func (mr *multiReconciler) ValidateAll(config *MultiRepositoryConfig, repoFilter
 		result.Details[repoConfig.Name] = validationDetails
 
 		// Validate repository configuration before merging
-		if err := mr.validateRepositoryConfig(&repoConfig, validationDetails); err != nil {
+		err := mr.validateRepositoryConfig(&repoConfig, validationDetails)
+		validationDetails.locate(repoConfig.positions)
+		if err != nil {
 			result.Invalid[repoConfig.Name] = err
 			result.Summary.InvalidCount++
 			continue
 		}
 
 		// Merge defaults with repository-specific configuration
-		mergedConfig, err := mr.merger.MergeDefaults(config.Defaults, &repoConfig)
+		mergedConfig, err := mr.mergeRepository(config, &repoConfig)
 		if err != nil {
 			mergeErr := fmt.Errorf("failed to merge defaults: %w", err)
 			result.Invalid[repoConfig.Name] = mergeErr
 			result.Summary.InvalidCount++
 			validationDetails.Errors = append(validationDetails.Errors, ValidationError{
-				Field:   "configuration_merge",
-				Message: mergeErr.Error(),
+				Field:    "configuration_merge",
+				Message:  mergeErr.Error(),
+				Position: repoConfig.positions.lookup(""),
 			})
 			continue
 		}
@@ -233,79 +276,122 @@ func (mr *multiReconciler) ValidateAll(config *MultiRepositoryConfig, repoFilter
 			result.Invalid[repoConfig.Name] = mergedErr
 			result.Summary.InvalidCount++
 			validationDetails.Errors = append(validationDetails.Errors, ValidationError{
-				Field:   "merged_configuration",
-				Message: mergedErr.Error(),
+				Field:    "merged_configuration",
+				Message:  mergedErr.Error(),
+				Position: firstErrorPosition(err),
 			})
 			continue
 		}
 
 		// Perform comprehensive validation using the reconciler
-		if err := mr.validateRepositoryWithReconciler(mergedConfig, validationDetails); err != nil {
+		err = mr.validateRepositoryWithReconciler(mergedConfig, validationDetails)
+		ValidationErrors(validationDetails.Errors).locate(mergedConfig.positions)
+		if err != nil {
 			result.Invalid[repoConfig.Name] = err
 			result.Summary.InvalidCount++
-		} else {
-			result.Valid = append(result.Valid, repoConfig.Name)
-			result.Summary.ValidCount++
+			continue
 		}
+		merged = append(merged, mergedConfig)
+	}
 
-		// Add any warnings to the summary
-		if len(validationDetails.Warnings) > 0 {
-			result.Summary.WarningCount += len(validationDetails.Warnings)
-		}
+	// Check the users and teams of the valid repositories against GitHub
+	if err := mr.validateAccounts(merged, result); err != nil {
+		return result, err
+	}
+
+	for _, repoConfig := range repositoriesToProcess {
+		result.Summary.WarningCount += len(result.Details[repoConfig.Name].Warnings)
 	}
 
 	return result, nil
 }
 
-// validateRepositoryFilter validates that all repositories in the filter exist in the configuration
-func (mr *multiReconciler) validateRepositoryFilter(config *MultiRepositoryConfig, repoFilter []string) error {
-	if len(repoFilter) == 0 {
-		return nil // No filter means process all repositories
-	}
-
-	// Create a set of available repository names
-	availableRepos := make(map[string]bool)
-	for _, repo := range config.Repositories {
-		availableRepos[repo.Name] = true
+// firstErrorPosition returns the position of the first of the validation
+// errors wrapped by err, which are located in the configuration they refer to
+func firstErrorPosition(err error) *SourcePosition {
+	var validationErrors ValidationErrors
+	if errors.As(err, &validationErrors) && len(validationErrors) > 0 {
+		return validationErrors[0].Position
 	}
+	return nil
+}
 
-	// Check that all filtered repositories exist
-	var invalidRepos []string
-	for _, repoName := range repoFilter {
-		if !availableRepos[repoName] {
-			invalidRepos = append(invalidRepos, repoName)
+// validateAccounts checks that the users and teams of the merged
+// configurations exist when the client can look them up, recording which
+// repositories are valid. Users and teams are looked up once, in batches,
+// however many repositories share them.
+func (mr *multiReconciler) validateAccounts(merged []*RepositoryConfig, result *MultiRepoValidationResult) error {
+	var accounts *accountCache
+	if directory, ok := mr.client.(AccountDirectory); ok {
+		accounts = newAccountCache(directory)
+		if err := accounts.prefetch(merged, mr.owner); err != nil {
+			return fmt.Errorf("failed to validate users and teams: %w", err)
 		}
 	}
 
-	if len(invalidRepos) > 0 {
-		return fmt.Errorf("repositories not found in configuration: %s", strings.Join(invalidRepos, ", "))
+	for _, config := range merged {
+		if accounts != nil {
+			details := result.Details[config.Name]
+			validationErrors, warnings, err := accounts.check(config, mr.owner)
+			if err != nil {
+				return fmt.Errorf("failed to validate users and teams: %w", err)
+			}
+			// Merged indices differ from those in the file, so locate them before merging in
+			validationErrors.locate(config.positions)
+			for i := range warnings {
+				if warnings[i].Position == nil {
+					warnings[i].Position = config.positions.lookup(warnings[i].Field)
+				}
+			}
+			details.Warnings = append(details.Warnings, warnings...)
+			if validationErrors.HasErrors() {
+				details.Errors = append(details.Errors, validationErrors...)
+				result.Invalid[config.Name] = validationErrors
+				result.Summary.InvalidCount++
+				continue
+			}
+		}
+		result.Valid = append(result.Valid, config.Name)
+		result.Summary.ValidCount++
 	}
-
 	return nil
 }
 
-// getRepositoriesToProcess returns the list of repositories to process based on the filter
-func (mr *multiReconciler) getRepositoriesToProcess(allRepos []RepositoryConfig, repoFilter []string) []RepositoryConfig {
-	if len(repoFilter) == 0 {
-		// No filter, return all repositories
-		return allRepos
-	}
+// validateRepositoryFilter validates that the filter parses, that the
+// repositories it names exist in the configuration and that it selects some
+func (mr *multiReconciler) validateRepositoryFilter(config *MultiRepositoryConfig, repoFilter []string) error {
+	_, err := SelectRepositories(config, repoFilter)
+	return err
+}
 
-	// Create a set of repositories to include
-	includeSet := make(map[string]bool)
-	for _, repoName := range repoFilter {
-		includeSet[repoName] = true
+// mergeRepository layers the defaults and the profiles referenced by repo
+// beneath its own settings and resolves its variables
+func (mr *multiReconciler) mergeRepository(config *MultiRepositoryConfig, repo *RepositoryConfig) (*RepositoryConfig, error) {
+	layers, err := config.layersFor(repo)
+	if err != nil {
+		return nil, err
 	}
-
-	// Filter repositories
-	var filteredRepos []RepositoryConfig
-	for _, repo := range allRepos {
-		if includeSet[repo.Name] {
-			filteredRepos = append(filteredRepos, repo)
-		}
+	merged, err := config.mergerFor(mr.merger, repo).MergeLayers(layers, repo)
+	if err != nil {
+		return nil, err
+	}
+	interpolated, err := InterpolateRepository(merged, mr.owner, config.varsFor(repo))
+	if err != nil {
+		return nil, err
 	}
+	interpolated.discovered = repo.discovered
+	interpolated.positions = merged.positions
+	return interpolated, nil
+}
 
-	return filteredRepos
+// getRepositoriesToProcess returns the list of repositories to process based
+// on the filter, which validateRepositoryFilter has already checked
+func (mr *multiReconciler) getRepositoriesToProcess(config *MultiRepositoryConfig, repoFilter []string) []RepositoryConfig {
+	selected, err := SelectRepositories(config, repoFilter)
+	if err != nil {
+		return nil
+	}
+	return selected
 }
 
 // performAuthenticationCheck performs a quick authentication check before processing repositories
@@ -656,6 +742,8 @@ func (mr *multiReconciler) validateRepositoryConfig(repo *RepositoryConfig, deta
 				Value:   topic,
 				Message: "topic must be 50 characters or less",
 			})
+		} else if hasVariables(topic) {
+			continue
 		} else if err := validateGitHubTopic(topic); err != nil {
 			validationErrors = append(validationErrors, fmt.Sprintf("topic %d is invalid: %v", i+1, err))
 			details.Errors = append(details.Errors, ValidationError{
@@ -693,7 +781,7 @@ func (mr *multiReconciler) validateRepositoryConfig(repo *RepositoryConfig, deta
 				Field:   fmt.Sprintf("collaborators[%d].username", i),
 				Message: "username is required",
 			})
-		} else if err := validateGitHubUsername(collab.Username); err != nil {
+		} else if err := validateGitHubUsername(collab.Username); err != nil && !hasVariables(collab.Username) {
 			validationErrors = append(validationErrors, fmt.Sprintf("collaborator %d: %v", i+1, err))
 			details.Errors = append(details.Errors, ValidationError{
 				Field:   fmt.Sprintf("collaborators[%d].username", i),
@@ -701,7 +789,7 @@ func (mr *multiReconciler) validateRepositoryConfig(repo *RepositoryConfig, deta
 				Message: fmt.Sprintf("invalid username: %v", err),
 			})
 		}
-		if !isValidPermission(collab.Permission) {
+		if !collab.Remove && !isValidPermission(collab.Permission) {
 			validationErrors = append(validationErrors, fmt.Sprintf("collaborator %d: permission must be one of: read, write, admin", i+1))
 			details.Errors = append(details.Errors, ValidationError{
 				Field:   fmt.Sprintf("collaborators[%d].permission", i),
@@ -719,7 +807,7 @@ func (mr *multiReconciler) validateRepositoryConfig(repo *RepositoryConfig, deta
 				Field:   fmt.Sprintf("teams[%d].team", i),
 				Message: "team slug is required",
 			})
-		} else if err := validateGitHubTeamSlug(team.TeamSlug); err != nil {
+		} else if err := validateGitHubTeamSlug(team.TeamSlug); err != nil && !hasVariables(team.TeamSlug) {
 			validationErrors = append(validationErrors, fmt.Sprintf("team %d: %v", i+1, err))
 			details.Errors = append(details.Errors, ValidationError{
 				Field:   fmt.Sprintf("teams[%d].team", i),
@@ -727,7 +815,7 @@ func (mr *multiReconciler) validateRepositoryConfig(repo *RepositoryConfig, deta
 				Message: fmt.Sprintf("invalid team slug: %v", err),
 			})
 		}
-		if !isValidPermission(team.Permission) {
+		if !team.Remove && !isValidPermission(team.Permission) {
 			validationErrors = append(validationErrors, fmt.Sprintf("team %d: permission must be one of: read, write, admin", i+1))
 			details.Errors = append(details.Errors, ValidationError{
 				Field:   fmt.Sprintf("teams[%d].permission", i),
@@ -746,6 +834,9 @@ func (mr *multiReconciler) validateRepositoryConfig(repo *RepositoryConfig, deta
 				Message: "URL is required",
 			})
 		}
+		if webhook.Remove {
+			continue
+		}
 		if len(webhook.Events) == 0 {
 			validationErrors = append(validationErrors, fmt.Sprintf("webhook %d: at least one event is required", i+1))
 			details.Errors = append(details.Errors, ValidationError{
@@ -754,7 +845,7 @@ func (mr *multiReconciler) validateRepositoryConfig(repo *RepositoryConfig, deta
 			})
 		}
 		for j, event := range webhook.Events {
-			if !isValidWebhookEvent(event) {
+			if !isValidWebhookEvent(event) && !hasVariables(event) {
 				validationErrors = append(validationErrors, fmt.Sprintf("webhook %d, event %d: invalid event type '%s'", i+1, j+1, event))
 				details.Errors = append(details.Errors, ValidationError{
 					Field:   fmt.Sprintf("webhooks[%d].events[%d]", i, j),
@@ -847,7 +938,7 @@ func (mr *multiReconciler) addValidationWarnings(repo *RepositoryConfig, details
 
 	// Warn about webhooks without secrets
 	for i, webhook := range repo.Webhooks {
-		if webhook.Secret == "" {
+		if webhook.Secret == "" && !webhook.Remove {
 			details.Warnings = append(details.Warnings, ValidationWarning{
 				Field:   fmt.Sprintf("webhooks[%d].secret", i),
 				Message: "Webhook configured without a secret, which is less secure",
@@ -863,7 +954,7 @@ func (mr *multiReconciler) validateMultiRepoStructure(config *MultiRepositoryCon
 	var validationErrors ValidationErrors
 
 	// Validate that we have at least one repository
-	if len(config.Repositories) == 0 {
+	if len(config.Repositories) == 0 && len(config.RepositorySets) == 0 {
 		validationErrors.Add("repositories", "", "at least one repository must be defined")
 	}
 
@@ -889,6 +980,7 @@ func (mr *multiReconciler) validateMultiRepoStructure(config *MultiRepositoryCon
 	}
 
 	if validationErrors.HasErrors() {
+		validationErrors.locate(config.positions)
 		return &Error{
 			Type:      ErrorTypeValidation,
 			Message:   validationErrors.Error(),
@@ -447,6 +447,13 @@ func (m *PerformanceMockAPIClient) UpdateRepository(_, _ string, _ RepositoryCon
 	return nil
 }
 
+func (m *PerformanceMockAPIClient) ListOrganizationRepositories(_ string) ([]*Repository, error) {
+	if m.delay > 0 {
+		time.Sleep(m.delay)
+	}
+	return nil, nil
+}
+
 func (m *PerformanceMockAPIClient) GetBranchProtection(_, _, _ string) (*BranchProtection, error) {
 	if m.delay > 0 {
 		time.Sleep(m.delay)
@@ -2,6 +2,7 @@ package github
 
 import (
 	"errors"
+	"sort"
 	"strings"
 	"testing"
 )
@@ -63,6 +64,20 @@ func (m *mockAPIClient) UpdateRepository(_, _ string, _ RepositoryConfig) error
 	return nil
 }
 
+func (m *mockAPIClient) ListOrganizationRepositories(org string) ([]*Repository, error) {
+	if err, exists := m.errors["list/"+org]; exists {
+		return nil, err
+	}
+	var repos []*Repository
+	for key, repo := range m.repositories {
+		if strings.HasPrefix(key, org+"/") {
+			repos = append(repos, repo)
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+	return repos, nil
+}
+
 func (m *mockAPIClient) GetBranchProtection(owner, name, branch string) (*BranchProtection, error) {
 	key := owner + "/" + name
 	if branchMap, exists := m.branchProtections[key]; exists {
@@ -815,6 +830,35 @@ func TestMultiReconciler_ValidateAll_ValidConfiguration(t *testing.T) {
 	}
 }
 
+func TestMultiReconciler_ValidateAll_Removals(t *testing.T) {
+	client := newMockAPIClient()
+	reconciler := NewMultiReconciler(client, "test-owner")
+
+	config := &MultiRepositoryConfig{
+		Defaults: &RepositoryDefaults{
+			Teams:    []TeamAccess{{TeamSlug: "platform", Permission: "read"}},
+			Webhooks: []Webhook{{URL: "https://ci.example.com/hook", Events: []string{"push"}, Secret: "s"}},
+		},
+		Repositories: []RepositoryConfig{
+			{
+				Name:          "repo1",
+				Teams:         []TeamAccess{{TeamSlug: "platform", Remove: true}},
+				Collaborators: []Collaborator{{Username: "contractor", Remove: true}},
+				Webhooks:      []Webhook{{URL: "https://ci.example.com/hook", Remove: true}},
+			},
+		},
+	}
+
+	result, err := reconciler.ValidateAll(config, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Summary.ValidCount != 1 {
+		t.Errorf("Expected removals without permissions or events to be valid, got %v", result.Invalid)
+	}
+}
+
 func TestMultiReconciler_ValidateAll_FilteredRepositories(t *testing.T) {
 	client := newMockAPIClient()
 	reconciler := NewMultiReconciler(client, "test-owner")
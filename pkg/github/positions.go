@@ -0,0 +1,317 @@
+package github
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourcePosition is where a value is defined in a configuration file. File is
+// empty when the configuration was not loaded from a file.
+type SourcePosition struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// String returns the position as file:line:column, or line:column without a file
+func (p SourcePosition) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// sourcePositions maps the paths of configuration values, such as
+// repositories[0].collaborators[2].permission, to where they are defined.
+// The empty path is the document itself.
+type sourcePositions map[string]*SourcePosition
+
+// indexPositions returns the positions of node and the values beneath it.
+// Nodes listed in origins, merged in from include fragments, and the values
+// beneath them are attributed to the fragment they came from.
+func indexPositions(node *yaml.Node, file string, origins map[*yaml.Node]string) sourcePositions {
+	positions := make(sourcePositions)
+	var walk func(node *yaml.Node, path, file string)
+	walk = func(node *yaml.Node, path, file string) {
+		if origin, ok := origins[node]; ok {
+			file = origin
+		}
+		positions[path] = &SourcePosition{File: file, Line: node.Line, Column: node.Column}
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				walk(node.Content[i+1], joinFieldPath(path, node.Content[i].Value), file)
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				walk(item, fmt.Sprintf("%s[%d]", path, i), file)
+			}
+		}
+	}
+	walk(node, "", file)
+	return positions
+}
+
+// joinFieldPath returns the path of field within the value at path
+func joinFieldPath(path, field string) string {
+	switch {
+	case path == "":
+		return field
+	case field == "":
+		return path
+	}
+	return path + "." + field
+}
+
+// lookup returns the position of the value at path, or of its closest
+// enclosing value when it is not defined, as for a missing required field
+func (p sourcePositions) lookup(path string) *SourcePosition {
+	if p == nil {
+		return nil
+	}
+	for {
+		if position, ok := p[path]; ok {
+			return position
+		}
+		if path == "" {
+			return nil
+		}
+		path = parentFieldPath(path)
+	}
+}
+
+// parentFieldPath returns the path of the value enclosing the value at path
+func parentFieldPath(path string) string {
+	if strings.HasSuffix(path, "]") {
+		if i := strings.LastIndex(path, "["); i >= 0 {
+			return path[:i]
+		}
+	}
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// within returns the positions beneath the value at path, relative to it
+func (p sourcePositions) within(path string) sourcePositions {
+	if p == nil {
+		return nil
+	}
+	positions := make(sourcePositions)
+	for key, position := range p {
+		switch {
+		case key == path:
+			positions[""] = position
+		case strings.HasPrefix(key, path+".") || strings.HasPrefix(key, path+"["):
+			rest := strings.TrimPrefix(key[len(path):], ".")
+			positions[rest] = position
+		}
+	}
+	return positions
+}
+
+// prefixed returns the positions moved beneath path
+func (p sourcePositions) prefixed(path string) sourcePositions {
+	if p == nil {
+		return nil
+	}
+	positions := make(sourcePositions, len(p))
+	for key, position := range p {
+		switch {
+		case key == "":
+			positions[path] = position
+		case strings.HasPrefix(key, "["):
+			positions[path+key] = position
+		default:
+			positions[joinFieldPath(path, key)] = position
+		}
+	}
+	return positions
+}
+
+// shifted returns the positions with the indices of the items of the list
+// field incremented by offset, for lists appended to another
+func (p sourcePositions) shifted(field string, offset int) sourcePositions {
+	if p == nil || offset == 0 {
+		return p
+	}
+	positions := make(sourcePositions, len(p))
+	for key, position := range p {
+		rest, ok := strings.CutPrefix(key, field+"[")
+		end := strings.Index(rest, "]")
+		if !ok || end < 0 {
+			positions[key] = position
+			continue
+		}
+		index, err := strconv.Atoi(rest[:end])
+		if err != nil {
+			positions[key] = position
+			continue
+		}
+		positions[fmt.Sprintf("%s[%d]%s", field, index+offset, rest[end+1:])] = position
+	}
+	return positions
+}
+
+// merge adds the positions of other, which take precedence
+func (p sourcePositions) merge(other sourcePositions) sourcePositions {
+	if p == nil {
+		p = make(sourcePositions, len(other))
+	}
+	maps.Copy(p, other)
+	return p
+}
+
+// locate sets the position of the errors that have none from the paths of their fields
+func (e ValidationErrors) locate(positions sourcePositions) {
+	for i := range e {
+		if e[i].Position == nil {
+			e[i].Position = positions.lookup(e[i].Field)
+		}
+	}
+}
+
+// setPositions records where the values of the configuration are defined,
+// giving each repository the positions of its own fields
+func (m *MultiRepositoryConfig) setPositions(positions sourcePositions) {
+	m.positions = positions
+	if m.Defaults != nil {
+		m.Defaults.positions = positions.within("defaults")
+	}
+	for name, profile := range m.Profiles {
+		if profile != nil {
+			profile.positions = positions.within("profiles." + name)
+		}
+	}
+	for i := range m.Repositories {
+		m.Repositories[i].positions = positions.within(fmt.Sprintf("repositories[%d]", i))
+	}
+	for i := range m.RepositorySets {
+		if config := m.RepositorySets[i].Config; config != nil {
+			config.positions = positions.within(fmt.Sprintf("repository_sets[%d].config", i))
+		}
+	}
+}
+
+// mergedLists are the list fields whose items are merged by key, so that an
+// item's index in a merged configuration differs from its index where defined
+var mergedLists = []string{"topics", "branch_protection", "collaborators", "teams", "webhooks"}
+
+// mergedPositions returns where the values of merged, the result of merging
+// repo over defaults, are defined. Each list item is located where the item
+// it came from is defined, in repo or in defaults; items whose origin has no
+// known position, such as the current settings of a live repository, are
+// left unlocated rather than attributed to the enclosing list.
+func mergedPositions(defaults *RepositoryDefaults, repo, merged *RepositoryConfig) sourcePositions {
+	var base RepositoryDefaults
+	if defaults != nil {
+		base = *defaults
+	}
+	if base.positions == nil && repo.positions == nil {
+		return nil
+	}
+
+	positions := make(sourcePositions)
+	for _, source := range []sourcePositions{base.positions, repo.positions} {
+		for key, position := range source {
+			if !isMergedListItem(key) {
+				positions[key] = position
+			}
+		}
+	}
+
+	locateItems(positions, "topics", topicItems(merged.Topics),
+		itemSource[topicItem]{topicItems(repo.Topics), repo.positions},
+		itemSource[topicItem]{topicItems(base.Topics), base.positions})
+	locateItems(positions, "branch_protection", merged.BranchRules,
+		itemSource[BranchProtectionRule]{repo.BranchRules, repo.positions},
+		itemSource[BranchProtectionRule]{base.BranchRules, base.positions})
+	locateItems(positions, "collaborators", merged.Collaborators,
+		itemSource[Collaborator]{repo.Collaborators, repo.positions},
+		itemSource[Collaborator]{base.Collaborators, base.positions})
+	locateItems(positions, "teams", merged.Teams,
+		itemSource[TeamAccess]{repo.Teams, repo.positions},
+		itemSource[TeamAccess]{base.Teams, base.positions})
+	locateItems(positions, "webhooks", merged.Webhooks,
+		itemSource[Webhook]{repo.Webhooks, repo.positions},
+		itemSource[Webhook]{base.Webhooks, base.positions})
+	return positions
+}
+
+// isMergedListItem reports whether path is within an item of a merged list
+func isMergedListItem(path string) bool {
+	for _, field := range mergedLists {
+		if strings.HasPrefix(path, field+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// itemSource is a list merged into a configuration and where it is defined
+type itemSource[T removable] struct {
+	items     []T
+	positions sourcePositions
+}
+
+// locateItems adds the positions of the items of the merged list field,
+// taking each from the first source, in order of precedence, that defines an
+// item with the same key. Repeated keys are matched in order of occurrence.
+func locateItems[T removable](positions sourcePositions, field string, merged []T, sources ...itemSource[T]) {
+	used := make([]map[string]int, len(sources))
+	for i := range used {
+		used[i] = make(map[string]int)
+	}
+
+	for i, item := range merged {
+		path := fmt.Sprintf("%s[%d]", field, i)
+		// An explicit nil keeps lookup from falling back to the enclosing list
+		positions[path] = nil
+
+		key := item.mergeKey()
+		for s, source := range sources {
+			j := source.find(key, used[s][key])
+			if j < 0 {
+				continue
+			}
+			used[s][key]++
+			maps.Copy(positions, source.positions.within(fmt.Sprintf("%s[%d]", field, j)).prefixed(path))
+			break
+		}
+	}
+}
+
+// find returns the index of the nth item with key that does not remove an
+// inherited item, or -1 when there are not that many
+func (s itemSource[T]) find(key string, nth int) int {
+	for j, item := range s.items {
+		if item.removes() || item.mergeKey() != key {
+			continue
+		}
+		if nth == 0 {
+			return j
+		}
+		nth--
+	}
+	return -1
+}
+
+// topicItem is a topic, merged by its name
+type topicItem string
+
+func (t topicItem) mergeKey() string { return string(t) }
+func (t topicItem) removes() bool    { return false }
+
+func topicItems(topics []string) []topicItem {
+	items := make([]topicItem, len(topics))
+	for i, topic := range topics {
+		items[i] = topicItem(topic)
+	}
+	return items
+}
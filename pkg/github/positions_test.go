@@ -0,0 +1,282 @@
+package github
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// validationErrorsOf returns the validation errors carried by err
+func validationErrorsOf(t *testing.T, err error) ValidationErrors {
+	t.Helper()
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Fatalf("error %v does not carry validation errors", err)
+	}
+	return validationErrors
+}
+
+// findValidationError returns the validation error for field, failing the test without one
+func findValidationError(t *testing.T, validationErrors []ValidationError, field string) ValidationError {
+	t.Helper()
+	for _, validationErr := range validationErrors {
+		if validationErr.Field == field {
+			return validationErr
+		}
+	}
+	t.Fatalf("no validation error for %s in %v", field, validationErrors)
+	return ValidationError{}
+}
+
+func TestValidationErrorPositions_MultiRepository(t *testing.T) {
+	data := []byte(`repositories:
+  - name: api
+  - name: web
+    collaborators:
+      - username: alice
+        permission: write
+      - username: bob
+        permission: owner
+    webhooks:
+      - url: https://example.com/hook
+        events: [push, nonsense]
+`)
+
+	_, err := LoadMultiRepositoryConfig(data)
+	if err == nil {
+		t.Fatal("LoadMultiRepositoryConfig() expected an error")
+	}
+	validationErrors := validationErrorsOf(t, err)
+
+	tests := []struct {
+		field    string
+		value    string
+		position string
+	}{
+		{"repositories[1].collaborators[1].permission", "owner", "8:21"},
+		{"repositories[1].webhooks[0].events[1]", "nonsense", "11:24"},
+	}
+	for _, tt := range tests {
+		validationErr := findValidationError(t, validationErrors, tt.field)
+		if validationErr.Value != tt.value {
+			t.Errorf("%s value = %q, want %q", tt.field, validationErr.Value, tt.value)
+		}
+		if validationErr.Position == nil || validationErr.Position.String() != tt.position {
+			t.Errorf("%s position = %v, want %s", tt.field, validationErr.Position, tt.position)
+		}
+	}
+}
+
+func TestValidationErrorPositions_MissingField(t *testing.T) {
+	data := []byte(`name: api
+collaborators:
+  - permission: write
+`)
+
+	_, err := LoadRepositoryConfig(data)
+	if err == nil {
+		t.Fatal("LoadRepositoryConfig() expected an error")
+	}
+
+	validationErr := findValidationError(t, validationErrorsOf(t, err), "collaborators[0].username")
+	if validationErr.Position == nil || validationErr.Position.String() != "3:5" {
+		t.Errorf("position = %v, want the collaborator at 3:5", validationErr.Position)
+	}
+}
+
+func TestValidationErrorPositions_Files(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"a.yaml": `repositories:
+  - name: api
+  - name: web
+`,
+		"b.yaml": `repositories:
+  - name: worker
+    include: _fragments/topics.yaml
+  - name: scheduler
+    teams:
+      - team: ops
+        permission: owner
+`,
+		"_fragments/topics.yaml": `description: Shared
+topics:
+  - Not_Valid
+`,
+	})
+
+	_, _, err := LoadConfigFromPath(dir)
+	if err == nil {
+		t.Fatal("LoadConfigFromPath() expected an error")
+	}
+	validationErrors := validationErrorsOf(t, err)
+
+	tests := []struct {
+		field    string
+		position string
+	}{
+		{"repositories[2].topics[0]", filepath.Join(dir, "_fragments", "topics.yaml") + ":3:5"},
+		{"repositories[3].teams[0].permission", filepath.Join(dir, "b.yaml") + ":7:21"},
+	}
+	for _, tt := range tests {
+		validationErr := findValidationError(t, validationErrors, tt.field)
+		if validationErr.Position == nil || validationErr.Position.String() != tt.position {
+			t.Errorf("%s position = %v, want %s", tt.field, validationErr.Position, tt.position)
+		}
+	}
+}
+
+func TestMultiReconciler_ValidateAllPositions(t *testing.T) {
+	config, err := LoadMultiRepositoryConfig([]byte(`repositories:
+  - name: api
+    private: true
+  - name: web
+    webhooks:
+      - url: https://example.com/hook
+        events: [push]
+`))
+	if err != nil {
+		t.Fatalf("LoadMultiRepositoryConfig() error = %v", err)
+	}
+
+	reconciler := NewMultiReconciler(newMockAPIClient(), "test-owner")
+	result, err := reconciler.ValidateAll(config, nil)
+	if err != nil {
+		t.Fatalf("ValidateAll() error = %v", err)
+	}
+
+	details := result.Details["web"]
+	if details.Position == nil || details.Position.String() != "4:5" {
+		t.Errorf("web position = %v, want 4:5", details.Position)
+	}
+
+	for _, warning := range details.Warnings {
+		if warning.Field == "webhooks[0].secret" {
+			if warning.Position == nil || warning.Position.String() != "6:9" {
+				t.Errorf("webhook warning position = %v, want the webhook at 6:9", warning.Position)
+			}
+			return
+		}
+	}
+	t.Errorf("no warning for the webhook secret in %v", details.Warnings)
+}
+
+func TestMultiReconciler_ValidateAllMergedPositions(t *testing.T) {
+	config, err := LoadMultiRepositoryConfig([]byte(`vars:
+  u: "bad_user!"
+defaults:
+  collaborators:
+    - username: alice
+      permission: write
+profiles:
+  ops:
+    teams:
+      - team: platform
+        permission: write
+repositories:
+  - name: api
+    profiles: [ops]
+    collaborators:
+      - !remove alice
+      - username: "${u}"
+        permission: write
+`))
+	if err != nil {
+		t.Fatalf("LoadMultiRepositoryConfig() error = %v", err)
+	}
+
+	result, err := NewMultiReconciler(newMockAPIClient(), "test-owner").ValidateAll(config, nil)
+	if err != nil {
+		t.Fatalf("ValidateAll() error = %v", err)
+	}
+
+	// The removal shifts the interpolated username to collaborators[0]
+	merged := findValidationError(t, result.Details["api"].Errors, "merged_configuration")
+	if merged.Position == nil || merged.Position.Line != 17 {
+		t.Errorf("merged configuration error position = %v, want the username on line 17", merged.Position)
+	}
+}
+
+func TestMultiReconciler_ValidateAllInheritedAccountPositions(t *testing.T) {
+	config, err := LoadMultiRepositoryConfig([]byte(`defaults:
+  collaborators:
+    - username: dave
+      permission: read
+profiles:
+  ops:
+    teams:
+      - team: ghost
+        permission: read
+repositories:
+  - name: api
+    profiles: [ops]
+    merge:
+      collaborators: append
+    collaborators:
+      - username: bob
+        permission: write
+`))
+	if err != nil {
+		t.Fatalf("LoadMultiRepositoryConfig() error = %v", err)
+	}
+
+	client := &directoryAPIClient{mockAPIClient: newMockAPIClient(), fakeDirectory: newFakeDirectory()}
+	result, err := NewMultiReconciler(client, "acme").ValidateAll(config, nil)
+	if err != nil {
+		t.Fatalf("ValidateAll() error = %v", err)
+	}
+
+	errs := result.Details["api"].Errors
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{field: "collaborators[1].username", want: "3:17"}, // dave, appended from the defaults
+		{field: "teams[0].team", want: "8:15"},             // ghost, from the ops profile
+	}
+	for _, tt := range tests {
+		validationErr := findValidationError(t, errs, tt.field)
+		if validationErr.Position == nil || validationErr.Position.String() != tt.want {
+			t.Errorf("%s position = %v, want %s", tt.field, validationErr.Position, tt.want)
+		}
+	}
+}
+
+func TestMergedPositions_UnknownOrigin(t *testing.T) {
+	repo := &RepositoryConfig{
+		Name:      "api",
+		positions: sourcePositions{"": {Line: 1}, "collaborators": {Line: 2}},
+	}
+	observed := &RepositoryDefaults{Collaborators: []Collaborator{{Username: "alice", Permission: "read"}}}
+
+	merged, err := (&DefaultConfigMerger{}).MergeDefaults(observed, repo)
+	if err != nil {
+		t.Fatalf("MergeDefaults() error = %v", err)
+	}
+
+	if position := merged.positions.lookup("collaborators[0].username"); position != nil {
+		t.Errorf("inherited collaborator without a position located at %v, want unlocated", position)
+	}
+	if position := merged.positions.lookup("name"); position == nil || position.Line != 1 {
+		t.Errorf("name position = %v, want line 1", position)
+	}
+}
+
+func TestSourcePositions_Shifted(t *testing.T) {
+	positions := sourcePositions{
+		"repositories[0].name": {Line: 2},
+		"repositories[1]":      {Line: 3},
+		"defaults.private":     {Line: 5},
+	}
+
+	shifted := positions.shifted("repositories", 2)
+
+	for key, line := range map[string]int{"repositories[2].name": 2, "repositories[3]": 3, "defaults.private": 5} {
+		if position := shifted[key]; position == nil || position.Line != line {
+			t.Errorf("shifted[%s] = %v, want line %d", key, position, line)
+		}
+	}
+	if _, ok := shifted["repositories[0].name"]; ok {
+		t.Errorf("shifted positions kept repositories[0].name")
+	}
+}
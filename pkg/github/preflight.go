@@ -0,0 +1,345 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// RequiredAccess is the access an operation needs from the token applying it
+type RequiredAccess struct {
+	// Scopes are the OAuth scopes of classic tokens granting the access; any
+	// of them suffices
+	Scopes []string `json:"scopes"`
+
+	// Permission and Level are the permission of fine-grained and
+	// installation tokens granting the access, such as administration: write
+	Permission string `json:"permission"`
+	Level      string `json:"level"`
+
+	// Role is the role on the repository the user of the token needs, empty
+	// for operations outside an existing repository
+	Role string `json:"role,omitempty"`
+}
+
+// PreflightCheck is a planned operation checked against the access of the token
+type PreflightCheck struct {
+	Repository string         `json:"repository"`
+	Operation  string         `json:"operation"`
+	Required   RequiredAccess `json:"required"`
+
+	// Problem explains why the operation would fail, empty when the token
+	// allows it or its permissions are unknown
+	Problem string `json:"problem,omitempty"`
+}
+
+// PreflightReport lists the operations of reconciliation plans and whether
+// the token allows them, so that missing access is found before anything
+// is changed
+type PreflightReport struct {
+	TokenType TokenType        `json:"token_type"`
+	Checks    []PreflightCheck `json:"checks"`
+
+	// Unverified reports that the permission levels of the token are
+	// unknown, as GitHub does not list them for fine-grained and
+	// installation tokens, so that only permissions probe requests showed
+	// missing and repository roles were checked
+	Unverified bool `json:"unverified,omitempty"`
+}
+
+// Failures returns the checks of the operations that would fail
+func (r *PreflightReport) Failures() []PreflightCheck {
+	var failures []PreflightCheck
+	for _, check := range r.Checks {
+		if check.Problem != "" {
+			failures = append(failures, check)
+		}
+	}
+	return failures
+}
+
+// FailedRepositories returns the sorted names of the repositories with
+// operations that would fail
+func (r *PreflightReport) FailedRepositories() []string {
+	var names []string
+	for _, check := range r.Failures() {
+		if !slices.Contains(names, check.Repository) {
+			names = append(names, check.Repository)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RequiredPermissions returns the fine-grained permissions the operations
+// need, such as "administration: write", sorted
+func (r *PreflightReport) RequiredPermissions() []string {
+	levels := make(map[string]string)
+	for _, check := range r.Checks {
+		if tokenPermissionAtLeast(levels[check.Required.Permission], check.Required.Level) {
+			continue
+		}
+		levels[check.Required.Permission] = check.Required.Level
+	}
+
+	permissions := make([]string, 0, len(levels))
+	for _, permission := range slices.Sorted(maps.Keys(levels)) {
+		permissions = append(permissions, permission+": "+levels[permission])
+	}
+	return permissions
+}
+
+// Preflight checks the operations of the plans, by repository name, against
+// the scopes or permissions of token and the role of its user on each
+// repository
+func Preflight(plans map[string]*ReconciliationPlan, token *TokenInfo) *PreflightReport {
+	report := &PreflightReport{
+		TokenType:  token.Type,
+		Unverified: token.Type != TokenTypeClassic && token.Type != "" && len(token.Permissions) == 0,
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(plans)) {
+		plan := plans[name]
+		if plan == nil {
+			continue
+		}
+		for _, operation := range planOperations(plan) {
+			check := PreflightCheck{
+				Repository: name,
+				Operation:  operation.name,
+				Required:   operation.access,
+				Problem:    missingAccess(token, operation.access),
+			}
+			if check.Problem == "" {
+				check.Problem = missingRole(token, plan.current, operation.access.Role)
+			}
+			report.Checks = append(report.Checks, check)
+		}
+	}
+
+	return report
+}
+
+// PermissionProber finds the fine-grained permissions a token lacks.
+// Clients implementing it let preflight checks verify fine-grained and
+// installation tokens without their permissions being given.
+type PermissionProber interface {
+	// ProbePermissions returns the sorted permissions the operations of the
+	// plans, by repository name, need and the token does not have at all
+	ProbePermissions(owner string, plans map[string]*ReconciliationPlan) ([]string, error)
+}
+
+// permissionProbes are read-only requests, by permission, that GitHub
+// answers with the permissions they accept when the token lacks them
+var permissionProbes = map[string]string{
+	"administration": "repos/%s/%s/teams",
+	"webhooks":       "repos/%s/%s/hooks",
+}
+
+// ProbePermissions makes a read-only request needing each permission the
+// plans need on an existing repository. GitHub names the permissions a
+// request accepts in the X-Accepted-GitHub-Permissions header when it is
+// refused. Probes cannot tell read from write access, so only permissions
+// the token does not have at all are found.
+func (c *Client) ProbePermissions(owner string, plans map[string]*ReconciliationPlan) ([]string, error) {
+	repositories := make(map[string]string)
+	for _, name := range slices.Sorted(maps.Keys(plans)) {
+		plan := plans[name]
+		if plan == nil || plan.current == nil {
+			continue
+		}
+		for _, operation := range planOperations(plan) {
+			if _, ok := repositories[operation.access.Permission]; !ok {
+				repositories[operation.access.Permission] = name
+			}
+		}
+	}
+
+	var missing []string
+	for _, permission := range slices.Sorted(maps.Keys(repositories)) {
+		probe, ok := permissionProbes[permission]
+		if !ok {
+			continue
+		}
+		req, err := c.client.NewRequest(http.MethodGet, fmt.Sprintf(probe, owner, repositories[permission]), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.client.Do(c.ctx, req, nil)
+		if err == nil {
+			continue
+		}
+		var errResp *github.ErrorResponse
+		if !errors.As(err, &errResp) || resp == nil || resp.StatusCode != http.StatusForbidden {
+			return nil, WrapGitHubError(err, fmt.Sprintf("probing the %s permission", permission))
+		}
+		if acceptedPermission(resp.Header.Get("X-Accepted-GitHub-Permissions")) == permission {
+			missing = append(missing, permission)
+		}
+	}
+	return missing, nil
+}
+
+// acceptedPermission returns the permission named by an
+// X-Accepted-GitHub-Permissions header, such as "administration=read", or
+// "" when it accepts several. Alternatives are separated by commas and the
+// permissions an alternative needs together by semicolons.
+func acceptedPermission(header string) string {
+	if header == "" || strings.ContainsAny(header, ",;") {
+		return ""
+	}
+	permission, _, _ := strings.Cut(strings.TrimSpace(header), "=")
+	return permission
+}
+
+// plannedOperation is an API operation applying a change of a plan
+type plannedOperation struct {
+	name   string
+	access RequiredAccess
+}
+
+// planOperations returns the operations applying plan, each with the
+// access GitHub requires for it
+func planOperations(plan *ReconciliationPlan) []plannedOperation {
+	// Private repositories need the repo scope, public ones public_repo
+	private := true
+	switch {
+	case plan.current != nil:
+		private = plan.current.Private
+	case plan.Repository != nil && plan.Repository.After != nil:
+		private = plan.Repository.After.Private
+	}
+	repoScopes := []string{"repo"}
+	if !private {
+		repoScopes = append(repoScopes, "public_repo")
+	}
+	administration := RequiredAccess{Scopes: repoScopes, Permission: "administration", Level: "write", Role: "admin"}
+
+	var operations []plannedOperation
+	if change := plan.Repository; change != nil {
+		access := administration
+		switch change.Type {
+		case ChangeTypeCreate:
+			// Creating is not bound to a role on the repository
+			access.Role = ""
+		case ChangeTypeDelete:
+			access.Scopes = []string{"delete_repo"}
+		}
+		operations = append(operations, plannedOperation{fmt.Sprintf("%s repository", change.Type), access})
+	}
+
+	for _, change := range plan.BranchRules {
+		operations = append(operations, plannedOperation{fmt.Sprintf("%s branch protection for %s", change.Type, change.Branch), administration})
+	}
+
+	for _, change := range plan.Collaborators {
+		collaborator := change.After
+		if collaborator == nil {
+			collaborator = change.Before
+		}
+		operations = append(operations, plannedOperation{fmt.Sprintf("%s collaborator %s", change.Type, collaborator.Username), administration})
+	}
+
+	for _, change := range plan.Teams {
+		team := change.After
+		if team == nil {
+			team = change.Before
+		}
+		operations = append(operations, plannedOperation{fmt.Sprintf("%s team access for %s", change.Type, team.TeamSlug), administration})
+	}
+
+	for _, change := range plan.Webhooks {
+		webhook := change.After
+		if webhook == nil {
+			webhook = change.Before
+		}
+		// Removing a webhook needs admin:repo_hook, writing one write:repo_hook
+		hookScopes := append(slices.Clone(repoScopes), "admin:repo_hook")
+		if change.Type != ChangeTypeDelete {
+			hookScopes = append(hookScopes, "write:repo_hook")
+		}
+		operations = append(operations, plannedOperation{
+			fmt.Sprintf("%s webhook %s", change.Type, webhook.URL),
+			RequiredAccess{Scopes: hookScopes, Permission: "webhooks", Level: "write", Role: "admin"},
+		})
+	}
+
+	return operations
+}
+
+// tokenPermissionLevels orders the levels of fine-grained permissions
+var tokenPermissionLevels = []string{"read", "write", "admin"}
+
+// tokenPermissionAtLeast reports whether a permission level grants required
+func tokenPermissionAtLeast(level, required string) bool {
+	return slices.Index(tokenPermissionLevels, level) >= slices.Index(tokenPermissionLevels, required)
+}
+
+// missingAccess describes the required access the token lacks, or returns
+// "" when it has it or its permissions are unknown
+func missingAccess(token *TokenInfo, required RequiredAccess) string {
+	if token.Type == TokenTypeClassic || token.Type == "" {
+		for _, scope := range required.Scopes {
+			if slices.Contains(token.Scopes, scope) {
+				return ""
+			}
+		}
+		if len(required.Scopes) == 1 {
+			return fmt.Sprintf("token needs the %s scope", required.Scopes[0])
+		}
+		return fmt.Sprintf("token needs one of the scopes %s", strings.Join(required.Scopes, ", "))
+	}
+
+	if len(token.Permissions) == 0 {
+		if slices.Contains(token.MissingPermissions, required.Permission) {
+			return fmt.Sprintf("token does not have the %s permission", required.Permission)
+		}
+		return ""
+	}
+	level := token.Permissions[required.Permission]
+	switch {
+	case level == "":
+		return fmt.Sprintf("token needs the %s: %s permission", required.Permission, required.Level)
+	case !tokenPermissionAtLeast(level, required.Level):
+		return fmt.Sprintf("token needs the %s: %s permission, it has %s", required.Permission, required.Level, level)
+	}
+	return ""
+}
+
+// missingRole describes why the user of the token lacks role on repository,
+// or returns "" when it has it or it is unknown. Installation tokens act as
+// the app rather than a user, so the role of the installation is checked.
+func missingRole(token *TokenInfo, repository *Repository, role string) string {
+	if role == "" || repository == nil {
+		return ""
+	}
+	if token.Type == TokenTypeInstallation {
+		if token.Repositories == nil {
+			return ""
+		}
+		installationRole, ok := token.Repositories[repository.Name]
+		switch {
+		case !ok:
+			return "repository is not accessible to the installation"
+		case installationRole == "" || installationRole == role:
+			return ""
+		}
+		return fmt.Sprintf("needs the %s role on the repository, the installation has %s", role, installationRole)
+	}
+	if repository.ViewerPermission == "" {
+		return ""
+	}
+	if repository.ViewerPermission == role {
+		return ""
+	}
+	user := token.User
+	if user == "" {
+		user = "the authenticated user"
+	}
+	return fmt.Sprintf("needs the %s role on the repository, %s has %s", role, user, repository.ViewerPermission)
+}
@@ -0,0 +1,211 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// preflightPlans returns plans for a new public repository and an existing
+// private one with a change of each kind
+func preflightPlans(viewerPermission string) map[string]*ReconciliationPlan {
+	return map[string]*ReconciliationPlan{
+		"site": {
+			Repository: &RepositoryChange{Type: ChangeTypeCreate, After: &Repository{Name: "site"}},
+		},
+		"api": {
+			current:     &Repository{Name: "api", Private: true, ViewerPermission: viewerPermission},
+			BranchRules: []BranchRuleChange{{Type: ChangeTypeUpdate, Branch: "main"}},
+			Teams:       []TeamChange{{Type: ChangeTypeCreate, After: &TeamAccess{TeamSlug: "platform", Permission: "write"}}},
+			Webhooks:    []WebhookChange{{Type: ChangeTypeDelete, Before: &Webhook{URL: "https://example.com/hook"}}},
+		},
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	tests := []struct {
+		name             string
+		token            *TokenInfo
+		viewerPermission string
+		wantFailures     map[string]string // problem by repository and operation
+		wantUnverified   bool
+	}{
+		{
+			name:             "classic token with repo scope",
+			token:            &TokenInfo{Type: TokenTypeClassic, Scopes: []string{"repo"}},
+			viewerPermission: "admin",
+		},
+		{
+			name:  "classic token with public_repo scope",
+			token: &TokenInfo{Type: TokenTypeClassic, Scopes: []string{"public_repo", "admin:repo_hook"}},
+			wantFailures: map[string]string{
+				"api: update branch protection for main": "token needs the repo scope",
+				"api: create team access for platform":   "token needs the repo scope",
+			},
+		},
+		{
+			name:  "classic token without hook scopes",
+			token: &TokenInfo{Type: TokenTypeClassic, Scopes: []string{"read:org"}},
+			wantFailures: map[string]string{
+				"site: create repository":                      "token needs one of the scopes repo, public_repo",
+				"api: update branch protection for main":       "token needs the repo scope",
+				"api: create team access for platform":         "token needs the repo scope",
+				"api: delete webhook https://example.com/hook": "token needs one of the scopes repo, admin:repo_hook",
+			},
+		},
+		{
+			name:             "user without the admin role",
+			token:            &TokenInfo{Type: TokenTypeClassic, User: "octocat", Scopes: []string{"repo"}},
+			viewerPermission: "push",
+			wantFailures: map[string]string{
+				"api: update branch protection for main":       "needs the admin role on the repository, octocat has push",
+				"api: create team access for platform":         "needs the admin role on the repository, octocat has push",
+				"api: delete webhook https://example.com/hook": "needs the admin role on the repository, octocat has push",
+			},
+		},
+		{
+			name: "fine-grained token with given permissions",
+			token: &TokenInfo{Type: TokenTypeFineGrained, Permissions: map[string]string{
+				"administration": "write",
+				"webhooks":       "read",
+			}},
+			wantFailures: map[string]string{
+				"api: delete webhook https://example.com/hook": "token needs the webhooks: write permission, it has read",
+			},
+		},
+		{
+			name:             "installation token with unknown permissions",
+			token:            &TokenInfo{Type: TokenTypeInstallation},
+			viewerPermission: "pull",
+			wantUnverified:   true,
+		},
+		{
+			name:  "fine-grained token missing a probed permission",
+			token: &TokenInfo{Type: TokenTypeFineGrained, MissingPermissions: []string{"webhooks"}},
+			wantFailures: map[string]string{
+				"api: delete webhook https://example.com/hook": "token does not have the webhooks permission",
+			},
+			wantUnverified: true,
+		},
+		{
+			name:           "installation token with the admin role",
+			token:          &TokenInfo{Type: TokenTypeInstallation, Repositories: map[string]string{"api": "admin"}},
+			wantUnverified: true,
+		},
+		{
+			name:           "installation token without the admin role",
+			token:          &TokenInfo{Type: TokenTypeInstallation, Repositories: map[string]string{"api": "push"}},
+			wantUnverified: true,
+			wantFailures: map[string]string{
+				"api: update branch protection for main":       "needs the admin role on the repository, the installation has push",
+				"api: create team access for platform":         "needs the admin role on the repository, the installation has push",
+				"api: delete webhook https://example.com/hook": "needs the admin role on the repository, the installation has push",
+			},
+		},
+		{
+			name:           "installation token without access to the repository",
+			token:          &TokenInfo{Type: TokenTypeInstallation, Repositories: map[string]string{}},
+			wantUnverified: true,
+			wantFailures: map[string]string{
+				"api: update branch protection for main":       "repository is not accessible to the installation",
+				"api: create team access for platform":         "repository is not accessible to the installation",
+				"api: delete webhook https://example.com/hook": "repository is not accessible to the installation",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Preflight(preflightPlans(tt.viewerPermission), tt.token)
+
+			if len(report.Checks) != 4 {
+				t.Errorf("Preflight() checked %d operations, want 4", len(report.Checks))
+			}
+			if report.Unverified != tt.wantUnverified {
+				t.Errorf("Unverified = %v, want %v", report.Unverified, tt.wantUnverified)
+			}
+
+			failures := report.Failures()
+			if len(failures) != len(tt.wantFailures) {
+				t.Errorf("Failures() = %v, want %d", failures, len(tt.wantFailures))
+			}
+			for _, failure := range failures {
+				key := failure.Repository + ": " + failure.Operation
+				if want, ok := tt.wantFailures[key]; !ok || failure.Problem != want {
+					t.Errorf("failure %s = %q, want %q", key, failure.Problem, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPreflightReport_Summaries(t *testing.T) {
+	report := Preflight(preflightPlans("admin"), &TokenInfo{Type: TokenTypeFineGrained, Permissions: map[string]string{"metadata": "read"}})
+
+	if got := report.FailedRepositories(); !slices.Equal(got, []string{"api", "site"}) {
+		t.Errorf("FailedRepositories() = %v, want [api site]", got)
+	}
+	if got := report.RequiredPermissions(); !slices.Equal(got, []string{"administration: write", "webhooks: write"}) {
+		t.Errorf("RequiredPermissions() = %v", got)
+	}
+}
+
+func TestClient_ProbePermissions(t *testing.T) {
+	var probes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes = append(probes, r.URL.Path)
+		switch r.URL.Path {
+		case "/repos/acme/api/teams":
+			_, _ = w.Write([]byte(`[]`))
+		case "/repos/acme/api/hooks":
+			w.Header().Set("X-Accepted-GitHub-Permissions", "webhooks=read")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "Resource not accessible by personal access token"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL + "/")
+	ghClient := github.NewClient(nil)
+	ghClient.BaseURL = baseURL
+	client := &Client{client: ghClient, ctx: context.Background()}
+
+	missing, err := client.ProbePermissions("acme", preflightPlans("admin"))
+	if err != nil {
+		t.Fatalf("ProbePermissions() error = %v", err)
+	}
+	if !slices.Equal(missing, []string{"webhooks"}) {
+		t.Errorf("ProbePermissions() = %v, want [webhooks]", missing)
+	}
+	// New repositories cannot be probed
+	if !slices.Equal(probes, []string{"/repos/acme/api/teams", "/repos/acme/api/hooks"}) {
+		t.Errorf("probed %v, want the teams and hooks of api", probes)
+	}
+}
+
+func TestAcceptedPermission(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"administration=read", "administration"},
+		{" webhooks=write ", "webhooks"},
+		{"administration=read, webhooks=read", ""},
+		{"administration=read; metadata=read", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := acceptedPermission(tt.header); got != tt.want {
+			t.Errorf("acceptedPermission(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
@@ -43,6 +43,8 @@ func (r *reconciler) Plan(config RepositoryConfig) (*ReconciliationPlan, error)
 			},
 		}
 	} else {
+		plan.current = currentRepo
+
 		// Repository exists, check for changes
 		if repoChange := r.compareRepository(currentRepo, config); repoChange != nil {
 			plan.Repository = repoChange
@@ -51,6 +53,10 @@ func (r *reconciler) Plan(config RepositoryConfig) (*ReconciliationPlan, error)
 
 	// Only plan other changes if repository exists (not for new repositories)
 	if currentRepo != nil {
+		// Repositories matched by a repository set only manage the collaborators,
+		// teams and webhooks configured for them
+		discovered := config.discovered != nil
+
 		// Plan branch protection changes
 		branchChanges, err := r.planBranchProtectionChanges(config)
 		if err != nil {
@@ -59,25 +65,31 @@ func (r *reconciler) Plan(config RepositoryConfig) (*ReconciliationPlan, error)
 		plan.BranchRules = branchChanges
 
 		// Plan collaborator changes
-		collaboratorChanges, err := r.planCollaboratorChanges(config)
-		if err != nil {
-			return nil, fmt.Errorf("failed to plan collaborator changes: %w", err)
+		if !discovered || len(config.Collaborators) > 0 {
+			collaboratorChanges, err := r.planCollaboratorChanges(config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to plan collaborator changes: %w", err)
+			}
+			plan.Collaborators = collaboratorChanges
 		}
-		plan.Collaborators = collaboratorChanges
 
 		// Plan team access changes
-		teamChanges, err := r.planTeamChanges(config)
-		if err != nil {
-			return nil, fmt.Errorf("failed to plan team changes: %w", err)
+		if !discovered || len(config.Teams) > 0 {
+			teamChanges, err := r.planTeamChanges(config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to plan team changes: %w", err)
+			}
+			plan.Teams = teamChanges
 		}
-		plan.Teams = teamChanges
 
 		// Plan webhook changes
-		webhookChanges, err := r.planWebhookChanges(config)
-		if err != nil {
-			return nil, fmt.Errorf("failed to plan webhook changes: %w", err)
+		if !discovered || len(config.Webhooks) > 0 {
+			webhookChanges, err := r.planWebhookChanges(config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to plan webhook changes: %w", err)
+			}
+			plan.Webhooks = webhookChanges
 		}
-		plan.Webhooks = webhookChanges
 	} else if plan.Repository != nil && plan.Repository.Type == ChangeTypeCreate {
 		// For new repositories, plan to add all configured resources after creation
 		for _, rule := range config.BranchRules {
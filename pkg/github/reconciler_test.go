@@ -34,6 +34,14 @@ func (m *MockAPIClient) UpdateRepository(owner, name string, config RepositoryCo
 	return args.Error(0)
 }
 
+func (m *MockAPIClient) ListOrganizationRepositories(org string) ([]*Repository, error) {
+	args := m.Called(org)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Repository), args.Error(1)
+}
+
 func (m *MockAPIClient) GetBranchProtection(owner, name, branch string) (*BranchProtection, error) {
 	args := m.Called(owner, name, branch)
 	if args.Get(0) == nil {
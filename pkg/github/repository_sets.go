@@ -0,0 +1,165 @@
+package github
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RepositorySet applies settings to every live repository of the repository
+// owner matching a query, so that repositories created later are covered without
+// being listed in the configuration
+type RepositorySet struct {
+	// Name identifies the set in messages
+	Name string `yaml:"name,omitempty"`
+
+	// Query selects the repositories of the set
+	Query RepositoryQuery `yaml:"query"`
+
+	// Profiles names profiles layered, in order, over the global defaults
+	Profiles []string `yaml:"profiles,omitempty"`
+
+	// Config holds the settings of the set, layered over its profiles
+	Config *RepositoryDefaults `yaml:"config,omitempty"`
+}
+
+// RepositoryQuery selects live repositories of the repository owner. Empty
+// fields match everything.
+type RepositoryQuery struct {
+	Name       string `yaml:"name,omitempty"`       // regular expression matched against the name
+	Topic      string `yaml:"topic,omitempty"`      // topic or topic glob the repository must have
+	Visibility string `yaml:"visibility,omitempty"` // public or private
+	Archived   string `yaml:"archived,omitempty"`   // false (the default), true or any
+	Language   string `yaml:"language,omitempty"`   // primary language, case-insensitive
+}
+
+// label returns the name of the set for messages
+func (s *RepositorySet) label(index int) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("repository_sets[%d]", index)
+}
+
+// validate validates the query
+func (q *RepositoryQuery) validate() error {
+	if _, err := regexp.Compile(q.Name); err != nil {
+		return fmt.Errorf("invalid name pattern %q: %w", q.Name, err)
+	}
+	if _, err := path.Match(q.Topic, ""); err != nil {
+		return fmt.Errorf("invalid topic pattern %q: %w", q.Topic, err)
+	}
+	switch q.Visibility {
+	case "", "public", "private":
+	default:
+		return fmt.Errorf("invalid visibility %q: must be public or private", q.Visibility)
+	}
+	switch q.Archived {
+	case "", "false", "true", "any":
+	default:
+		return fmt.Errorf("invalid archived %q: must be true, false or any", q.Archived)
+	}
+	return nil
+}
+
+// matches reports whether repo is selected by the query, whose name pattern
+// has been compiled as name
+func (q *RepositoryQuery) matches(repo *Repository, name *regexp.Regexp) bool {
+	// Archived repositories are read-only, so only active ones match by default
+	if q.Archived != "any" && repo.Archived != (q.Archived == "true") {
+		return false
+	}
+	if !name.MatchString(repo.Name) {
+		return false
+	}
+	if q.Topic != "" && !slices.ContainsFunc(repo.Topics, func(topic string) bool { return globMatch(q.Topic, topic) }) {
+		return false
+	}
+	if q.Visibility != "" && repo.Private != (q.Visibility == "private") {
+		return false
+	}
+	if q.Language != "" && !strings.EqualFold(repo.Language, q.Language) {
+		return false
+	}
+	return true
+}
+
+// observedLayer returns the current repository-level settings of a repository,
+// which set members keep unless their layers configure them
+func observedLayer(repo *Repository) *RepositoryDefaults {
+	private := repo.Private
+	features := repo.Features
+	return &RepositoryDefaults{
+		Description: repo.Description,
+		Private:     &private,
+		Topics:      slices.Clone(repo.Topics),
+		Features:    &features,
+	}
+}
+
+// expandRepositorySets returns config with a repository added for each live
+// repository matched by its repository sets. Repositories listed explicitly
+// are left alone; a repository matched by several sets gets all of them, in
+// order.
+func (mr *multiReconciler) expandRepositorySets(config *MultiRepositoryConfig) (*MultiRepositoryConfig, error) {
+	if len(config.RepositorySets) == 0 {
+		return config, nil
+	}
+
+	explicit := make(map[string]bool, len(config.Repositories))
+	for _, repo := range config.Repositories {
+		explicit[repo.Name] = true
+	}
+
+	var repos []*Repository // repositories of the owner, listed once
+	members := make(map[string]int)
+	var discovered []RepositoryConfig
+
+	for i := range config.RepositorySets {
+		set := &config.RepositorySets[i]
+
+		if mr.owner == "" {
+			return nil, fmt.Errorf("repository set %s: an owner is required to list repositories", set.label(i))
+		}
+
+		if repos == nil {
+			var err error
+			repos, err = mr.client.ListOrganizationRepositories(mr.owner)
+			if err != nil {
+				return nil, fmt.Errorf("repository set %s: failed to list repositories: %w", set.label(i), err)
+			}
+		}
+
+		name, err := regexp.Compile(set.Query.Name)
+		if err != nil {
+			return nil, fmt.Errorf("repository set %s: %w", set.label(i), err)
+		}
+
+		for _, live := range repos {
+			if explicit[live.Name] || !set.Query.matches(live, name) {
+				continue
+			}
+
+			index, ok := members[live.Name]
+			if !ok {
+				discovered = append(discovered, RepositoryConfig{Name: live.Name, discovered: live})
+				index = len(discovered) - 1
+				members[live.Name] = index
+			}
+
+			member := &discovered[index]
+			for _, profile := range set.Profiles {
+				if !slices.Contains(member.Profiles, profile) {
+					member.Profiles = append(member.Profiles, profile)
+				}
+			}
+			member.setLayers = append(member.setLayers, set.Config)
+		}
+	}
+
+	expanded := *config
+	expanded.Repositories = append(slices.Clone(config.Repositories), discovered...)
+	return &expanded, nil
+}
@@ -0,0 +1,179 @@
+package github
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRepositorySetsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "invalid name pattern",
+			data:    "repository_sets:\n  - query:\n      name: \"svc-(\"\n",
+			wantErr: "invalid name pattern",
+		},
+		{
+			name:    "invalid visibility",
+			data:    "repository_sets:\n  - query:\n      visibility: internal\n",
+			wantErr: "invalid visibility",
+		},
+		{
+			name:    "invalid archived",
+			data:    "repository_sets:\n  - query:\n      archived: sometimes\n",
+			wantErr: "invalid archived",
+		},
+		{
+			name:    "unknown profile",
+			data:    "repository_sets:\n  - profiles: [missing]\n",
+			wantErr: "unknown profile",
+		},
+		{
+			name:    "invalid config",
+			data:    "repository_sets:\n  - config:\n      teams:\n        - team: backend\n          permission: owner\n",
+			wantErr: "repository_sets[0].config",
+		},
+		{
+			name:    "undefined variable",
+			data:    "repository_sets:\n  - config:\n      description: ${missing}\n",
+			wantErr: "undefined variable ${missing}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMultiRepositoryConfig([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadMultiRepositoryConfig() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	format, err := NewConfigDetector().DetectFormat([]byte("repository_sets:\n  - query: {}\n"))
+	if err != nil || format != FormatMultiRepository {
+		t.Errorf("DetectFormat() = %v, %v, want the multi-repository format", format, err)
+	}
+}
+
+func TestRepositoryQuery_Matches(t *testing.T) {
+	repo := &Repository{Name: "svc-payments", Topics: []string{"backend", "go"}, Private: true, Language: "Go"}
+
+	tests := []struct {
+		name  string
+		query RepositoryQuery
+		want  bool
+	}{
+		{name: "empty query", query: RepositoryQuery{}, want: true},
+		{name: "name pattern", query: RepositoryQuery{Name: "^svc-"}, want: true},
+		{name: "name mismatch", query: RepositoryQuery{Name: "^web-"}, want: false},
+		{name: "topic", query: RepositoryQuery{Topic: "back*"}, want: true},
+		{name: "missing topic", query: RepositoryQuery{Topic: "frontend"}, want: false},
+		{name: "visibility", query: RepositoryQuery{Visibility: "private"}, want: true},
+		{name: "other visibility", query: RepositoryQuery{Visibility: "public"}, want: false},
+		{name: "language", query: RepositoryQuery{Language: "go"}, want: true},
+		{name: "archived only", query: RepositoryQuery{Archived: "true"}, want: false},
+		{name: "active only", query: RepositoryQuery{Archived: "false"}, want: true},
+		{name: "archived or active", query: RepositoryQuery{Archived: "any"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.query.validate(); err != nil {
+				t.Fatalf("validate() error = %v", err)
+			}
+			got := tt.query.matches(repo, mustCompileQueryName(t, tt.query.Name))
+			if got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiReconciler_PlanAll_RepositorySets(t *testing.T) {
+	client := newMockAPIClient()
+	client.repositories["test-owner/api"] = &Repository{Name: "api", Description: "API"}
+	client.repositories["test-owner/svc-orders"] = &Repository{Name: "svc-orders", Description: "Orders", Private: true, Topics: []string{"backend"}}
+	client.repositories["test-owner/svc-old"] = &Repository{Name: "svc-old", Archived: true}
+	client.repositories["test-owner/web"] = &Repository{Name: "web"}
+	client.collaborators["test-owner/svc-orders"] = []Collaborator{{Username: "maintainer", Permission: "maintain"}}
+
+	data := `
+repositories:
+  - name: api
+    description: API
+repository_sets:
+  - name: services
+    query:
+      name: "^(svc-|api$)"
+    config:
+      branch_protection:
+        - pattern: main
+          required_reviews: 2
+      labels:
+        tier: critical
+`
+	config, err := LoadMultiRepositoryConfig([]byte(data))
+	if err != nil {
+		t.Fatalf("LoadMultiRepositoryConfig() error = %v", err)
+	}
+
+	reconciler := NewMultiReconciler(client, "test-owner")
+	plans, err := reconciler.PlanAll(config, nil)
+	if err != nil {
+		t.Fatalf("PlanAll() error = %v", err)
+	}
+
+	if len(plans) != 2 {
+		t.Fatalf("PlanAll() planned %d repositories, want api and svc-orders", len(plans))
+	}
+	if len(plans["api"].BranchRules) != 0 {
+		t.Errorf("api is listed explicitly, the set should not apply to it")
+	}
+
+	orders, ok := plans["svc-orders"]
+	if !ok {
+		t.Fatalf("PlanAll() has no plan for svc-orders")
+	}
+	if orders.Repository != nil {
+		t.Errorf("svc-orders repository change = %+v, want its current settings kept", orders.Repository)
+	}
+	if len(orders.BranchRules) != 1 || orders.BranchRules[0].Branch != "main" {
+		t.Errorf("svc-orders branch rules = %+v, want main protected", orders.BranchRules)
+	}
+	if len(orders.Collaborators) != 0 {
+		t.Errorf("svc-orders collaborators = %+v, the set does not manage collaborators", orders.Collaborators)
+	}
+
+	// Repositories from sets can be selected like any other
+	plans, err = reconciler.PlanAll(config, []string{"tier=critical"})
+	if err != nil {
+		t.Fatalf("PlanAll() with a selector error = %v", err)
+	}
+	if _, ok := plans["svc-orders"]; len(plans) != 1 || !ok {
+		t.Errorf("PlanAll() with a selector planned %d repositories, want only svc-orders", len(plans))
+	}
+}
+
+func TestMultiReconciler_PlanAll_RepositorySetWithoutOwner(t *testing.T) {
+	config := &MultiRepositoryConfig{
+		RepositorySets: []RepositorySet{{Name: "everything"}},
+	}
+
+	_, err := NewMultiReconciler(newMockAPIClient(), "").PlanAll(config, nil)
+	if err == nil || !strings.Contains(err.Error(), "an owner is required") {
+		t.Errorf("PlanAll() error = %v, want an error about the owner", err)
+	}
+}
+
+func mustCompileQueryName(t *testing.T, pattern string) *regexp.Regexp {
+	t.Helper()
+	name, err := regexp.Compile(pattern)
+	if err != nil {
+		t.Fatalf("regexp.Compile() error = %v", err)
+	}
+	return name
+}
@@ -0,0 +1,209 @@
+package github
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonSchemaDialect is the JSON Schema version of the generated schema
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	removableType     = reflect.TypeOf((*removable)(nil)).Elem()
+	mergeStrategyType = reflect.TypeOf(MergeStrategy(0))
+	strategiesType    = reflect.TypeOf(MergeStrategies(nil))
+)
+
+// schemaOverrides replace the generated schema of fields whose values are
+// restricted beyond their Go type, keyed by type and field name
+var schemaOverrides = map[string]func() map[string]any{
+	"Collaborator.Permission": permissionSchema,
+	"TeamAccess.Permission":   permissionSchema,
+	"RepositoryQuery.Archived": func() map[string]any {
+		return map[string]any{"enum": []any{true, false, "true", "false", "any"}}
+	},
+	"Webhook.Events": func() map[string]any {
+		return map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"anyOf": []any{
+					map[string]any{"type": "string", "enum": validWebhookEvents},
+					templatedSchema(),
+				},
+			},
+		}
+	},
+}
+
+func permissionSchema() map[string]any {
+	return map[string]any{"type": "string", "enum": validPermissions}
+}
+
+// templatedSchema matches values using ${...} variables, which are only
+// checked once resolved
+func templatedSchema() map[string]any {
+	return map[string]any{"type": "string", "pattern": `\$\{[^}]*\}`}
+}
+
+// GenerateJSONSchema returns a JSON Schema for configuration files, in either
+// the single-repository or the multi-repository format. It is generated from
+// the yaml and validate struct tags of RepositoryConfig, MultiRepositoryConfig
+// and RepositoryDefaults, with the permission levels and webhook events
+// accepted by validation as enums.
+func GenerateJSONSchema() ([]byte, error) {
+	generator := &schemaGenerator{defs: make(map[string]any)}
+	single := generator.ref(reflect.TypeOf(RepositoryConfig{}))
+	multi := generator.ref(reflect.TypeOf(MultiRepositoryConfig{}))
+
+	schema := map[string]any{
+		"$schema":     jsonSchemaDialect,
+		"title":       "synacklab GitHub repository configuration",
+		"description": "A single repository, or several repositories with shared defaults, managed by synacklab github apply",
+		"anyOf":       []any{single, multi},
+		"$defs":       generator.defs,
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaGenerator builds schemas for Go types, collecting structs as definitions
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+// ref returns a reference to the definition of a struct type, generating it
+// on first use
+func (g *schemaGenerator) ref(t reflect.Type) map[string]any {
+	if _, ok := g.defs[t.Name()]; !ok {
+		g.defs[t.Name()] = nil // reserved while the fields are generated
+		g.defs[t.Name()] = g.object(t)
+	}
+	return map[string]any{"$ref": "#/$defs/" + t.Name()}
+}
+
+// object returns the schema of a struct from the yaml tags of its fields
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		rules := field.Tag.Get("validate")
+		if override, ok := schemaOverrides[t.Name()+"."+field.Name]; ok {
+			properties[name] = override()
+		} else {
+			properties[name] = g.schema(field.Type, rules)
+		}
+
+		fieldRules, _, _ := strings.Cut(rules, "dive")
+		if hasRule(fieldRules, "required") {
+			required = append(required, name)
+		}
+	}
+
+	object := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
+
+// schema returns the schema of a value of type t, constrained by the rules of
+// a validate tag; rules after dive apply to the elements of slices and maps
+func (g *schemaGenerator) schema(t reflect.Type, rules string) map[string]any {
+	fieldRules, elemRules, _ := strings.Cut(rules, "dive")
+
+	var schema map[string]any
+	switch {
+	case t == mergeStrategyType:
+		if _, ok := g.defs[t.Name()]; !ok {
+			names := make([]string, 0, len(mergeStrategyNames))
+			for _, name := range mergeStrategyNames {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			g.defs[t.Name()] = map[string]any{"type": "string", "enum": names}
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	case t == strategiesType:
+		properties := make(map[string]any, len(mergeStrategyFields))
+		for field := range mergeStrategyFields {
+			properties[field] = g.schema(mergeStrategyType, "")
+		}
+		return map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem(), rules)
+	case reflect.String:
+		schema = map[string]any{"type": "string"}
+	case reflect.Bool:
+		schema = map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		schema = map[string]any{"type": "integer"}
+	case reflect.Slice:
+		schema = map[string]any{"type": "array", "items": g.schema(t.Elem(), elemRules)}
+	case reflect.Map:
+		schema = map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem(), elemRules)}
+	case reflect.Struct:
+		if t.Implements(removableType) {
+			// Tagged !remove, items may be given by their key alone
+			return map[string]any{"anyOf": []any{g.ref(t), map[string]any{"type": "string"}}}
+		}
+		return g.ref(t)
+	default:
+		return map[string]any{}
+	}
+
+	applyRules(schema, t.Kind(), fieldRules)
+	return schema
+}
+
+// applyRules adds the min and max rules of a validate tag to schema
+func applyRules(schema map[string]any, kind reflect.Kind, rules string) {
+	keywords := map[reflect.Kind][2]string{
+		reflect.String: {"minLength", "maxLength"},
+		reflect.Slice:  {"minItems", "maxItems"},
+		reflect.Map:    {"minProperties", "maxProperties"},
+	}
+	bounds, ok := keywords[kind]
+	if !ok {
+		bounds = [2]string{"minimum", "maximum"}
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(rule), "=")
+		n, err := strconv.Atoi(value)
+		if !found || err != nil {
+			continue
+		}
+		switch key {
+		case "min":
+			schema[bounds[0]] = n
+		case "max":
+			schema[bounds[1]] = n
+		}
+	}
+}
+
+// hasRule reports whether a validate tag contains rule
+func hasRule(rules, rule string) bool {
+	for _, r := range strings.Split(rules, ",") {
+		if strings.TrimSpace(r) == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package github
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestGenerateJSONSchema(t *testing.T) {
+	data, err := GenerateJSONSchema()
+	if err != nil {
+		t.Fatalf("GenerateJSONSchema() error = %v", err)
+	}
+
+	var schema struct {
+		Schema string                     `json:"$schema"`
+		AnyOf  []map[string]string        `json:"anyOf"`
+		Defs   map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("generated schema is not valid JSON: %v", err)
+	}
+
+	if schema.Schema != jsonSchemaDialect {
+		t.Errorf("$schema = %q, want %q", schema.Schema, jsonSchemaDialect)
+	}
+	if len(schema.AnyOf) != 2 || schema.AnyOf[0]["$ref"] != "#/$defs/RepositoryConfig" || schema.AnyOf[1]["$ref"] != "#/$defs/MultiRepositoryConfig" {
+		t.Errorf("anyOf = %v, want the single and multi-repository formats", schema.AnyOf)
+	}
+	for _, name := range []string{"RepositoryConfig", "MultiRepositoryConfig", "RepositoryDefaults", "RepositorySet", "Collaborator", "Webhook", "MergeStrategy"} {
+		if _, ok := schema.Defs[name]; !ok {
+			t.Errorf("$defs has no %s", name)
+		}
+	}
+
+	type property struct {
+		Type      string          `json:"type"`
+		Enum      []string        `json:"enum"`
+		MaxItems  int             `json:"maxItems"`
+		MaxLength int             `json:"maxLength"`
+		Items     json.RawMessage `json:"items"`
+		AnyOf     []property      `json:"anyOf"`
+	}
+	type object struct {
+		Properties           map[string]property `json:"properties"`
+		Required             []string            `json:"required"`
+		AdditionalProperties bool                `json:"additionalProperties"`
+	}
+	def := func(name string) object {
+		t.Helper()
+		var o object
+		if err := json.Unmarshal(schema.Defs[name], &o); err != nil {
+			t.Fatalf("$defs.%s: %v", name, err)
+		}
+		return o
+	}
+
+	repo := def("RepositoryConfig")
+	if !slices.Equal(repo.Required, []string{"name"}) || repo.AdditionalProperties {
+		t.Errorf("RepositoryConfig requires %v and additionalProperties = %v, want name and false", repo.Required, repo.AdditionalProperties)
+	}
+	if repo.Properties["description"].MaxLength != 350 {
+		t.Errorf("description maxLength = %d, want 350", repo.Properties["description"].MaxLength)
+	}
+	topics := repo.Properties["topics"]
+	var topic property
+	if err := json.Unmarshal(topics.Items, &topic); err != nil || topics.MaxItems != 20 || topic.MaxLength != 50 {
+		t.Errorf("topics = %+v, want at most 20 topics of at most 50 characters", topics)
+	}
+	for _, field := range []string{"labels", "vars", "profiles", "include", "merge"} {
+		if _, ok := repo.Properties[field]; !ok {
+			t.Errorf("RepositoryConfig has no %s property", field)
+		}
+	}
+	if len(repo.Properties["collaborators"].Items) == 0 {
+		t.Errorf("collaborators have no item schema")
+	}
+
+	if got := def("Collaborator").Properties["permission"].Enum; !slices.Equal(got, validPermissions) {
+		t.Errorf("collaborator permissions = %v, want %v", got, validPermissions)
+	}
+	if got := def("TeamAccess").Properties["permission"].Enum; !slices.Equal(got, validPermissions) {
+		t.Errorf("team permissions = %v, want %v", got, validPermissions)
+	}
+
+	var event property
+	if err := json.Unmarshal(def("Webhook").Properties["events"].Items, &event); err != nil || len(event.AnyOf) != 2 {
+		t.Fatalf("webhook events = %s, want an enum or a templated value", def("Webhook").Properties["events"].Items)
+	}
+	if !slices.Equal(event.AnyOf[0].Enum, validWebhookEvents) {
+		t.Errorf("webhook event enum = %v, want %v", event.AnyOf[0].Enum, validWebhookEvents)
+	}
+
+	var query struct {
+		Properties map[string]struct {
+			Enum []any `json:"enum"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(schema.Defs["RepositoryQuery"], &query); err != nil {
+		t.Fatalf("$defs.RepositoryQuery: %v", err)
+	}
+	if got := query.Properties["archived"].Enum; !slices.Contains(got, any(true)) || !slices.Contains(got, any("any")) {
+		t.Errorf("archived enum = %v, want booleans and any", got)
+	}
+	if _, ok := query.Properties["org"]; ok {
+		t.Errorf("RepositoryQuery has an org property, sets always target the owner")
+	}
+
+	multi := def("MultiRepositoryConfig")
+	if len(multi.Required) != 0 {
+		t.Errorf("MultiRepositoryConfig requires %v, repositories may be replaced by repository_sets", multi.Required)
+	}
+	if _, ok := multi.Properties["repository_sets"]; !ok {
+		t.Errorf("MultiRepositoryConfig has no repository_sets property")
+	}
+}
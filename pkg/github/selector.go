@@ -0,0 +1,207 @@
+package github
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// selectorKind is what a selector term matches against
+type selectorKind int
+
+const (
+	selectorName selectorKind = iota
+	selectorTopic
+	selectorLabel
+)
+
+// selectorTerm is a single term of a repository selector
+type selectorTerm struct {
+	kind    selectorKind
+	key     string
+	pattern string
+}
+
+// RepositorySelector picks repositories from a multi-repository configuration.
+// It is built from terms such as those given to --repos and --selector:
+//
+//	api, svc-*        repository names or globs; a repository must match one
+//	name=svc-*        the same, spelled as a predicate
+//	topic=backend     a topic the repository has (globs allowed)
+//	tier=critical     a label from the repository's labels: (globs allowed)
+//	!svc-legacy       negation: repositories matching the term are excluded
+//	tier!=critical    the same as !tier=critical
+//
+// Topic and label terms must all match. Topics and labels inherited from
+// defaults and profiles count.
+type RepositorySelector struct {
+	names    []selectorTerm
+	require  []selectorTerm
+	exclude  []selectorTerm
+	explicit []string
+}
+
+// ParseRepositorySelector parses selector terms, ignoring empty ones
+func ParseRepositorySelector(terms []string) (*RepositorySelector, error) {
+	selector := &RepositorySelector{}
+	for _, raw := range terms {
+		text := strings.TrimSpace(raw)
+		if text == "" {
+			continue
+		}
+
+		negate := strings.HasPrefix(text, "!")
+		text = strings.TrimPrefix(text, "!")
+
+		term := selectorTerm{kind: selectorName, pattern: text}
+		if key, value, found := strings.Cut(text, "="); found {
+			if strings.HasSuffix(key, "!") {
+				negate = !negate
+				key = strings.TrimSuffix(key, "!")
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if key == "" || value == "" {
+				return nil, fmt.Errorf("invalid selector %q: expected key=value", raw)
+			}
+
+			term = selectorTerm{kind: selectorLabel, key: key, pattern: value}
+			switch key {
+			case "name":
+				term.kind = selectorName
+			case "topic":
+				term.kind = selectorTopic
+			}
+		}
+
+		if term.pattern == "" {
+			return nil, fmt.Errorf("invalid selector %q", raw)
+		}
+		if _, err := path.Match(term.pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", raw, err)
+		}
+
+		switch {
+		case negate:
+			selector.exclude = append(selector.exclude, term)
+		case term.kind == selectorName:
+			selector.names = append(selector.names, term)
+			if !strings.ContainsAny(term.pattern, "*?[") {
+				selector.explicit = append(selector.explicit, term.pattern)
+			}
+		default:
+			selector.require = append(selector.require, term)
+		}
+	}
+	return selector, nil
+}
+
+// Empty reports whether the selector has no terms, and so selects everything
+func (s *RepositorySelector) Empty() bool {
+	return len(s.names) == 0 && len(s.require) == 0 && len(s.exclude) == 0
+}
+
+// Matches reports whether repo is selected
+func (s *RepositorySelector) Matches(repo *RepositoryConfig) bool {
+	if len(s.names) > 0 {
+		matched := false
+		for _, term := range s.names {
+			if term.matches(repo) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for _, term := range s.require {
+		if !term.matches(repo) {
+			return false
+		}
+	}
+
+	for _, term := range s.exclude {
+		if term.matches(repo) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t selectorTerm) matches(repo *RepositoryConfig) bool {
+	switch t.kind {
+	case selectorTopic:
+		for _, topic := range repo.Topics {
+			if globMatch(t.pattern, topic) {
+				return true
+			}
+		}
+		return false
+	case selectorLabel:
+		value, ok := repo.Labels[t.key]
+		return ok && globMatch(t.pattern, value)
+	default:
+		return globMatch(t.pattern, repo.Name)
+	}
+}
+
+// globMatch matches value against a shell pattern; patterns were checked when parsed
+func globMatch(pattern, value string) bool {
+	matched, _ := path.Match(pattern, value)
+	return matched
+}
+
+// SelectRepositories returns the repositories of config selected by terms, in
+// configuration order. Without terms every repository is returned. Repository
+// names given literally must exist, and a selector must match something.
+func SelectRepositories(config *MultiRepositoryConfig, terms []string) ([]RepositoryConfig, error) {
+	selector, err := ParseRepositorySelector(terms)
+	if err != nil {
+		return nil, err
+	}
+	if selector.Empty() {
+		return config.Repositories, nil
+	}
+
+	available := make(map[string]bool, len(config.Repositories))
+	for _, repo := range config.Repositories {
+		available[repo.Name] = true
+	}
+	var missing []string
+	for _, name := range selector.explicit {
+		if !available[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("repositories not found in configuration: %s", strings.Join(missing, ", "))
+	}
+
+	var selected []RepositoryConfig
+	for i := range config.Repositories {
+		if selector.Matches(config.effectiveRepository(&config.Repositories[i])) {
+			selected = append(selected, config.Repositories[i])
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no repositories match selector %q", strings.Join(terms, ","))
+	}
+
+	return selected, nil
+}
+
+// effectiveRepository returns repo merged with its defaults and profiles, or
+// repo itself when it cannot be merged
+func (m *MultiRepositoryConfig) effectiveRepository(repo *RepositoryConfig) *RepositoryConfig {
+	layers, err := m.layersFor(repo)
+	if err != nil {
+		return repo
+	}
+	merged, err := m.mergerFor(NewConfigMerger(), repo).MergeLayers(layers, repo)
+	if err != nil {
+		return repo
+	}
+	return merged
+}
@@ -0,0 +1,100 @@
+package github
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectRepositories(t *testing.T) {
+	data := `
+defaults:
+  topics: [managed]
+profiles:
+  critical:
+    labels:
+      tier: critical
+repositories:
+  - name: svc-api
+    topics: [backend]
+    profiles: [critical]
+  - name: svc-billing
+    topics: [backend]
+    labels:
+      tier: standard
+  - name: svc-legacy
+    topics: [backend, legacy]
+  - name: web
+    profiles: [critical]
+    labels:
+      team: web
+`
+	config, err := LoadMultiRepositoryConfig([]byte(data))
+	if err != nil {
+		t.Fatalf("LoadMultiRepositoryConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		terms   []string
+		want    string
+		wantErr string
+	}{
+		{name: "everything", terms: nil, want: "svc-api,svc-billing,svc-legacy,web"},
+		{name: "exact names", terms: []string{"web", "svc-api"}, want: "svc-api,web"},
+		{name: "glob", terms: []string{"svc-*"}, want: "svc-api,svc-billing,svc-legacy"},
+		{name: "name predicate", terms: []string{"name=*b*"}, want: "svc-billing,web"},
+		{name: "topic", terms: []string{"topic=backend"}, want: "svc-api,svc-billing,svc-legacy"},
+		{name: "topic from defaults", terms: []string{"topic=managed"}, want: "web"},
+		{name: "label from profile", terms: []string{"tier=critical"}, want: "svc-api,web"},
+		{name: "label glob", terms: []string{"tier=*"}, want: "svc-api,svc-billing,web"},
+		{name: "predicates are combined", terms: []string{"topic=backend", "tier=critical"}, want: "svc-api"},
+		{name: "names and predicates", terms: []string{"svc-*", "web", "tier=critical"}, want: "svc-api,web"},
+		{name: "negated name", terms: []string{"svc-*", "!svc-legacy"}, want: "svc-api,svc-billing"},
+		{name: "negation alone", terms: []string{"!topic=legacy"}, want: "svc-api,svc-billing,web"},
+		{name: "not equal", terms: []string{"topic=backend", "tier!=critical"}, want: "svc-billing,svc-legacy"},
+		{name: "empty terms ignored", terms: []string{" ", ""}, want: "svc-api,svc-billing,svc-legacy,web"},
+		{name: "missing name", terms: []string{"web", "missing"}, wantErr: "repositories not found in configuration: missing"},
+		{name: "nothing matches", terms: []string{"topic=mobile"}, wantErr: "no repositories match selector"},
+		{name: "empty value", terms: []string{"tier="}, wantErr: "expected key=value"},
+		{name: "bad glob", terms: []string{"svc-["}, wantErr: "invalid selector"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectRepositories(config, tt.terms)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("SelectRepositories() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectRepositories() error = %v", err)
+			}
+
+			names := make([]string, 0, len(got))
+			for _, repo := range got {
+				names = append(names, repo.Name)
+			}
+			if strings.Join(names, ",") != tt.want {
+				t.Errorf("SelectRepositories() = %s, want %s", strings.Join(names, ","), tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultConfigMerger_MergeLabels(t *testing.T) {
+	defaults := &RepositoryDefaults{Labels: map[string]string{"tier": "standard", "owner": "platform"}}
+	repo := &RepositoryConfig{Name: "repo", Labels: map[string]string{"tier": "critical"}}
+
+	merged, err := NewConfigMerger().MergeDefaults(defaults, repo)
+	if err != nil {
+		t.Fatalf("MergeDefaults() error = %v", err)
+	}
+	if merged.Labels["tier"] != "critical" || merged.Labels["owner"] != "platform" {
+		t.Errorf("MergeDefaults() labels = %v, want tier=critical and owner=platform", merged.Labels)
+	}
+	if len(repo.Labels) != 1 {
+		t.Errorf("MergeDefaults() modified the repository labels: %v", repo.Labels)
+	}
+}
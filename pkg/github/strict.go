@@ -0,0 +1,208 @@
+package github
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SupportedConfigVersion is the major version of the configuration format
+// understood by this release; any 1.x version is accepted
+const SupportedConfigVersion = 1
+
+// LoadOptions control how configuration files are decoded
+type LoadOptions struct {
+	// Strict rejects unknown and duplicate keys instead of ignoring them
+	Strict bool
+}
+
+// checkConfigVersion checks that a configuration version is supported
+func checkConfigVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return fmt.Errorf("invalid configuration version %q: expected a version such as \"%d.0\"", version, SupportedConfigVersion)
+	}
+	if n != SupportedConfigVersion {
+		return fmt.Errorf("unsupported configuration version %q: this release supports version %d.x", version, SupportedConfigVersion)
+	}
+	return nil
+}
+
+// detectFormat returns the format of the document. In strict mode keys that
+// decoding would ignore, because they are unknown to the format or defined
+// twice, are rejected.
+func (s *configSource) detectFormat(options LoadOptions) (ConfigFormat, error) {
+	if options.Strict {
+		// Duplicate keys would otherwise fail format detection without a position
+		if err := s.reject(func(errs *ValidationErrors) { checkDuplicateKeys(s.root, "", errs) }); err != nil {
+			return FormatSingleRepository, fmt.Errorf("configuration validation failed: %w", err)
+		}
+	}
+
+	format, err := NewConfigDetector().DetectFormat(s.data)
+	if err != nil {
+		return format, fmt.Errorf("failed to detect config format: %w", err)
+	}
+
+	if options.Strict {
+		if err := s.checkStrict(format); err != nil {
+			return format, fmt.Errorf("configuration validation failed: %w", err)
+		}
+	}
+	return format, nil
+}
+
+// checkStrict reports the keys of the document defined twice in the same
+// mapping or not matching a field of the configuration format
+func (s *configSource) checkStrict(format ConfigFormat) error {
+	configType := reflect.TypeOf(RepositoryConfig{})
+	if format == FormatMultiRepository {
+		configType = reflect.TypeOf(MultiRepositoryConfig{})
+	}
+
+	return s.reject(func(errs *ValidationErrors) {
+		checkDuplicateKeys(s.root, "", errs)
+		checkNodeFields(s.root, configType, "", errs)
+	})
+}
+
+// reject returns the validation errors found by check in the document, located
+func (s *configSource) reject(check func(*ValidationErrors)) error {
+	if s.root == nil {
+		return nil
+	}
+
+	var validationErrors ValidationErrors
+	check(&validationErrors)
+	if !validationErrors.HasErrors() {
+		return nil
+	}
+
+	validationErrors.locate(s.positions)
+	return &Error{
+		Type:      ErrorTypeValidation,
+		Message:   validationErrors.Error(),
+		Cause:     validationErrors,
+		Retryable: false,
+	}
+}
+
+// checkNodeFields checks the keys of node, at path, against the fields of t
+func checkNodeFields(node *yaml.Node, t reflect.Type, path string, validationErrors *ValidationErrors) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			checkNodeFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), validationErrors)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkNodeFields(node.Content[i+1], t.Elem(), joinFieldPath(path, node.Content[i].Value), validationErrors)
+		}
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if key == "<<" {
+				// YAML merge keys are resolved by the decoder
+				continue
+			}
+			field, ok := fields[key]
+			if !ok {
+				message := fmt.Sprintf("unknown field %q", key)
+				if suggestion := suggestField(key, fields); suggestion != "" {
+					message += fmt.Sprintf(", did you mean %q?", suggestion)
+				}
+				validationErrors.Add(joinFieldPath(path, key), key, message)
+				continue
+			}
+			checkNodeFields(node.Content[i+1], field.Type, joinFieldPath(path, key), validationErrors)
+		}
+	}
+	// Anything else is a scalar, a removal given by its key alone or a type
+	// mismatch, which decoding reports
+}
+
+// checkDuplicateKeys reports the keys defined more than once in the mappings
+// of node, at path
+func checkDuplicateKeys(node *yaml.Node, path string, validationErrors *ValidationErrors) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		seen := make(map[string]*yaml.Node)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if first, ok := seen[key.Value]; ok {
+				validationErrors.Add(joinFieldPath(path, key.Value), key.Value,
+					fmt.Sprintf("duplicate key %q, already defined at line %d", key.Value, first.Line))
+				continue
+			}
+			seen[key.Value] = key
+			checkDuplicateKeys(node.Content[i+1], joinFieldPath(path, key.Value), validationErrors)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			checkDuplicateKeys(item, fmt.Sprintf("%s[%d]", path, i), validationErrors)
+		}
+	}
+}
+
+// yamlFields returns the fields of a struct type by YAML key
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// suggestField returns the known field closest to an unknown key, or "" when
+// none is close enough to be a likely typo
+func suggestField(key string, fields map[string]reflect.StructField) string {
+	best, bestDistance := "", 0
+	for name := range fields {
+		distance := editDistance(key, name)
+		if best == "" || distance < bestDistance || (distance == bestDistance && name < best) {
+			best, bestDistance = name, distance
+		}
+	}
+	if best == "" || bestDistance > max(2, len(key)/3) {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,256 @@
+package github
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadRepositoryConfigStrict(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		field    string
+		message  string
+		position string
+	}{
+		{
+			name: "misspelled top-level field",
+			yaml: `name: api
+branch_protections:
+  - pattern: main
+`,
+			field:    "branch_protections",
+			message:  `unknown field "branch_protections", did you mean "branch_protection"?`,
+			position: "3:3",
+		},
+		{
+			name: "misspelled nested field",
+			yaml: `name: api
+branch_protection:
+  - pattern: main
+    requierd_reviews: 2
+`,
+			field:    "branch_protection[0].requierd_reviews",
+			message:  `unknown field "requierd_reviews", did you mean "required_reviews"?`,
+			position: "4:23",
+		},
+		{
+			name: "unknown field without a close match",
+			yaml: `name: api
+features:
+  security_and_analysis: true
+`,
+			field:    "features.security_and_analysis",
+			message:  `unknown field "security_and_analysis"`,
+			position: "3:26",
+		},
+		{
+			name: "field of a removed collaborator",
+			yaml: `name: api
+collaborators:
+  - !remove
+    username: alice
+    permision: read
+`,
+			field:   "collaborators[0].permision",
+			message: `unknown field "permision", did you mean "permission"?`,
+		},
+		{
+			name: "duplicate key",
+			yaml: `name: api
+private: true
+private: false
+`,
+			field:    "private",
+			message:  `duplicate key "private", already defined at line 2`,
+			position: "3:10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadRepositoryConfigStrict([]byte(tt.yaml))
+			if err == nil {
+				t.Fatal("LoadRepositoryConfigStrict() expected an error")
+			}
+
+			validationErr := findValidationError(t, validationErrorsOf(t, err), tt.field)
+			if validationErr.Message != tt.message {
+				t.Errorf("message = %q, want %q", validationErr.Message, tt.message)
+			}
+			if tt.position != "" && (validationErr.Position == nil || validationErr.Position.String() != tt.position) {
+				t.Errorf("position = %v, want %s", validationErr.Position, tt.position)
+			}
+		})
+	}
+}
+
+func TestLoadRepositoryConfigStrict_Valid(t *testing.T) {
+	data := []byte(`name: api
+description: API
+private: true
+topics: [go]
+features:
+  issues: true
+branch_protection:
+  - pattern: main
+    required_reviews: 2
+collaborators:
+  - username: alice
+    permission: write
+  - !remove bob
+labels:
+  tier: critical
+merge:
+  topics: append
+`)
+
+	if _, err := LoadRepositoryConfigStrict(data); err != nil {
+		t.Errorf("LoadRepositoryConfigStrict() error = %v", err)
+	}
+	// Lenient loading ignores unknown fields, as before
+	if _, err := LoadRepositoryConfig([]byte("name: api\nbranch_protections: []\n")); err != nil {
+		t.Errorf("LoadRepositoryConfig() error = %v", err)
+	}
+}
+
+func TestLoadMultiRepositoryConfigStrict(t *testing.T) {
+	data := []byte(`version: "1.0"
+defaults:
+  privat: true
+profiles:
+  service:
+    topics: [service]
+    team: []
+repositories:
+  - name: api
+    profiles: [service]
+repository_sets:
+  - query:
+      topics: backend
+`)
+
+	_, err := LoadMultiRepositoryConfigStrict(data)
+	if err == nil {
+		t.Fatal("LoadMultiRepositoryConfigStrict() expected an error")
+	}
+	validationErrors := validationErrorsOf(t, err)
+
+	for field, suggestion := range map[string]string{
+		"defaults.privat":                 "private",
+		"profiles.service.team":           "teams",
+		"repository_sets[0].query.topics": "topic",
+	} {
+		validationErr := findValidationError(t, validationErrors, field)
+		if !strings.Contains(validationErr.Message, `did you mean "`+suggestion+`"?`) {
+			t.Errorf("%s message = %q, want a suggestion of %s", field, validationErr.Message, suggestion)
+		}
+	}
+}
+
+func TestLoadConfigFromPathWithOptions_Strict(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"repos.yaml": `repositories:
+  - name: api
+    include: _fragments/common.yaml
+`,
+		"_fragments/common.yaml": `description: Shared
+topcs: [shared]
+`,
+	})
+
+	if _, _, err := LoadConfigFromPathWithOptions(dir, LoadOptions{}); err != nil {
+		t.Fatalf("LoadConfigFromPathWithOptions() lenient error = %v", err)
+	}
+
+	_, _, err := LoadConfigFromPathWithOptions(dir, LoadOptions{Strict: true})
+	if err == nil {
+		t.Fatal("LoadConfigFromPathWithOptions() strict expected an error")
+	}
+	validationErr := findValidationError(t, validationErrorsOf(t, err), "repositories[0].topcs")
+	want := filepath.Join(dir, "_fragments", "common.yaml") + ":2:8"
+	if validationErr.Position == nil || validationErr.Position.String() != want {
+		t.Errorf("position = %v, want %s", validationErr.Position, want)
+	}
+}
+
+func TestLoadConfigFromPathWithOptions_StrictDuplicateRepositoryKey(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"repos.yaml": `repositories:
+  - name: api
+    description: First
+    description: Second
+`,
+	})
+
+	_, _, err := LoadConfigFromPathWithOptions(filepath.Join(dir, "repos.yaml"), LoadOptions{Strict: true})
+	if err == nil {
+		t.Fatal("LoadConfigFromPathWithOptions() expected an error")
+	}
+	validationErr := findValidationError(t, validationErrorsOf(t, err), "repositories[0].description")
+	if validationErr.Message != `duplicate key "description", already defined at line 3` {
+		t.Errorf("message = %q", validationErr.Message)
+	}
+}
+
+func TestCheckConfigVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr string
+	}{
+		{"", ""},
+		{"1", ""},
+		{"1.0", ""},
+		{"1.3", ""},
+		{"2.0", `unsupported configuration version "2.0": this release supports version 1.x`},
+		{"latest", `invalid configuration version "latest"`},
+	}
+
+	for _, tt := range tests {
+		err := checkConfigVersion(tt.version)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("checkConfigVersion(%q) error = %v", tt.version, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("checkConfigVersion(%q) error = %v, want %q", tt.version, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMultiRepositoryConfig_ValidateVersion(t *testing.T) {
+	_, err := LoadMultiRepositoryConfig([]byte(`version: "2.0"
+repositories:
+  - name: api
+`))
+	if err == nil {
+		t.Fatal("LoadMultiRepositoryConfig() expected an error for version 2.0")
+	}
+	validationErr := findValidationError(t, validationErrorsOf(t, err), "version")
+	if validationErr.Position == nil || validationErr.Position.String() != "1:10" {
+		t.Errorf("position = %v, want 1:10", validationErr.Position)
+	}
+}
+
+func TestSuggestField(t *testing.T) {
+	fields := yamlFields(reflect.TypeOf(BranchProtectionRule{}))
+
+	tests := map[string]string{
+		"requierd_reviews":        "required_reviews",
+		"pattren":                 "pattern",
+		"dismiss_stale_review":    "dismiss_stale_reviews",
+		"enforce_admins":          "",
+		"required_status_checkss": "required_status_checks",
+	}
+	for key, want := range tests {
+		if got := suggestField(key, fields); got != want {
+			t.Errorf("suggestField(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -11,8 +11,14 @@ type Repository struct {
 	Private     bool               `json:"private"`
 	Topics      []string           `json:"topics"`
 	Features    RepositoryFeatures `json:"features"`
+	Archived    bool               `json:"archived"`
+	Language    string             `json:"language"`
 	CreatedAt   time.Time          `json:"created_at"`
 	UpdatedAt   time.Time          `json:"updated_at"`
+
+	// ViewerPermission is the role of the authenticated user on the
+	// repository: admin, maintain, push, triage or pull
+	ViewerPermission string `json:"viewer_permission,omitempty"`
 }
 
 // RepositoryFeatures represents repository feature settings
@@ -38,12 +44,14 @@ type BranchProtection struct {
 type Collaborator struct {
 	Username   string `json:"username" yaml:"username"`
 	Permission string `json:"permission" yaml:"permission"` // read, write, admin
+	Remove     bool   `json:"-" yaml:"-"`                   // tagged !remove in configuration
 }
 
 // TeamAccess represents team access to a repository
 type TeamAccess struct {
 	TeamSlug   string `json:"team_slug" yaml:"team"`
 	Permission string `json:"permission" yaml:"permission"` // read, write, admin
+	Remove     bool   `json:"-" yaml:"-"`                   // tagged !remove in configuration
 }
 
 // Webhook represents a repository webhook
@@ -53,4 +61,5 @@ type Webhook struct {
 	Events []string `json:"events" yaml:"events"`
 	Secret string   `json:"secret,omitempty" yaml:"secret,omitempty"`
 	Active bool     `json:"active" yaml:"active"`
+	Remove bool     `json:"-" yaml:"-"` // tagged !remove in configuration
 }
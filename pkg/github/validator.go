@@ -12,14 +12,18 @@ import (
 type Validator struct {
 	client *github.Client
 	ctx    context.Context
+
+	// accounts caches user and team lookups across the configurations validated
+	accounts *accountCache
 }
 
 // NewValidator creates a new validator with GitHub API access
 func NewValidator(token string) *Validator {
 	client := NewClient(token)
 	return &Validator{
-		client: client.client,
-		ctx:    client.ctx,
+		client:   client.client,
+		ctx:      client.ctx,
+		accounts: newAccountCache(client),
 	}
 }
 
@@ -30,46 +34,37 @@ func (v *Validator) ValidateConfig(config *RepositoryConfig, owner string) error
 		return err
 	}
 
-	// Then perform GitHub API validation
-	if err := v.validateCollaboratorsExist(config.Collaborators); err != nil {
+	// Then check the collaborators and teams against GitHub
+	validationErrors, _, err := v.ValidateAccounts(config, owner)
+	if err != nil {
 		return err
 	}
-
-	if err := v.validateTeamsExist(config.Teams, owner); err != nil {
-		return err
+	if validationErrors.HasErrors() {
+		validationErrors.locate(config.positions)
+		return &Error{
+			Type:      ErrorTypeValidation,
+			Message:   validationErrors.Error(),
+			Cause:     validationErrors,
+			Retryable: false,
+		}
 	}
 
 	return nil
 }
 
-// validateCollaboratorsExist checks if all collaborator usernames exist on GitHub
-func (v *Validator) validateCollaboratorsExist(collaborators []Collaborator) error {
-	for i, collab := range collaborators {
-		_, _, err := v.client.Users.Get(v.ctx, collab.Username)
-		if err != nil {
-			// Check if it's a 404 error (user not found)
-			if githubErr, ok := err.(*github.ErrorResponse); ok && githubErr.Response.StatusCode == http.StatusNotFound {
-				return fmt.Errorf("collaborator %d: user '%s' does not exist on GitHub", i+1, collab.Username)
-			}
-			return fmt.Errorf("collaborator %d: failed to validate user '%s': %w", i+1, collab.Username, err)
-		}
+// ValidateAccounts checks that the collaborators and teams of config exist,
+// and warns about collaborators who are not members of the owner organization
+// and team permissions its base permission already grants. Lookups are
+// batched and cached across calls.
+func (v *Validator) ValidateAccounts(config *RepositoryConfig, owner string) (ValidationErrors, []ValidationWarning, error) {
+	validationErrors, warnings, err := v.accounts.check(config, owner)
+	if err != nil {
+		return nil, nil, err
 	}
-	return nil
-}
-
-// validateTeamsExist checks if all team slugs exist in the organization
-func (v *Validator) validateTeamsExist(teams []TeamAccess, owner string) error {
-	for i, team := range teams {
-		_, _, err := v.client.Teams.GetTeamBySlug(v.ctx, owner, team.TeamSlug)
-		if err != nil {
-			// Check if it's a 404 error (team not found)
-			if githubErr, ok := err.(*github.ErrorResponse); ok && githubErr.Response.StatusCode == http.StatusNotFound {
-				return fmt.Errorf("team %d: team '%s' does not exist in organization '%s'", i+1, team.TeamSlug, owner)
-			}
-			return fmt.Errorf("team %d: failed to validate team '%s': %w", i+1, team.TeamSlug, err)
-		}
+	for i := range warnings {
+		warnings[i].Position = config.positions.lookup(warnings[i].Field)
 	}
-	return nil
+	return validationErrors, warnings, nil
 }
 
 // ValidatePermissions checks if the current token has the required permissions
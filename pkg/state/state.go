@@ -0,0 +1,304 @@
+// Package state persists picker history and favourites between synacklab runs.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Picker names used as keys in the state file
+const (
+	PickerAWSProfile    = "aws-profile"
+	PickerKubeContext   = "kube-context"
+	PickerKubeNamespace = "kube-namespace"
+)
+
+// maxEntries limits how many distinct values are remembered per picker
+const maxEntries = 200
+
+// Entry records how often and how recently a value was selected
+type Entry struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Picker holds the selection state of a single picker
+type Picker struct {
+	Current    string            `json:"current,omitempty"`
+	Previous   string            `json:"previous,omitempty"`
+	Favourites []string          `json:"favourites,omitempty"`
+	Entries    map[string]*Entry `json:"entries,omitempty"`
+}
+
+// Store is the on-disk selection state for all pickers
+type Store struct {
+	Pickers map[string]*Picker `json:"pickers"`
+
+	path string
+}
+
+// DefaultPath returns the default state file path
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".synacklab", "state.json"), nil
+}
+
+// New creates an empty store that saves to path
+func New(path string) *Store {
+	return &Store{
+		Pickers: make(map[string]*Picker),
+		path:    path,
+	}
+}
+
+// Load reads the store from path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	store := New(path)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return store, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return New(path), fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+
+	if store.Pickers == nil {
+		store.Pickers = make(map[string]*Picker)
+	}
+
+	return store, nil
+}
+
+// Save writes the store to disk, replacing the file atomically
+func (s *Store) Save() error {
+	if s.path == "" {
+		return fmt.Errorf("state store has no path")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(s.path), ".state-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmpFile.Name()) // Ignore cleanup errors after rename
+	}()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+
+	return nil
+}
+
+// picker returns the named picker, creating it if needed
+func (s *Store) picker(name string) *Picker {
+	p, ok := s.Pickers[name]
+	if !ok {
+		p = &Picker{}
+		s.Pickers[name] = p
+	}
+	if p.Entries == nil {
+		p.Entries = make(map[string]*Entry)
+	}
+	return p
+}
+
+// Record registers a selection and remembers the value it replaced
+func (s *Store) Record(pickerName, value string, now time.Time) {
+	p := s.picker(pickerName)
+
+	if p.Current != value {
+		p.Previous = p.Current
+		p.Current = value
+	}
+
+	entry, ok := p.Entries[value]
+	if !ok {
+		entry = &Entry{}
+		p.Entries[value] = entry
+	}
+	entry.Count++
+	entry.LastUsed = now
+
+	p.evict()
+}
+
+// Observe notes the value that is active right now without counting it as a
+// selection, so that a change made outside synacklab is still remembered as
+// the previous value on the next switch
+func (s *Store) Observe(pickerName, value string) {
+	if value == "" {
+		return
+	}
+	s.picker(pickerName).Current = value
+}
+
+// evict drops the least recently used entries beyond maxEntries
+func (p *Picker) evict() {
+	if len(p.Entries) <= maxEntries {
+		return
+	}
+
+	values := make([]string, 0, len(p.Entries))
+	for value := range p.Entries {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return p.Entries[values[i]].LastUsed.Before(p.Entries[values[j]].LastUsed)
+	})
+
+	for _, value := range values[:len(values)-maxEntries] {
+		delete(p.Entries, value)
+	}
+}
+
+// Previous returns the selection made before the current one
+func (s *Store) Previous(pickerName string) string {
+	if p, ok := s.Pickers[pickerName]; ok {
+		return p.Previous
+	}
+	return ""
+}
+
+// Pin marks a value as favourite. It returns false if it was already pinned.
+func (s *Store) Pin(pickerName, value string) bool {
+	if s.IsFavourite(pickerName, value) {
+		return false
+	}
+
+	p := s.picker(pickerName)
+	p.Favourites = append(p.Favourites, value)
+	return true
+}
+
+// Unpin removes a value from the favourites. It returns false if it was not pinned.
+func (s *Store) Unpin(pickerName, value string) bool {
+	p, ok := s.Pickers[pickerName]
+	if !ok {
+		return false
+	}
+
+	for i, favourite := range p.Favourites {
+		if favourite == value {
+			p.Favourites = append(p.Favourites[:i], p.Favourites[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// IsFavourite reports whether a value is pinned
+func (s *Store) IsFavourite(pickerName, value string) bool {
+	p, ok := s.Pickers[pickerName]
+	if !ok {
+		return false
+	}
+
+	for _, favourite := range p.Favourites {
+		if favourite == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Rank orders values for display: favourites first in pinned order, then
+// previously used values by frecency, then the remaining values unchanged
+func (s *Store) Rank(pickerName string, values []string, now time.Time) []string {
+	p, ok := s.Pickers[pickerName]
+	if !ok {
+		return append([]string(nil), values...)
+	}
+
+	available := make(map[string]bool, len(values))
+	for _, value := range values {
+		available[value] = true
+	}
+
+	ranked := make([]string, 0, len(values))
+	placed := make(map[string]bool, len(values))
+
+	for _, favourite := range p.Favourites {
+		if available[favourite] && !placed[favourite] {
+			ranked = append(ranked, favourite)
+			placed[favourite] = true
+		}
+	}
+
+	var used []string
+	for _, value := range values {
+		if _, ok := p.Entries[value]; ok && !placed[value] {
+			used = append(used, value)
+		}
+	}
+	sort.SliceStable(used, func(i, j int) bool {
+		a, b := p.Entries[used[i]], p.Entries[used[j]]
+		scoreA, scoreB := a.score(now), b.score(now)
+		if scoreA != scoreB {
+			return scoreA > scoreB
+		}
+		return a.LastUsed.After(b.LastUsed)
+	})
+	for _, value := range used {
+		ranked = append(ranked, value)
+		placed[value] = true
+	}
+
+	for _, value := range values {
+		if !placed[value] {
+			ranked = append(ranked, value)
+			placed[value] = true
+		}
+	}
+
+	return ranked
+}
+
+// score weights the selection count by how recently the value was used
+func (e *Entry) score(now time.Time) float64 {
+	age := now.Sub(e.LastUsed)
+
+	var weight float64
+	switch {
+	case age < time.Hour:
+		weight = 4
+	case age < 24*time.Hour:
+		weight = 2
+	case age < 7*24*time.Hour:
+		weight = 1
+	default:
+		weight = 0.25
+	}
+
+	return float64(e.Count) * weight
+}
@@ -0,0 +1,172 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	store, err := Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("Load should not fail for a missing file: %v", err)
+	}
+
+	if len(store.Pickers) != 0 {
+		t.Errorf("Expected empty store, got %d pickers", len(store.Pickers))
+	}
+}
+
+func TestLoadCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	store, err := Load(path)
+	if err == nil {
+		t.Error("Expected error for corrupt state file")
+	}
+	if store == nil || store.Pickers == nil {
+		t.Fatal("Load should return a usable empty store on error")
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store := New(path)
+	store.Record(PickerAWSProfile, "dev", now)
+	store.Record(PickerAWSProfile, "prod", now.Add(time.Minute))
+	store.Pin(PickerKubeContext, "prod-eks")
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := loaded.Previous(PickerAWSProfile); got != "dev" {
+		t.Errorf("Expected previous profile dev, got %q", got)
+	}
+	if !loaded.IsFavourite(PickerKubeContext, "prod-eks") {
+		t.Error("Expected prod-eks to be a favourite after reload")
+	}
+	if entry := loaded.Pickers[PickerAWSProfile].Entries["prod"]; entry == nil || entry.Count != 1 {
+		t.Errorf("Expected prod to be recorded once, got %+v", entry)
+	}
+}
+
+func TestRecordPrevious(t *testing.T) {
+	store := New("")
+	now := time.Now()
+
+	if got := store.Previous(PickerAWSProfile); got != "" {
+		t.Errorf("Expected no previous value, got %q", got)
+	}
+
+	store.Record(PickerAWSProfile, "a", now)
+	store.Record(PickerAWSProfile, "b", now)
+	if got := store.Previous(PickerAWSProfile); got != "a" {
+		t.Errorf("Expected previous a, got %q", got)
+	}
+
+	// Re-selecting the current value keeps the previous one
+	store.Record(PickerAWSProfile, "b", now)
+	if got := store.Previous(PickerAWSProfile); got != "a" {
+		t.Errorf("Expected previous a after reselect, got %q", got)
+	}
+
+	// Switching back swaps current and previous, like cd -
+	store.Record(PickerAWSProfile, "a", now)
+	if got := store.Previous(PickerAWSProfile); got != "b" {
+		t.Errorf("Expected previous b after switching back, got %q", got)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	store := New("")
+	now := time.Now()
+
+	store.Record(PickerKubeContext, "a", now)
+	store.Observe(PickerKubeContext, "changed-elsewhere")
+	store.Record(PickerKubeContext, "b", now)
+
+	if got := store.Previous(PickerKubeContext); got != "changed-elsewhere" {
+		t.Errorf("Expected previous changed-elsewhere, got %q", got)
+	}
+	if _, ok := store.Pickers[PickerKubeContext].Entries["changed-elsewhere"]; ok {
+		t.Error("Observed values should not be counted as selections")
+	}
+}
+
+func TestPinUnpin(t *testing.T) {
+	store := New("")
+
+	if !store.Pin(PickerAWSProfile, "prod") {
+		t.Error("First pin should report a change")
+	}
+	if store.Pin(PickerAWSProfile, "prod") {
+		t.Error("Pinning twice should not report a change")
+	}
+	if !store.Unpin(PickerAWSProfile, "prod") {
+		t.Error("Unpin should report a change")
+	}
+	if store.Unpin(PickerAWSProfile, "prod") {
+		t.Error("Unpinning twice should not report a change")
+	}
+	if store.IsFavourite(PickerAWSProfile, "prod") {
+		t.Error("prod should no longer be a favourite")
+	}
+}
+
+func TestRank(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	store := New("")
+
+	// "old" was used often but long ago, "recent" once just now
+	for i := 0; i < 3; i++ {
+		store.Record(PickerAWSProfile, "old", now.Add(-30*24*time.Hour))
+	}
+	store.Record(PickerAWSProfile, "recent", now.Add(-time.Minute))
+	store.Record(PickerAWSProfile, "daily", now.Add(-2*time.Hour))
+	store.Record(PickerAWSProfile, "daily", now.Add(-3*time.Hour))
+	store.Pin(PickerAWSProfile, "pinned")
+	store.Pin(PickerAWSProfile, "removed")
+
+	values := []string{"alpha", "daily", "old", "pinned", "recent", "zulu"}
+	got := store.Rank(PickerAWSProfile, values, now)
+	expected := []string{"pinned", "recent", "daily", "old", "alpha", "zulu"}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Rank() = %v, want %v", got, expected)
+	}
+
+	unknown := store.Rank(PickerKubeContext, values, now)
+	if !reflect.DeepEqual(unknown, values) {
+		t.Errorf("Rank() for unused picker = %v, want %v", unknown, values)
+	}
+}
+
+func TestEvict(t *testing.T) {
+	store := New("")
+	now := time.Now()
+
+	for i := 0; i < maxEntries+10; i++ {
+		store.Record(PickerKubeContext, time.Duration(i).String(), now.Add(time.Duration(i)*time.Second))
+	}
+
+	entries := store.Pickers[PickerKubeContext].Entries
+	if len(entries) != maxEntries {
+		t.Errorf("Expected %d entries after eviction, got %d", maxEntries, len(entries))
+	}
+	if _, ok := entries[time.Duration(0).String()]; ok {
+		t.Error("Oldest entry should have been evicted")
+	}
+}